@@ -0,0 +1,42 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func RetrieveAuditLogs(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt model.RetrieveAuditLogOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.AuditSvc.RetrieveAuditLogs(opID, opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+// reqMeta collects the request metadata recorded along audited operations.
+func reqMeta(ctx iris.Context) model.ReqMeta {
+	return model.ReqMeta{
+		IP:        ctx.RemoteAddr(),
+		UserAgent: ctx.GetHeader("User-Agent"),
+	}
+}
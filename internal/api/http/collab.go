@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+// Idle streams are pinged so that proxies do not close them.
+const pageEventPingInterval = 25 * time.Second
+
+// StreamPageEvents streams the events of a page as server-sent events
+// until the client disconnects.
+func StreamPageEvents(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		pageID := ctx.Params().Get("page_id")
+		if pageID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 page_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.CollabSvc.JoinPage(opID, pageID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		sub := *res.Data
+		defer sub.Leave()
+
+		ctx.ContentType("text/event-stream")
+		ctx.Header("Cache-Control", "no-cache")
+		// Keep reverse proxies such as nginx from buffering the stream.
+		ctx.Header("X-Accel-Buffering", "no")
+		ctx.StatusCode(iris.StatusOK)
+
+		w := ctx.ResponseWriter()
+		w.Flush()
+
+		ping := time.NewTicker(pageEventPingInterval)
+		defer ping.Stop()
+
+		done := ctx.Request().Context().Done()
+
+		for {
+			select {
+			case <-done:
+				return
+
+			case event, ok := <-sub.Events:
+				// Dropped for falling behind.
+				if !ok {
+					return
+				}
+
+				data, err := json.Marshal(event.Data)
+				if err != nil {
+					return
+				}
+
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+					return
+				}
+				w.Flush()
+
+			case <-ping.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+				w.Flush()
+			}
+		}
+	}
+}
@@ -44,7 +44,13 @@ func GetComicBriefsByWorksetID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		res, err := appState.ComicSvc.GetComicBriefsByWorksetID(worksetID, opt.Offset, opt.Limit)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicSvc.GetComicBriefsByWorksetID(opID, worksetID, opt.Offset, opt.Limit)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -63,7 +69,18 @@ func RetrieveComicBriefs(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		res, err := appState.ComicSvc.RetrieveComics(opt)
+		if opt.Sort != nil && *opt.Sort != model.COMIC_SORT_UPDATED && *opt.Sort != model.COMIC_SORT_LIKES {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicSvc.RetrieveComics(opID, opt)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -133,7 +150,13 @@ func DeleteComicByID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.ComicSvc.DeleteComicByID(comicID)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicSvc.DeleteComicByID(opID, reqMeta(ctx), comicID)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -157,7 +180,13 @@ func ExportComic(appState *state.AppState) iris.Handler {
 			return 
 		}
 
-		res, err := appState.ComicSvc.ExportComic(comicID, exportFormat)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicSvc.ExportComic(opID, comicID, exportFormat)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -200,3 +229,51 @@ func ImportComic(appState *state.AppState) iris.Handler {
 		ctx.StatusCode(iris.StatusNoContent)
 	}
 }
+
+func LikeComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicSvc.LikeComic(opID, comicID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func UnlikeComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicSvc.UnlikeComic(opID, comicID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
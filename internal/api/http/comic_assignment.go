@@ -54,6 +54,24 @@ func GetAsgnsByComicID(appState *state.AppState) iris.Handler {
 	}
 }
 
+func GetPageCoverage(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		res, err := appState.ComicAsgnSvc.GetPageCoverage(comicID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
 func GetAsgnsByUserID(appState *state.AppState) iris.Handler {
 	return func(ctx iris.Context) {
 		userID := ctx.Params().Get("user_id")
@@ -91,7 +109,13 @@ func CreateAsgn(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		res, err := appState.ComicAsgnSvc.CreateAsgn(args)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicAsgnSvc.CreateAsgn(opID, reqMeta(ctx), args)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -118,7 +142,13 @@ func UpdateAsgn(appState *state.AppState) iris.Handler {
 
 		args.ID = asgnID
 
-		err := appState.ComicAsgnSvc.UpdateAsgnByID(args)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicAsgnSvc.UpdateAsgnByID(opID, reqMeta(ctx), args)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -136,7 +166,13 @@ func DeleteAsgnByID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.ComicAsgnSvc.DeleteAsgnByID(asgnID)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicAsgnSvc.DeleteAsgnByID(opID, reqMeta(ctx), asgnID)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -145,3 +181,49 @@ func DeleteAsgnByID(appState *state.AppState) iris.Handler {
 		ctx.StatusCode(iris.StatusNoContent)
 	}
 }
+
+func RetrieveAsgns(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		opt := model.RetrieveAsgnOpt{Limit: 10}
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.ComicAsgnSvc.RetrieveAsgns(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func GetCurrUserDeadlines(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt struct {
+			Days int `url:"days"`
+		}
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicAsgnSvc.GetUpcomingDeadlines(opID, opt.Days)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
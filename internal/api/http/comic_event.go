@@ -0,0 +1,34 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func GetComicTimeline(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		var opt model.RetrieveComicEventOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.ComicEventSvc.GetComicTimeline(comicID, opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
@@ -158,7 +158,13 @@ func DeletePageByID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.ComicPageSvc.DeletePageByID(pageID)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicPageSvc.DeletePageByID(opID, reqMeta(ctx), pageID)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
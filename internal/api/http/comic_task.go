@@ -0,0 +1,125 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func RetrieveTasks(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		opt := model.RetrieveComicTaskOpt{Limit: 10}
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.ComicTaskSvc.RetrieveTasks(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateTask(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.CreateComicTaskArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicTaskSvc.CreateTask(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func ClaimTask(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		taskID := ctx.Params().Get("task_id")
+		if taskID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 task_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.ComicTaskSvc.ClaimTask(opID, reqMeta(ctx), taskID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func ReleaseTask(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		taskID := ctx.Params().Get("task_id")
+		if taskID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 task_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicTaskSvc.ReleaseTask(opID, reqMeta(ctx), taskID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteTask(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		taskID := ctx.Params().Get("task_id")
+		if taskID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 task_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicTaskSvc.DeleteTask(opID, reqMeta(ctx), taskID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
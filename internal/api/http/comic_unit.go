@@ -16,7 +16,9 @@ func GetUnitsByPageID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		res, err := appState.ComicUnitSvc.GetUnitsByPageID(pageID)
+		withTerms := ctx.URLParamBoolDefault("with_terms", false)
+
+		res, err := appState.ComicUnitSvc.GetUnitsByPageID(pageID, withTerms)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -92,9 +94,9 @@ func UpdateUnits(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.ComicUnitSvc.UpdateUnitsByIDs(opID, patchUnits)
+		res, err := appState.ComicUnitSvc.UpdateUnitsByIDs(opID, patchUnits)
 		if err != svc.NO_ERROR {
-			reject(ctx, err.Code(), err.Msg())
+			rejectWith(ctx, err.Code(), err.Msg(), res)
 			return
 		}
 
@@ -116,7 +118,13 @@ func DeleteUnits(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.ComicUnitSvc.DeleteUnitByIDs(unitIDs)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.ComicUnitSvc.DeleteUnitByIDs(opID, unitIDs)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -1,15 +1,22 @@
 package http
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
 
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/middleware/logger"
 )
 
-func Run(appState state.AppState) {
+// How long in-flight requests may take to finish on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// Run serves the API until ctx is done.
+func Run(ctx context.Context, appState state.AppState) {
 	// A default Iris application,
 	// with logger and recovery middleware already attached.
 	app := iris.Default()
@@ -20,7 +27,7 @@ func Run(appState state.AppState) {
 	routeApp(app, &appState)
 
 	// Run the application.
-	runServer(app, appState.Cfg.Host, appState.Cfg.Port)
+	runServer(ctx, app, appState.Cfg.Host, appState.Cfg.Port)
 }
 
 // Route application endpoints.
@@ -30,92 +37,235 @@ func routeApp(app *iris.Application, appState *state.AppState) {
 	// Public routes (no auth required)
 	api.Get("/check-update", CheckUpdateHandler(appState))
 	api.Post("/login", LoginUser(appState))
+	api.Post("/sessions/refresh", RefreshSession(appState))
+	// Posted by the QQ bot, which signs events instead.
+	api.Post("/onebot/events", HandleOneBotEvent(appState))
 
 	// Apply auth middleware to all routes below
+	// and state the policy of each with Require. Services may check further.
 	api.Use(AuthMiddleware(appState))
 
 	users := api.Party("/users")
 	{
-		users.Get("", RetrieveUserInfos(appState))
-		users.Get("/me", GetCurrUserInfo(appState))
-		users.Get("/{user_id:string}", GetUserInfoByID(appState))
-		users.Get("/invitations", GetInvitations(appState))
-		users.Post("/invitations", InviteUser(appState))
-		users.Patch("/{user_id:string}/roles", AssignUserRole(appState))
-		users.Patch("/{user_id:string}", UpdateUserInfo(appState))
+		users.Get("", Require(appState, ANYONE), RetrieveUserInfos(appState))
+		users.Get("/me", Require(appState, ANYONE), GetCurrUserInfo(appState))
+		users.Get("/me/deadlines", Require(appState, ANYONE), GetCurrUserDeadlines(appState))
+		users.Get("/{user_id:string}", Require(appState, ANYONE), GetUserInfoByID(appState))
+		users.Get("/invitations", Require(appState, ADMIN), GetInvitations(appState))
+		users.Post("/invitations", Require(appState, ADMIN), InviteUser(appState))
+		users.Post("/invitations/bulk", Require(appState, ADMIN), BulkInviteUsers(appState))
+		users.Post("/invitations/{inv_id:string}/regenerate", Require(appState, ADMIN), RegenerateInvitationCode(appState))
+		users.Delete("/invitations/{inv_id:string}", Require(appState, ADMIN), RevokeInvitation(appState))
+		users.Patch("/{user_id:string}/roles", Require(appState, ADMIN), AssignUserRole(appState))
+		users.Patch("/{user_id:string}", Require(appState, SELF), UpdateUserInfo(appState))
+	}
+
+	sessions := api.Party("/sessions")
+	{
+		sessions.Get("", Require(appState, ANYONE), GetCurrUserSessions(appState))
+		sessions.Delete("/{session_id:string}", Require(appState, ANYONE), RevokeSessionByID(appState))
+	}
+
+	userSessions := api.Party("/users/{user_id:string}/sessions")
+	{
+		userSessions.Get("", Require(appState, SELF), GetSessionsByUserID(appState))
+		userSessions.Delete("", Require(appState, SELF), RevokeSessionsByUserID(appState))
 	}
 
 	worksets := api.Party("/worksets")
 	{
-		worksets.Get("", RetrieveWorksets(appState))
-		worksets.Get("/{workset_id:string}", GetWorksetByID(appState))
-		worksets.Post("", CreateWorkset(appState))
-		worksets.Patch("/{workset_id:string}", UpdateWorksetByID(appState))
-		worksets.Delete("/{workset_id:string}", DeleteWorksetByID(appState))
+		worksets.Get("", Require(appState, ANYONE), RetrieveWorksets(appState))
+		worksets.Get("/{workset_id:string}", Require(appState, ANYONE), GetWorksetByID(appState))
+		worksets.Post("", Require(appState, ADMIN), CreateWorkset(appState))
+		worksets.Patch("/{workset_id:string}", Require(appState, ADMIN), UpdateWorksetByID(appState))
+		worksets.Delete("/{workset_id:string}", Require(appState, ADMIN), DeleteWorksetByID(appState))
 	}
 
 	comics := api.Party("/comics")
 	{
-		comics.Get("", RetrieveComicBriefs(appState))
-		comics.Get("/{comic_id:string}", GetComicInfoByID(appState))
-		comics.Get("/{comic_id:string}/export", ExportComic(appState))
-		comics.Get("/{comic_id:string}/cover", GetCoverByComicID(appState))
-		comics.Get("/{comic_id:string}/pages", GetPagesByComicID(appState))
-		comics.Post("/{comic_id:string}/import", ImportComic(appState))
-		comics.Post("", CreateComic(appState))
-		comics.Patch("/{comic_id:string}", UpdateComicByID(appState))
-		comics.Delete("/{comic_id:string}", DeleteComicByID(appState))
+		comics.Get("", Require(appState, ANYONE), RetrieveComicBriefs(appState))
+		comics.Get("/{comic_id:string}", Require(appState, ANYONE), GetComicInfoByID(appState))
+		comics.Get("/{comic_id:string}/export", Require(appState, ANYONE), ExportComic(appState))
+		comics.Get("/{comic_id:string}/cover", Require(appState, ANYONE), GetCoverByComicID(appState))
+		comics.Get("/{comic_id:string}/pages", Require(appState, ANYONE), GetPagesByComicID(appState))
+		comics.Post("/{comic_id:string}/import", Require(appState, AssignedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER, svc.ROLE_REVIEWER)), ImportComic(appState))
+		comics.Post("", Require(appState, ADMIN), CreateComic(appState))
+		comics.Patch("/{comic_id:string}", Require(appState, AssignedAs()), UpdateComicByID(appState))
+		comics.Delete("/{comic_id:string}", Require(appState, ADMIN), DeleteComicByID(appState))
 
 		app.HandleDir(appState.ComicSvc.ExportBaseURI(), appState.Cfg.ComicExportDir)
 	}
 
+	comicLikes := api.Party("/comics/{comic_id:string}/likes")
+	{
+		comicLikes.Post("", Require(appState, ANYONE), LikeComic(appState))
+		comicLikes.Delete("", Require(appState, ANYONE), UnlikeComic(appState))
+	}
+
+	// Which transitions a user may make depends on the stage, so the service checks further.
+	comicStage := api.Party("/comics/{comic_id:string}/stage")
+	{
+		comicStage.Get("", Require(appState, ANYONE), GetComicWorkflow(appState))
+		comicStage.Put("", Require(appState, AssignedAs(), COMIC_CREATOR), TransitionComicStage(appState))
+	}
+
+	comicTimeline := api.Party("/comics/{comic_id:string}/timeline")
+	{
+		comicTimeline.Get("", Require(appState, ANYONE), GetComicTimeline(appState))
+	}
+
 	worksetComics := api.Party("/worksets/{workset_id:string}/comics")
 	{
-		worksetComics.Get("", GetComicBriefsByWorksetID(appState))
+		worksetComics.Get("", Require(appState, ANYONE), GetComicBriefsByWorksetID(appState))
 	}
 
 	pages := api.Party("/pages")
 	{
-		pages.Get("/{page_id:string}", GetPageByID(appState))
-		pages.Post("", CreatePages(appState))
-		pages.Post("/recreate", RecreatePage(appState))
-		pages.Delete("/{page_id:string}", DeletePageByID(appState))
-		pages.Patch("/{page_id:string}", UpdatePageByID(appState))
+		pages.Get("/{page_id:string}", Require(appState, ANYONE), GetPageByID(appState))
+		pages.Post("", Require(appState, ANYONE), CreatePages(appState))
+		pages.Post("/recreate", Require(appState, ANYONE), RecreatePage(appState))
+		pages.Delete("/{page_id:string}", Require(appState, AssignedAs()), DeletePageByID(appState))
+		pages.Patch("/{page_id:string}", Require(appState, AssignedAs()), UpdatePageByID(appState))
 	}
 
 	units := api.Party("/pages/{page_id:string}/units")
 	{
-		units.Get("", GetUnitsByPageID(appState))
-		units.Post("", CreateUnits(appState))
-		units.Patch("", UpdateUnits(appState))
-		units.Delete("", DeleteUnits(appState))
+		units.Get("", Require(appState, ANYONE), GetUnitsByPageID(appState))
+		units.Get("/events", Require(appState, ANYONE), StreamPageEvents(appState))
+		units.Post("", Require(appState, AssignedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER, svc.ROLE_REVIEWER)), CreateUnits(appState))
+		units.Patch("", Require(appState, AssignedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER, svc.ROLE_REVIEWER)), UpdateUnits(appState))
+		units.Delete("", Require(appState, AssignedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER, svc.ROLE_REVIEWER)), DeleteUnits(appState))
 	}
 
 	asgns := api.Party("/assignments")
 	{
-		asgns.Get("/{asgn_id:string}", GetAsgnByID(appState))
-		asgns.Post("", CreateAsgn(appState))
-		asgns.Delete("/{asgn_id:string}", DeleteAsgnByID(appState))
-		asgns.Patch("/{asgn_id:string}", UpdateAsgn(appState))
+		asgns.Get("", Require(appState, ANYONE), RetrieveAsgns(appState))
+		asgns.Get("/{asgn_id:string}", Require(appState, ANYONE), GetAsgnByID(appState))
+		asgns.Post("", Require(appState, ADMIN), CreateAsgn(appState))
+		asgns.Delete("/{asgn_id:string}", Require(appState, ADMIN), DeleteAsgnByID(appState))
+		asgns.Patch("/{asgn_id:string}", Require(appState, ADMIN), UpdateAsgn(appState))
 	}
 
 	comicAsgns := api.Party("/comics/{comic_id:string}/assignments")
 	{
-		comicAsgns.Get("", GetAsgnsByComicID(appState))
+		comicAsgns.Get("", Require(appState, ANYONE), GetAsgnsByComicID(appState))
+		comicAsgns.Get("/coverage", Require(appState, ANYONE), GetPageCoverage(appState))
 	}
 
 	userAsgns := api.Party("/users/{user_id:string}/assignments")
 	{
-		userAsgns.Get("", GetAsgnsByUserID(appState))
+		userAsgns.Get("", Require(appState, ANYONE), GetAsgnsByUserID(appState))
+	}
+
+	tasks := api.Party("/tasks")
+	{
+		tasks.Get("", Require(appState, ANYONE), RetrieveTasks(appState))
+		tasks.Post("", Require(appState, ADMIN), CreateTask(appState))
+		tasks.Post("/{task_id:string}/claim", Require(appState, ANYONE), ClaimTask(appState))
+		tasks.Post("/{task_id:string}/release", Require(appState, ANYONE), ReleaseTask(appState))
+		tasks.Delete("/{task_id:string}", Require(appState, ADMIN), DeleteTask(appState))
+	}
+
+	// Notifications are always those of the operator.
+	notifications := api.Party("/notifications")
+	{
+		notifications.Get("", Require(appState, ANYONE), RetrieveNotifications(appState))
+		notifications.Post("/read-all", Require(appState, ANYONE), MarkAllNotificationsRead(appState))
+		notifications.Post("/{notification_id:string}/read", Require(appState, ANYONE), MarkNotificationRead(appState))
+	}
+
+	teams := api.Party("/teams")
+	{
+		teams.Get("", Require(appState, ANYONE), RetrieveTeams(appState))
+		teams.Get("/{team_id:string}", Require(appState, ANYONE), GetTeamByID(appState))
+		teams.Post("", Require(appState, ADMIN), CreateTeam(appState))
+		teams.Patch("/{team_id:string}", Require(appState, ADMIN), UpdateTeamByID(appState))
+		teams.Delete("/{team_id:string}", Require(appState, ADMIN), DeleteTeamByID(appState))
+		teams.Post("/{team_id:string}/apply", Require(appState, ADMIN), ApplyTeamToComic(appState))
+	}
+
+	// Webhooks carry secrets and post every change, so only admins manage them.
+	webhooks := api.Party("/webhooks")
+	{
+		webhooks.Get("", Require(appState, ADMIN), RetrieveWebhooks(appState))
+		webhooks.Get("/{webhook_id:string}", Require(appState, ADMIN), GetWebhookByID(appState))
+		webhooks.Get("/{webhook_id:string}/deliveries", Require(appState, ADMIN), RetrieveWebhookDeliveries(appState))
+		webhooks.Post("", Require(appState, ADMIN), CreateWebhook(appState))
+		webhooks.Patch("/{webhook_id:string}", Require(appState, ADMIN), UpdateWebhookByID(appState))
+		webhooks.Delete("/{webhook_id:string}", Require(appState, ADMIN), DeleteWebhookByID(appState))
+	}
+
+	termbases := api.Party("/termbases")
+	{
+		termbases.Get("", Require(appState, ANYONE), RetrieveTermbases(appState))
+		termbases.Get("/{termbase_id:string}", Require(appState, ANYONE), GetTermbaseByID(appState))
+		termbases.Get("/{termbase_id:string}/export", Require(appState, ANYONE), ExportTerms(appState))
+		termbases.Post("/{termbase_id:string}/import", Require(appState, TERMBASE_CREATOR), ImportTerms(appState))
+		termbases.Post("", Require(appState, ADMIN), CreateTermbase(appState))
+		termbases.Patch("/{termbase_id:string}", Require(appState, TERMBASE_CREATOR), UpdateTermbaseByID(appState))
+		termbases.Delete("/{termbase_id:string}", Require(appState, TERMBASE_CREATOR), DeleteTermbaseByID(appState))
+	}
+
+	terms := api.Party("/termbases/{termbase_id:string}/terms")
+	{
+		terms.Get("", Require(appState, ANYONE), RetrieveTerms(appState))
+		terms.Post("", Require(appState, QualifiedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER)), CreateTerm(appState))
+		terms.Patch("/{term_id:string}", Require(appState, QualifiedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER)), UpdateTermByID(appState))
+		terms.Delete("/{term_id:string}", Require(appState, QualifiedAs(svc.ROLE_TRANSLATOR, svc.ROLE_PROOFREADER)), DeleteTermByID(appState))
+	}
+
+	tags := api.Party("/tags")
+	{
+		tags.Get("", Require(appState, ANYONE), RetrieveTags(appState))
+		tags.Get("/{tag_id:string}", Require(appState, ANYONE), GetTagByID(appState))
+		tags.Post("", Require(appState, ADMIN), CreateTag(appState))
+		tags.Patch("/{tag_id:string}", Require(appState, ADMIN), UpdateTagByID(appState))
+		tags.Delete("/{tag_id:string}", Require(appState, ADMIN), DeleteTagByID(appState))
+	}
+
+	comicTags := api.Party("/comics/{comic_id:string}/tags")
+	{
+		comicTags.Post("", Require(appState, COMIC_CREATOR), AttachTagsToComic(appState))
+		comicTags.Delete("/{tag_id:string}", Require(appState, COMIC_CREATOR), DetachTagFromComic(appState))
+	}
+
+	userTags := api.Party("/users/{user_id:string}/tags")
+	{
+		userTags.Post("", Require(appState, SELF), AttachTagsToUser(appState))
+		userTags.Delete("/{tag_id:string}", Require(appState, SELF), DetachTagFromUser(appState))
+	}
+
+	comicTermbases := api.Party("/comics/{comic_id:string}/termbases")
+	{
+		comicTermbases.Get("", Require(appState, ANYONE), GetTermbasesByComicID(appState))
+		comicTermbases.Post("", Require(appState, ADMIN), LinkTermbaseToComic(appState))
+		comicTermbases.Delete("/{termbase_id:string}", Require(appState, ADMIN), UnlinkTermbaseFromComic(appState))
+	}
+
+	audit := api.Party("/audit")
+	{
+		audit.Get("", Require(appState, ADMIN), RetrieveAuditLogs(appState))
 	}
 }
 
 func runServer(
+	ctx context.Context,
 	app *iris.Application,
 	host string,
 	port uint16,
 ) {
 	addr := host + ":" + strconv.Itoa(int(port))
 
-	app.Listen(addr)
+	go func() {
+		<-ctx.Done()
+
+		// Streams of page events hold their connections till the timeout.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		app.Shutdown(shutdownCtx)
+	}()
+
+	// Shutdown is driven by ctx instead.
+	app.Listen(addr, iris.WithoutInterruptHandler)
 }
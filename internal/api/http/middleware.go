@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
 
 	"github.com/kataras/iris/v12"
 )
@@ -34,8 +35,15 @@ func AuthMiddleware(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		// Set user_id in context for downstream handlers
+		// Reject tokens whose session has been revoked or has expired
+		if err := appState.SessionSvc.CheckSession(claims.UserID, claims.SessionID); err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		// Set user_id and session_id in context for downstream handlers
 		ctx.Values().Set("user_id", claims.UserID)
+		ctx.Values().Set("session_id", claims.SessionID)
 
 		// Continue to next handler
 		ctx.Next()
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func RetrieveNotifications(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt model.RetrieveNotificationOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.NotificationSvc.RetrieveNotifications(opID, opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func MarkNotificationRead(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		notificationID := ctx.Params().Get("notification_id")
+		if notificationID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 notification_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.NotificationSvc.MarkNotificationRead(opID, notificationID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func MarkAllNotificationsRead(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.NotificationSvc.MarkAllNotificationsRead(opID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+// HandleOneBotEvent answers an event posted by the QQ bot
+// with the quick operation itself, as OneBot expects, rather than HTTPRslt.
+func HandleOneBotEvent(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		body, err := ctx.GetBody()
+		if err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		res, svcErr := appState.OneBotSvc.HandleEvent(reqMeta(ctx), body, ctx.GetHeader("X-Signature"))
+		if svcErr != svc.NO_ERROR {
+			reject(ctx, svcErr.Code(), svcErr.Msg())
+			return
+		}
+
+		if res.Data.Reply == "" {
+			ctx.StatusCode(iris.StatusNoContent)
+			return
+		}
+
+		ctx.JSON(res.Data)
+	}
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"strings"
+
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+// Policy is a route-level authorization requirement,
+// checked by Require before the handler of the route runs.
+type Policy struct {
+	name  string
+	allow func(pc *policyCtx) (bool, svc.SvcErr)
+}
+
+func (p Policy) String() string { return p.name }
+
+// Policies that need no arguments.
+var (
+	// Any authenticated user.
+	ANYONE = Policy{
+		name:  "anyone",
+		allow: func(*policyCtx) (bool, svc.SvcErr) { return true, svc.NO_ERROR },
+	}
+
+	// Admins only.
+	// Every other policy is satisfied by admins as well.
+	ADMIN = Policy{
+		name:  "admin",
+		allow: func(*policyCtx) (bool, svc.SvcErr) { return false, svc.NO_ERROR },
+	}
+
+	// The user named by the user_id path parameter.
+	SELF = Policy{
+		name: "self",
+		allow: func(pc *policyCtx) (bool, svc.SvcErr) {
+			userID := pc.ctx.Params().Get("user_id")
+			return userID != "" && userID == pc.opID, svc.NO_ERROR
+		},
+	}
+
+	// The creator of the comic resolved from the path.
+	COMIC_CREATOR = Policy{
+		name: "comic creator",
+		allow: func(pc *policyCtx) (bool, svc.SvcErr) {
+			access, err := pc.comicAccess()
+			if err != svc.NO_ERROR {
+				return false, err
+			}
+
+			return access.IsCreator, svc.NO_ERROR
+		},
+	}
+
+	// The creator of the termbase named by the termbase_id path parameter.
+	TERMBASE_CREATOR = Policy{
+		name: "termbase creator",
+		allow: func(pc *policyCtx) (bool, svc.SvcErr) {
+			creatorID, err := pc.authz.GetTermbaseCreatorID(pc.ctx.Params().Get("termbase_id"))
+			if err != svc.NO_ERROR {
+				return false, err
+			}
+
+			return creatorID == pc.opID, svc.NO_ERROR
+		},
+	}
+)
+
+// QualifiedAs is satisfied by users qualified for any of roles,
+// whichever comics they are assigned to.
+func QualifiedAs(roles ...string) Policy {
+	return Policy{
+		name: "qualified as " + strings.Join(roles, "/"),
+		allow: func(pc *policyCtx) (bool, svc.SvcErr) {
+			quals, err := pc.authz.GetQualifications(pc.opID)
+			if err != svc.NO_ERROR {
+				return false, err
+			}
+
+			for _, r := range roles {
+				if quals[r] {
+					return true, svc.NO_ERROR
+				}
+			}
+
+			return false, svc.NO_ERROR
+		},
+	}
+}
+
+// AssignedAs is satisfied by users assigned to the comic resolved from the path
+// as any of roles, or as anything if no roles are given.
+// The comic is resolved from the comic_id, page_id or asgn_id path parameter, in this order.
+func AssignedAs(roles ...string) Policy {
+	name := "assigned"
+	if len(roles) > 0 {
+		name += " as " + strings.Join(roles, "/")
+	}
+
+	return Policy{
+		name: name,
+		allow: func(pc *policyCtx) (bool, svc.SvcErr) {
+			access, err := pc.comicAccess()
+			if err != svc.NO_ERROR {
+				return false, err
+			}
+
+			for role, assigned := range access.Roles {
+				if !assigned {
+					continue
+				}
+				if len(roles) == 0 {
+					return true, svc.NO_ERROR
+				}
+				for _, r := range roles {
+					if r == role {
+						return true, svc.NO_ERROR
+					}
+				}
+			}
+
+			return false, svc.NO_ERROR
+		},
+	}
+}
+
+// Require builds a middleware that lets the request through
+// if the operator is an admin or satisfies any of the policies.
+// It must run after AuthMiddleware.
+func Require(appState *state.AppState, policies ...Policy) iris.Handler {
+	if len(policies) == 0 {
+		panic("Require needs at least one policy")
+	}
+
+	return func(ctx iris.Context) {
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		pc := &policyCtx{ctx: ctx, authz: appState.AuthzSvc, opID: opID}
+
+		for _, p := range policies {
+			ok, err := p.allow(pc)
+			if err != svc.NO_ERROR {
+				reject(ctx, err.Code(), err.Msg())
+				return
+			}
+			if ok {
+				ctx.Next()
+				return
+			}
+		}
+
+		isAdmin, err := appState.AuthzSvc.IsAdmin(opID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+		if isAdmin {
+			ctx.Next()
+			return
+		}
+
+		denied := svc.PERMISSION_DENIED
+		reject(ctx, denied.Code(), denied.Msg())
+	}
+}
+
+// policyCtx caches what policies resolve during one request,
+// so that combined policies do not hit the database twice.
+type policyCtx struct {
+	ctx   iris.Context
+	authz svc.AuthzSvc
+	opID  string
+
+	access *svc.ComicAccess
+}
+
+// comicAccess resolves the comic from the path and the relation of the operator to it.
+func (pc *policyCtx) comicAccess() (svc.ComicAccess, svc.SvcErr) {
+	if pc.access != nil {
+		return *pc.access, svc.NO_ERROR
+	}
+
+	params := pc.ctx.Params()
+
+	comicID := params.Get("comic_id")
+	if comicID == "" {
+		var err svc.SvcErr
+
+		switch {
+		case params.Get("page_id") != "":
+			comicID, err = pc.authz.GetComicIDByPageID(params.Get("page_id"))
+		case params.Get("asgn_id") != "":
+			comicID, err = pc.authz.GetComicIDByAsgnID(params.Get("asgn_id"))
+		default:
+			// A route misconfiguration, not a client error.
+			return svc.ComicAccess{}, svc.PERMISSION_DENIED
+		}
+
+		if err != svc.NO_ERROR {
+			return svc.ComicAccess{}, err
+		}
+	}
+
+	access, err := pc.authz.GetComicAccess(pc.opID, comicID)
+	if err != svc.NO_ERROR {
+		return svc.ComicAccess{}, err
+	}
+
+	pc.access = &access
+
+	return access, svc.NO_ERROR
+}
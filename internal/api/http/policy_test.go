@@ -0,0 +1,324 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"poprako-main-server/internal/config"
+	"poprako-main-server/internal/jwtcodec"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/middleware/recover"
+)
+
+// Users of the fixture.
+const (
+	tADMIN       = "u-admin"
+	tSELF        = "u-self"
+	tSTRANGER    = "u-stranger"
+	tCREATOR     = "u-creator"
+	tTRANSLATOR  = "u-translator"
+	tPROOFREADER = "u-proofreader"
+	tTYPESETTER  = "u-typesetter"
+	tREVIEWER    = "u-reviewer"
+)
+
+// Resources of the fixture, all belonging to the same comic.
+const (
+	tCOMIC = "c-1"
+	tPAGE  = "p-1"
+	tASGN  = "a-1"
+)
+
+// Termbase of the fixture, created by tCREATOR.
+const tTERMBASE = "tb-1"
+
+type fakeAuthzSvc struct{}
+
+func (fakeAuthzSvc) IsAdmin(userID string) (bool, svc.SvcErr) {
+	return userID == tADMIN, svc.NO_ERROR
+}
+
+// Users of the fixture are qualified for the roles they are assigned to the comic as.
+func (fakeAuthzSvc) GetQualifications(userID string) (map[string]bool, svc.SvcErr) {
+	access, err := fakeAuthzSvc{}.GetComicAccess(userID, tCOMIC)
+	return access.Roles, err
+}
+
+func (fakeAuthzSvc) GetTermbaseCreatorID(termbaseID string) (string, svc.SvcErr) {
+	if termbaseID != tTERMBASE {
+		return "", svc.NOT_FOUND
+	}
+	return tCREATOR, svc.NO_ERROR
+}
+
+func (fakeAuthzSvc) GetComicAccess(userID string, comicID string) (svc.ComicAccess, svc.SvcErr) {
+	if comicID != tCOMIC {
+		return svc.ComicAccess{}, svc.NOT_FOUND
+	}
+
+	roles := map[string]string{
+		tTRANSLATOR:  svc.ROLE_TRANSLATOR,
+		tPROOFREADER: svc.ROLE_PROOFREADER,
+		tTYPESETTER:  svc.ROLE_TYPESETTER,
+		tREVIEWER:    svc.ROLE_REVIEWER,
+	}
+
+	access := svc.ComicAccess{IsCreator: userID == tCREATOR, Roles: map[string]bool{}}
+	if role, ok := roles[userID]; ok {
+		access.Roles[role] = true
+	}
+
+	return access, svc.NO_ERROR
+}
+
+func (fakeAuthzSvc) GetComicIDByPageID(pageID string) (string, svc.SvcErr) {
+	if pageID != tPAGE {
+		return "", svc.NOT_FOUND
+	}
+	return tCOMIC, svc.NO_ERROR
+}
+
+func (fakeAuthzSvc) GetComicIDByAsgnID(asgnID string) (string, svc.SvcErr) {
+	if asgnID != tASGN {
+		return "", svc.NOT_FOUND
+	}
+	return tCOMIC, svc.NO_ERROR
+}
+
+// fakeSessionSvc accepts every session.
+type fakeSessionSvc struct{ svc.SessionSvc }
+
+func (fakeSessionSvc) CheckSession(string, string) svc.SvcErr { return svc.NO_ERROR }
+
+// fakeComicSvc only serves what routeApp needs to register routes.
+type fakeComicSvc struct{ svc.ComicSvc }
+
+func (fakeComicSvc) ExportBaseURI() string { return "/exports" }
+
+// Expected outcomes of each policy.
+// Users outside allow and deny are not checked.
+type expect struct {
+	public bool
+	allow  []string
+	deny   []string
+}
+
+var (
+	ePUBLIC        = expect{public: true}
+	eANYONE        = expect{allow: []string{tSTRANGER, tADMIN}}
+	eADMIN         = expect{allow: []string{tADMIN}, deny: []string{tSTRANGER, tCREATOR, tTRANSLATOR, tREVIEWER}}
+	eSELF          = expect{allow: []string{tSELF, tADMIN}, deny: []string{tSTRANGER}}
+	eASSIGNED      = expect{allow: []string{tTRANSLATOR, tTYPESETTER, tADMIN}, deny: []string{tSTRANGER, tCREATOR}}
+	eEDITOR        = expect{allow: []string{tTRANSLATOR, tPROOFREADER, tREVIEWER, tADMIN}, deny: []string{tTYPESETTER, tSTRANGER, tCREATOR}}
+	eCOMIC_CREATOR = expect{allow: []string{tCREATOR, tADMIN}, deny: []string{tTRANSLATOR, tSTRANGER}}
+	eCOMIC_MEMBER  = expect{allow: []string{tCREATOR, tTRANSLATOR, tTYPESETTER, tADMIN}, deny: []string{tSTRANGER}}
+
+	eTERMBASE_CREATOR = expect{allow: []string{tCREATOR, tADMIN}, deny: []string{tTRANSLATOR, tSTRANGER}}
+	eTERM_EDITOR      = expect{allow: []string{tTRANSLATOR, tPROOFREADER, tADMIN}, deny: []string{tTYPESETTER, tREVIEWER, tSTRANGER, tCREATOR}}
+)
+
+func TestRoutePolicies(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "policy-test-secret")
+
+	appState := &state.AppState{
+		Cfg:        config.AppCfg{ComicExportDir: t.TempDir()},
+		JWTCodec:   jwtcodec.NewJWTCodec(60),
+		ComicSvc:   fakeComicSvc{},
+		SessionSvc: fakeSessionSvc{},
+		AuthzSvc:   fakeAuthzSvc{},
+	}
+
+	app := iris.New()
+	app.Logger().SetLevel("disable")
+	// Requests let through reach handlers whose services are absent.
+	app.UseRouter(recover.New())
+
+	routeApp(app, appState)
+
+	if err := app.Build(); err != nil {
+		t.Fatalf("Failed to build app: %v", err)
+	}
+
+	cases := []struct {
+		method string
+		route  string
+		url    string
+		expect expect
+	}{
+		{"GET", "/api/v1/check-update", "/api/v1/check-update", ePUBLIC},
+		{"POST", "/api/v1/login", "/api/v1/login", ePUBLIC},
+		{"POST", "/api/v1/sessions/refresh", "/api/v1/sessions/refresh", ePUBLIC},
+		{"POST", "/api/v1/onebot/events", "/api/v1/onebot/events", ePUBLIC},
+
+		{"GET", "/api/v1/users", "/api/v1/users", eANYONE},
+		{"GET", "/api/v1/users/me", "/api/v1/users/me", eANYONE},
+		{"GET", "/api/v1/users/me/deadlines", "/api/v1/users/me/deadlines", eANYONE},
+		{"GET", "/api/v1/users/:user_id", "/api/v1/users/" + tSELF, eANYONE},
+		{"GET", "/api/v1/users/invitations", "/api/v1/users/invitations", eADMIN},
+		{"POST", "/api/v1/users/invitations", "/api/v1/users/invitations", eADMIN},
+		{"POST", "/api/v1/users/invitations/bulk", "/api/v1/users/invitations/bulk", eADMIN},
+		{"POST", "/api/v1/users/invitations/:inv_id/regenerate", "/api/v1/users/invitations/i-1/regenerate", eADMIN},
+		{"DELETE", "/api/v1/users/invitations/:inv_id", "/api/v1/users/invitations/i-1", eADMIN},
+		{"PATCH", "/api/v1/users/:user_id/roles", "/api/v1/users/" + tSELF + "/roles", eADMIN},
+		{"PATCH", "/api/v1/users/:user_id", "/api/v1/users/" + tSELF, eSELF},
+
+		{"GET", "/api/v1/sessions", "/api/v1/sessions", eANYONE},
+		{"DELETE", "/api/v1/sessions/:session_id", "/api/v1/sessions/s-1", eANYONE},
+		{"GET", "/api/v1/users/:user_id/sessions", "/api/v1/users/" + tSELF + "/sessions", eSELF},
+		{"DELETE", "/api/v1/users/:user_id/sessions", "/api/v1/users/" + tSELF + "/sessions", eSELF},
+
+		{"GET", "/api/v1/worksets", "/api/v1/worksets", eANYONE},
+		{"GET", "/api/v1/worksets/:workset_id", "/api/v1/worksets/w-1", eANYONE},
+		{"POST", "/api/v1/worksets", "/api/v1/worksets", eADMIN},
+		{"PATCH", "/api/v1/worksets/:workset_id", "/api/v1/worksets/w-1", eADMIN},
+		{"DELETE", "/api/v1/worksets/:workset_id", "/api/v1/worksets/w-1", eADMIN},
+		{"GET", "/api/v1/worksets/:workset_id/comics", "/api/v1/worksets/w-1/comics", eANYONE},
+
+		{"GET", "/api/v1/comics", "/api/v1/comics", eANYONE},
+		{"GET", "/api/v1/comics/:comic_id", "/api/v1/comics/" + tCOMIC, eANYONE},
+		{"GET", "/api/v1/comics/:comic_id/export", "/api/v1/comics/" + tCOMIC + "/export", eANYONE},
+		{"GET", "/api/v1/comics/:comic_id/cover", "/api/v1/comics/" + tCOMIC + "/cover", eANYONE},
+		{"GET", "/api/v1/comics/:comic_id/pages", "/api/v1/comics/" + tCOMIC + "/pages", eANYONE},
+		{"POST", "/api/v1/comics/:comic_id/import", "/api/v1/comics/" + tCOMIC + "/import", eEDITOR},
+		{"POST", "/api/v1/comics", "/api/v1/comics", eADMIN},
+		{"PATCH", "/api/v1/comics/:comic_id", "/api/v1/comics/" + tCOMIC, eASSIGNED},
+		{"DELETE", "/api/v1/comics/:comic_id", "/api/v1/comics/" + tCOMIC, eADMIN},
+		{"POST", "/api/v1/comics/:comic_id/likes", "/api/v1/comics/" + tCOMIC + "/likes", eANYONE},
+		{"DELETE", "/api/v1/comics/:comic_id/likes", "/api/v1/comics/" + tCOMIC + "/likes", eANYONE},
+		{"GET", "/api/v1/comics/:comic_id/stage", "/api/v1/comics/" + tCOMIC + "/stage", eANYONE},
+		{"PUT", "/api/v1/comics/:comic_id/stage", "/api/v1/comics/" + tCOMIC + "/stage", eCOMIC_MEMBER},
+		{"GET", "/api/v1/comics/:comic_id/timeline", "/api/v1/comics/" + tCOMIC + "/timeline", eANYONE},
+
+		{"GET", "/api/v1/pages/:page_id", "/api/v1/pages/" + tPAGE, eANYONE},
+		{"POST", "/api/v1/pages", "/api/v1/pages", eANYONE},
+		{"POST", "/api/v1/pages/recreate", "/api/v1/pages/recreate", eANYONE},
+		{"DELETE", "/api/v1/pages/:page_id", "/api/v1/pages/" + tPAGE, eASSIGNED},
+		{"PATCH", "/api/v1/pages/:page_id", "/api/v1/pages/" + tPAGE, eASSIGNED},
+
+		{"GET", "/api/v1/pages/:page_id/units", "/api/v1/pages/" + tPAGE + "/units", eANYONE},
+		{"GET", "/api/v1/pages/:page_id/units/events", "/api/v1/pages/" + tPAGE + "/units/events", eANYONE},
+		{"POST", "/api/v1/pages/:page_id/units", "/api/v1/pages/" + tPAGE + "/units", eEDITOR},
+		{"PATCH", "/api/v1/pages/:page_id/units", "/api/v1/pages/" + tPAGE + "/units", eEDITOR},
+		{"DELETE", "/api/v1/pages/:page_id/units", "/api/v1/pages/" + tPAGE + "/units", eEDITOR},
+
+		{"GET", "/api/v1/assignments", "/api/v1/assignments?overdue=1", eANYONE},
+		{"GET", "/api/v1/assignments/:asgn_id", "/api/v1/assignments/" + tASGN, eANYONE},
+		{"POST", "/api/v1/assignments", "/api/v1/assignments", eADMIN},
+		{"DELETE", "/api/v1/assignments/:asgn_id", "/api/v1/assignments/" + tASGN, eADMIN},
+		{"PATCH", "/api/v1/assignments/:asgn_id", "/api/v1/assignments/" + tASGN, eADMIN},
+		{"GET", "/api/v1/comics/:comic_id/assignments", "/api/v1/comics/" + tCOMIC + "/assignments", eANYONE},
+		{"GET", "/api/v1/comics/:comic_id/assignments/coverage", "/api/v1/comics/" + tCOMIC + "/assignments/coverage", eANYONE},
+		{"GET", "/api/v1/users/:user_id/assignments", "/api/v1/users/" + tSELF + "/assignments", eANYONE},
+
+		{"GET", "/api/v1/tasks", "/api/v1/tasks?claimed=false", eANYONE},
+		{"POST", "/api/v1/tasks", "/api/v1/tasks", eADMIN},
+		{"POST", "/api/v1/tasks/:task_id/claim", "/api/v1/tasks/task-1/claim", eANYONE},
+		{"POST", "/api/v1/tasks/:task_id/release", "/api/v1/tasks/task-1/release", eANYONE},
+		{"DELETE", "/api/v1/tasks/:task_id", "/api/v1/tasks/task-1", eADMIN},
+
+		{"GET", "/api/v1/notifications", "/api/v1/notifications?unread=true", eANYONE},
+		{"POST", "/api/v1/notifications/read-all", "/api/v1/notifications/read-all", eANYONE},
+		{"POST", "/api/v1/notifications/:notification_id/read", "/api/v1/notifications/n-1/read", eANYONE},
+
+		{"GET", "/api/v1/teams", "/api/v1/teams", eANYONE},
+		{"GET", "/api/v1/teams/:team_id", "/api/v1/teams/team-1", eANYONE},
+		{"POST", "/api/v1/teams", "/api/v1/teams", eADMIN},
+		{"PATCH", "/api/v1/teams/:team_id", "/api/v1/teams/team-1", eADMIN},
+		{"DELETE", "/api/v1/teams/:team_id", "/api/v1/teams/team-1", eADMIN},
+		{"POST", "/api/v1/teams/:team_id/apply", "/api/v1/teams/team-1/apply", eADMIN},
+
+		{"GET", "/api/v1/webhooks", "/api/v1/webhooks", eADMIN},
+		{"GET", "/api/v1/webhooks/:webhook_id", "/api/v1/webhooks/hook-1", eADMIN},
+		{"GET", "/api/v1/webhooks/:webhook_id/deliveries", "/api/v1/webhooks/hook-1/deliveries?status=failed", eADMIN},
+		{"POST", "/api/v1/webhooks", "/api/v1/webhooks", eADMIN},
+		{"PATCH", "/api/v1/webhooks/:webhook_id", "/api/v1/webhooks/hook-1", eADMIN},
+		{"DELETE", "/api/v1/webhooks/:webhook_id", "/api/v1/webhooks/hook-1", eADMIN},
+
+		{"GET", "/api/v1/termbases", "/api/v1/termbases", eANYONE},
+		{"GET", "/api/v1/termbases/:termbase_id", "/api/v1/termbases/" + tTERMBASE, eANYONE},
+		{"GET", "/api/v1/termbases/:termbase_id/export", "/api/v1/termbases/" + tTERMBASE + "/export", eANYONE},
+		{"POST", "/api/v1/termbases/:termbase_id/import", "/api/v1/termbases/" + tTERMBASE + "/import", eTERMBASE_CREATOR},
+		{"POST", "/api/v1/termbases", "/api/v1/termbases", eADMIN},
+		{"PATCH", "/api/v1/termbases/:termbase_id", "/api/v1/termbases/" + tTERMBASE, eTERMBASE_CREATOR},
+		{"DELETE", "/api/v1/termbases/:termbase_id", "/api/v1/termbases/" + tTERMBASE, eTERMBASE_CREATOR},
+		{"GET", "/api/v1/termbases/:termbase_id/terms", "/api/v1/termbases/" + tTERMBASE + "/terms", eANYONE},
+		{"POST", "/api/v1/termbases/:termbase_id/terms", "/api/v1/termbases/" + tTERMBASE + "/terms", eTERM_EDITOR},
+		{"PATCH", "/api/v1/termbases/:termbase_id/terms/:term_id", "/api/v1/termbases/" + tTERMBASE + "/terms/t-1", eTERM_EDITOR},
+		{"DELETE", "/api/v1/termbases/:termbase_id/terms/:term_id", "/api/v1/termbases/" + tTERMBASE + "/terms/t-1", eTERM_EDITOR},
+
+		{"GET", "/api/v1/tags", "/api/v1/tags", eANYONE},
+		{"GET", "/api/v1/tags/:tag_id", "/api/v1/tags/g-1", eANYONE},
+		{"POST", "/api/v1/tags", "/api/v1/tags", eADMIN},
+		{"PATCH", "/api/v1/tags/:tag_id", "/api/v1/tags/g-1", eADMIN},
+		{"DELETE", "/api/v1/tags/:tag_id", "/api/v1/tags/g-1", eADMIN},
+		{"POST", "/api/v1/comics/:comic_id/tags", "/api/v1/comics/" + tCOMIC + "/tags", eCOMIC_CREATOR},
+		{"DELETE", "/api/v1/comics/:comic_id/tags/:tag_id", "/api/v1/comics/" + tCOMIC + "/tags/g-1", eCOMIC_CREATOR},
+		{"POST", "/api/v1/users/:user_id/tags", "/api/v1/users/" + tSELF + "/tags", eSELF},
+		{"DELETE", "/api/v1/users/:user_id/tags/:tag_id", "/api/v1/users/" + tSELF + "/tags/g-1", eSELF},
+
+		{"GET", "/api/v1/comics/:comic_id/termbases", "/api/v1/comics/" + tCOMIC + "/termbases", eANYONE},
+		{"POST", "/api/v1/comics/:comic_id/termbases", "/api/v1/comics/" + tCOMIC + "/termbases", eADMIN},
+		{"DELETE", "/api/v1/comics/:comic_id/termbases/:termbase_id", "/api/v1/comics/" + tCOMIC + "/termbases/" + tTERMBASE, eADMIN},
+
+		{"GET", "/api/v1/audit", "/api/v1/audit", eADMIN},
+	}
+
+	// Every API route must be covered, so that new routes cannot skip a policy unnoticed.
+	covered := make(map[string]bool, len(cases))
+	for _, c := range cases {
+		covered[c.method+" "+c.route] = true
+	}
+	for _, r := range app.GetRoutes() {
+		key := r.Method + " " + r.Path
+		if len(r.Path) >= len("/api/v1") && r.Path[:len("/api/v1")] == "/api/v1" && !covered[key] {
+			t.Errorf("route %s has no policy test case", key)
+		}
+	}
+
+	do := func(method, url, userID string) int {
+		req := httptest.NewRequest(method, url, nil)
+		if userID != "" {
+			token, err := appState.JWTCodec.Encode(userID, "s-"+userID)
+			if err != nil {
+				t.Fatalf("Failed to encode token: %v", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	for _, c := range cases {
+		t.Run(c.method+" "+c.route, func(t *testing.T) {
+			if c.expect.public {
+				if code := do(c.method, c.url, ""); code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusNotFound {
+					t.Errorf("anonymous request got %d, want it let through", code)
+				}
+				return
+			}
+
+			if code := do(c.method, c.url, ""); code != http.StatusUnauthorized {
+				t.Errorf("anonymous request got %d, want 401", code)
+			}
+
+			for _, u := range c.expect.allow {
+				if code := do(c.method, c.url, u); code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusNotFound {
+					t.Errorf("%s got %d, want it let through", u, code)
+				}
+			}
+
+			for _, u := range c.expect.deny {
+				if code := do(c.method, c.url, u); code != http.StatusForbidden {
+					t.Errorf("%s got %d, want 403", u, code)
+				}
+			}
+		})
+	}
+}
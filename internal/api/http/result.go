@@ -8,7 +8,8 @@ import (
 
 // HTTPRslt is a unified wrapper for HTTP responses.
 // When it represents a successful response, Data is populated and Msg is empty.
-// When it represents a failed response, Msg is populated and Data is nil.
+// When it represents a failed response, Msg is populated and Data is nil,
+// unless it describes what failed.
 type HTTPRslt[T any] struct {
 	Code uint16 `json:"code"`
 	Msg  string `json:"msg,omitempty"`
@@ -25,6 +26,17 @@ func reject(ctx iris.Context, code uint16, msg string) {
 	})
 }
 
+// Responds to a failed request with data describing what failed.
+func rejectWith[T any](ctx iris.Context, code uint16, msg string, res svc.SvcRslt[T]) {
+	ctx.StatusCode(int(code))
+
+	ctx.JSON(HTTPRslt[T]{
+		Code: code,
+		Msg:  msg,
+		Data: res.Data,
+	})
+}
+
 // A quick transform function that converts
 // SvcRslt into HTTPResult and writes it to the context.
 func accept[T any](ctx iris.Context, res svc.SvcRslt[T]) {
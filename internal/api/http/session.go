@@ -0,0 +1,132 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func RefreshSession(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.RefreshSessionArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.IP = ctx.RemoteAddr()
+
+		res, err := appState.SessionSvc.RefreshSession(args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.SetCookie(&http.Cookie{
+			Name:  "Authorization",
+			Value: fmt.Sprintf("Bearer %s", res.Data.Token),
+		})
+
+		accept(ctx, res)
+	}
+}
+
+func GetCurrUserSessions(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		sessionID := ctx.Values().GetString("session_id")
+
+		res, err := appState.SessionSvc.GetSessionsByUserID(opID, sessionID, opID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func GetSessionsByUserID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		userID := ctx.Params().Get("user_id")
+		if userID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 user_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		sessionID := ctx.Values().GetString("session_id")
+
+		res, err := appState.SessionSvc.GetSessionsByUserID(opID, sessionID, userID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RevokeSessionByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		sessionID := ctx.Params().Get("session_id")
+		if sessionID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 session_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.SessionSvc.RevokeSessionByID(opID, sessionID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func RevokeSessionsByUserID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		userID := ctx.Params().Get("user_id")
+		if userID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 user_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.SessionSvc.RevokeSessionsByUserID(opID, userID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
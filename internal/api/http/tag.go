@@ -0,0 +1,250 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func GetTagByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		tagID := ctx.Params().Get("tag_id")
+		if tagID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 tag_id 路径参数")
+			return
+		}
+
+		res, err := appState.TagSvc.GetTagByID(tagID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RetrieveTags(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt model.RetrieveTagOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.TagSvc.RetrieveTags(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateTag(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.CreateTagArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.TagSvc.CreateTag(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func UpdateTagByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		tagID := ctx.Params().Get("tag_id")
+		if tagID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 tag_id 路径参数")
+			return
+		}
+
+		var args model.UpdateTagArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.ID = tagID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.UpdateTagByID(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteTagByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		tagID := ctx.Params().Get("tag_id")
+		if tagID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 tag_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.DeleteTagByID(opID, tagID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func AttachTagsToComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		var args model.AttachTagsArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.AttachTagsToComic(opID, comicID, args.TagIDs)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DetachTagFromComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		tagID := ctx.Params().Get("tag_id")
+		if tagID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 tag_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.DetachTagFromComic(opID, comicID, tagID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func AttachTagsToUser(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		userID := ctx.Params().Get("user_id")
+		if userID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 user_id 路径参数")
+			return
+		}
+
+		var args model.AttachTagsArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.AttachTagsToUser(opID, userID, args.TagIDs)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DetachTagFromUser(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		userID := ctx.Params().Get("user_id")
+		if userID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 user_id 路径参数")
+			return
+		}
+
+		tagID := ctx.Params().Get("tag_id")
+		if tagID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 tag_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TagSvc.DetachTagFromUser(opID, userID, tagID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func GetTeamByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		teamID := ctx.Params().Get("team_id")
+		if teamID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 team_id 路径参数")
+			return
+		}
+
+		res, err := appState.TeamSvc.GetTeamByID(teamID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RetrieveTeams(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		opt := model.RetrieveTeamOpt{Limit: 10}
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.TeamSvc.RetrieveTeams(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateTeam(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.CreateTeamArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.TeamSvc.CreateTeam(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func UpdateTeamByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		teamID := ctx.Params().Get("team_id")
+		if teamID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 team_id 路径参数")
+			return
+		}
+
+		var args model.UpdateTeamArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.ID = teamID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TeamSvc.UpdateTeamByID(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteTeamByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		teamID := ctx.Params().Get("team_id")
+		if teamID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 team_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TeamSvc.DeleteTeamByID(opID, reqMeta(ctx), teamID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func ApplyTeamToComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		teamID := ctx.Params().Get("team_id")
+		if teamID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 team_id 路径参数")
+			return
+		}
+
+		var args model.ApplyTeamArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.TeamID = teamID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.TeamSvc.ApplyTeamToComic(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
@@ -0,0 +1,394 @@
+package http
+
+import (
+	"net/url"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func GetTermbaseByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.GetTermbaseByID(termbaseID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RetrieveTermbases(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt model.RetrieveTermbaseOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.RetrieveTermbases(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateTermbase(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.CreateTermbaseArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.CreateTermbase(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func UpdateTermbaseByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		var args model.UpdateTermbaseArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.ID = termbaseID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.UpdateTermbaseByID(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteTermbaseByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.DeleteTermbaseByID(opID, termbaseID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func RetrieveTerms(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		var opt model.RetrieveTermOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.RetrieveTerms(termbaseID, opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateTerm(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		var args model.CreateTermArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.TermbaseID = termbaseID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.CreateTerm(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func UpdateTermByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		termID := ctx.Params().Get("term_id")
+		if termID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 term_id 路径参数")
+			return
+		}
+
+		var args model.UpdateTermArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.ID = termID
+		args.TermbaseID = termbaseID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.UpdateTermByID(opID, args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteTermByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		termID := ctx.Params().Get("term_id")
+		if termID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 term_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.DeleteTermByID(opID, termbaseID, termID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func GetTermbasesByComicID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		res, err := appState.TermbaseSvc.GetTermbasesByComicID(comicID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func LinkTermbaseToComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		var args model.LinkComicTermbaseArgs
+
+		if err := ctx.ReadJSON(&args); err != nil || args.TermbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.LinkTermbaseToComic(opID, comicID, args.TermbaseID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func UnlinkTermbaseFromComic(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		comicID := ctx.Params().Get("comic_id")
+		if comicID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 comic_id 路径参数")
+			return
+		}
+
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.TermbaseSvc.UnlinkTermbaseFromComic(opID, comicID, termbaseID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func ImportTerms(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		// Read file from form-data with field name `term_data`
+		file, fh, err := ctx.FormFile("term_data")
+		if err != nil {
+			reject(ctx, iris.StatusBadRequest, "缺少 term_data 文件")
+			return
+		}
+		defer file.Close()
+
+		dryRun := ctx.URLParamBoolDefault("dry_run", false)
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, svcErr := appState.TermbaseSvc.ImportTerms(opID, termbaseID, fh.Filename, file, dryRun)
+		if svcErr != svc.NO_ERROR {
+			rejectWith(ctx, svcErr.Code(), svcErr.Msg(), res)
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func ExportTerms(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		termbaseID := ctx.Params().Get("termbase_id")
+		if termbaseID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 termbase_id 路径参数")
+			return
+		}
+
+		format := ctx.URLParamDefault("format", "csv")
+
+		res, err := appState.TermbaseSvc.ExportTerms(termbaseID, format)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		// Sent as a download instead of the usual JSON wrapper.
+		ctx.ContentType(res.Data.ContentType)
+		ctx.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(res.Data.FileName))
+		ctx.Write(res.Data.Content)
+	}
+}
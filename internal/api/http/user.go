@@ -97,7 +97,7 @@ func InviteUser(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		res, err := appState.InvitationSvc.CreateInvitation(opID, args)
+		res, err := appState.InvitationSvc.CreateInvitation(opID, reqMeta(ctx), args)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
@@ -125,6 +125,82 @@ func GetInvitations(appState *state.AppState) iris.Handler {
 	}
 }
 
+func BulkInviteUsers(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		// Read file from form-data with field name `invitation_data`
+		file, _, err := ctx.FormFile("invitation_data")
+		if err != nil {
+			reject(ctx, iris.StatusBadRequest, "缺少 invitation_data 文件")
+			return
+		}
+		defer file.Close()
+
+		dryRun := ctx.URLParamBoolDefault("dry_run", false)
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, svcErr := appState.InvitationSvc.BulkCreateInvitations(opID, reqMeta(ctx), file, dryRun)
+		if svcErr != svc.NO_ERROR {
+			reject(ctx, svcErr.Code(), svcErr.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RegenerateInvitationCode(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		invID := ctx.Params().Get("inv_id")
+		if invID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 inv_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.InvitationSvc.RegenerateInvitationCode(opID, reqMeta(ctx), invID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RevokeInvitation(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		invID := ctx.Params().Get("inv_id")
+		if invID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 inv_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.InvitationSvc.RevokeInvitationByID(opID, reqMeta(ctx), invID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
 func LoginUser(appState *state.AppState) iris.Handler {
 	return func(ctx iris.Context) {
 		var args model.LoginArgs
@@ -134,6 +210,11 @@ func LoginUser(appState *state.AppState) iris.Handler {
 			return
 		}
 
+		if args.Device == "" {
+			args.Device = ctx.GetHeader("User-Agent")
+		}
+		args.IP = ctx.RemoteAddr()
+
 		res, err := appState.UserSvc.LoginUser(args)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
@@ -194,7 +275,7 @@ func AssignUserRole(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.UserSvc.AssignUserRole(opID, args)
+		err := appState.UserSvc.AssignUserRole(opID, reqMeta(ctx), args)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
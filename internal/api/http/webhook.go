@@ -0,0 +1,153 @@
+package http
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/state"
+	"poprako-main-server/internal/svc"
+
+	"github.com/kataras/iris/v12"
+)
+
+func GetWebhookByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		webhookID := ctx.Params().Get("webhook_id")
+		if webhookID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 webhook_id 路径参数")
+			return
+		}
+
+		res, err := appState.WebhookSvc.GetWebhookByID(webhookID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func RetrieveWebhooks(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var opt model.RetrieveWebhookOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.WebhookSvc.RetrieveWebhooks(opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func CreateWebhook(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		var args model.CreateWebhookArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		res, err := appState.WebhookSvc.CreateWebhook(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
+
+func UpdateWebhookByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		webhookID := ctx.Params().Get("webhook_id")
+		if webhookID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 webhook_id 路径参数")
+			return
+		}
+
+		var args model.UpdateWebhookArgs
+
+		if err := ctx.ReadJSON(&args); err != nil {
+			reject(ctx, iris.StatusBadRequest, "请求体格式错误")
+			return
+		}
+
+		args.ID = webhookID
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.WebhookSvc.UpdateWebhookByID(opID, reqMeta(ctx), args)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func DeleteWebhookByID(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		webhookID := ctx.Params().Get("webhook_id")
+		if webhookID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 webhook_id 路径参数")
+			return
+		}
+
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.WebhookSvc.DeleteWebhookByID(opID, reqMeta(ctx), webhookID)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		ctx.StatusCode(iris.StatusNoContent)
+	}
+}
+
+func RetrieveWebhookDeliveries(appState *state.AppState) iris.Handler {
+	return func(ctx iris.Context) {
+		webhookID := ctx.Params().Get("webhook_id")
+		if webhookID == "" {
+			reject(ctx, iris.StatusBadRequest, "缺少 webhook_id 路径参数")
+			return
+		}
+
+		var opt model.RetrieveWebhookDeliveryOpt
+
+		if err := ctx.ReadQuery(&opt); err != nil {
+			reject(ctx, iris.StatusBadRequest, "查询参数格式错误")
+			return
+		}
+
+		res, err := appState.WebhookSvc.RetrieveDeliveries(webhookID, opt)
+		if err != svc.NO_ERROR {
+			reject(ctx, err.Code(), err.Msg())
+			return
+		}
+
+		accept(ctx, res)
+	}
+}
@@ -108,7 +108,13 @@ func DeleteWorksetByID(appState *state.AppState) iris.Handler {
 			return
 		}
 
-		err := appState.WorksetSvc.DeleteWorksetByID(worksetID)
+		opID := ctx.Values().GetString("user_id")
+		if opID == "" {
+			reject(ctx, iris.StatusUnauthorized, "未认证用户")
+			return
+		}
+
+		err := appState.WorksetSvc.DeleteWorksetByID(opID, reqMeta(ctx), worksetID)
 		if err != svc.NO_ERROR {
 			reject(ctx, err.Code(), err.Msg())
 			return
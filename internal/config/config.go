@@ -17,7 +17,34 @@ type AppCfg struct {
 	Host string `mapstructure:"host"`
 	Port uint16 `mapstructure:"port"`
 
+	// Lifetime of access tokens.
 	JWTExpSecs int64 `mapstructure:"jwt_exp_secs"`
+	// Lifetime of refresh tokens, renewed on every refresh.
+	RefreshExpSecs int64 `mapstructure:"refresh_exp_secs"`
+	// Lifetime of invitation codes, restarted when regenerated.
+	InvExpSecs int64 `mapstructure:"inv_exp_secs"`
+
+	// Interval of the stall checker, which is off if not positive.
+	StallCheckSecs int64 `mapstructure:"stall_check_secs"`
+	// Idle time after which assignments are flagged stalled.
+	StallAfterSecs int64 `mapstructure:"stall_after_secs"`
+
+	// Open tasks a user may claim at once, unlimited if not positive.
+	TaskClaimLimit int `mapstructure:"task_claim_limit"`
+
+	// Interval of the webhook dispatcher, which is off if not positive.
+	WebhookDispatchSecs int64 `mapstructure:"webhook_dispatch_secs"`
+	// Time allowed for each delivery attempt.
+	WebhookTimeoutSecs int64 `mapstructure:"webhook_timeout_secs"`
+	// Attempts after which a delivery is given up.
+	WebhookMaxAttempts int `mapstructure:"webhook_max_attempts"`
+
+	// Base URL of the HTTP API of the OneBot v11 QQ bot, which is off if empty.
+	OneBotAPIURL string `mapstructure:"onebot_api_url"`
+	// Interval of pushing notifications to QQ, which is off if not positive.
+	OneBotPushSecs int64 `mapstructure:"onebot_push_secs"`
+	// Groups whose commands are answered, all of them if empty.
+	OneBotGroupIDs []int64 `mapstructure:"onebot_group_ids"`
 
 	ComicExportDir string `mapstructure:"comic_export_dir"`
 }
@@ -12,6 +12,8 @@ import (
 // UserClaims contains the claims stored in JWT tokens.
 type UserClaims struct {
 	UserID string `json:"user_id"`
+	// Session the token was issued for, checked against revocation on every request.
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
@@ -35,12 +37,16 @@ func NewJWTCodec(expSecs int64) *Codec {
 	}
 }
 
-func (jc *Codec) Encode(userID string) (string, error) {
+// ExpSecs returns the lifetime of issued tokens in seconds.
+func (jc *Codec) ExpSecs() int64 { return jc.expSecs }
+
+func (jc *Codec) Encode(userID string, sessionID string) (string, error) {
 	now := time.Now()
 	exp := now.Add(time.Duration(jc.expSecs) * time.Second)
 
 	claims := &UserClaims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(exp),
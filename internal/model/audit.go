@@ -0,0 +1,41 @@
+package model
+
+import "encoding/json"
+
+// ReqMeta is the request metadata recorded along audited operations.
+type ReqMeta struct {
+	IP        string
+	UserAgent string
+}
+
+type AuditLogInfo struct {
+	ID      string `json:"id"`
+	ActorID string `json:"actor_id"`
+
+	Action     string `json:"action"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+type RetrieveAuditLogOpt struct {
+	ActorID *string `url:"actor_id,omitempty"`
+	Action  *string `url:"action,omitempty"`
+
+	EntityType *string `url:"entity_type,omitempty"`
+	EntityID   *string `url:"entity_id,omitempty"`
+
+	// Unix seconds; From is inclusive and To exclusive.
+	From *int64 `url:"from,omitempty"`
+	To   *int64 `url:"to,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
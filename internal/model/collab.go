@@ -0,0 +1,34 @@
+package model
+
+// PageEvent is sent to the viewers of a page as a server-sent event named Type.
+type PageEvent struct {
+	Type string
+	Data any
+}
+
+// UnitsEventData describes units created, updated or deleted on a page.
+type UnitsEventData struct {
+	PageID  string `json:"page_id"`
+	ActorID string `json:"actor_id"`
+
+	// The units as they are after creation or update.
+	Units []ComicUnitInfo `json:"units,omitempty"`
+	// The IDs of deleted units.
+	UnitIDs []string `json:"unit_ids,omitempty"`
+
+	OccurredAt int64 `json:"occurred_at"`
+}
+
+// PresenceEventData lists who are viewing a page.
+type PresenceEventData struct {
+	PageID  string           `json:"page_id"`
+	Viewers []PageViewerInfo `json:"viewers"`
+}
+
+type PageViewerInfo struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname"`
+
+	// When the earliest of the connections of the user was opened.
+	JoinedAt int64 `json:"joined_at"`
+}
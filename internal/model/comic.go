@@ -1,5 +1,7 @@
 package model
 
+import "encoding/json"
+
 type ComicBrief struct {
 	ID string `json:"id"`
 
@@ -10,7 +12,14 @@ type ComicBrief struct {
 	Author string `json:"author"`
 	Title  string `json:"title"`
 
-	PageCount int64 `json:"page_count"`
+	PageCount  int64 `json:"page_count"`
+	LikesCount int64 `json:"likes_count"`
+
+	// One of the COMIC_STAGE_* values.
+	Stage string `json:"stage"`
+
+	// Whether the requesting user likes the comic.
+	LikedByMe bool `json:"liked_by_me"`
 
 	TranslatingStartedAt    *int64 `json:"translating_started_at"`
 	TranslatingCompletedAt  *int64 `json:"translating_completed_at"`
@@ -37,7 +46,11 @@ type ComicInfo struct {
 	Description *string `json:"description,omitempty"`
 	Comment     *string `json:"comment,omitempty"`
 
-	PageCount int64 `json:"page_count"`
+	PageCount  int64 `json:"page_count"`
+	LikesCount int64 `json:"likes_count"`
+
+	// One of the COMIC_STAGE_* values.
+	Stage string `json:"stage"`
 
 	TranslatingStartedAt    *int64 `json:"translating_started_at"`
 	TranslatingCompletedAt  *int64 `json:"translating_completed_at"`
@@ -48,6 +61,8 @@ type ComicInfo struct {
 	ReviewingCompletedAt    *int64 `json:"reviewing_completed_at"`
 	UploadingCompletedAt    *int64 `json:"uploading_completed_at"`
 
+	Tags []TagBrief `json:"tags"`
+
 	CreatedAt int64 `json:"created_at"`
 	UpdatedAt int64 `json:"updated_at"`
 }
@@ -60,6 +75,10 @@ type RetrieveComicOpt struct {
 	WorksetIndex *string `url:"widx,omitempty"`
 	Index        *string `url:"idx,omitempty"`
 
+	// COMIC_STAGE_* values, each given as a repeated query parameter.
+	// A comic must be at one of them.
+	Stages []string `url:"stage,omitempty"`
+
 	// Every group below is only allowed to be one of three states:
 	// nil (not care), true, false.
 	// What's more, only one of the three states can be true at the same time in each group.
@@ -84,10 +103,26 @@ type RetrieveComicOpt struct {
 	// Accurate.
 	AssignedUserID *string `url:"auid,omitempty"`
 
+	// Tag IDs, each given as a repeated query parameter.
+	// A comic must carry at least one of AnyTagIDs, every one of AllTagIDs
+	// and none of ExcludedTagIDs.
+	AnyTagIDs      []string `url:"tag_any,omitempty"`
+	AllTagIDs      []string `url:"tag_all,omitempty"`
+	ExcludedTagIDs []string `url:"tag_none,omitempty"`
+
+	// Sort order, COMIC_SORT_UPDATED (default) or COMIC_SORT_LIKES.
+	Sort *string `url:"sort,omitempty"`
+
 	Offset int `url:"offset"`
 	Limit  int `url:"limit"`
 }
 
+// Sort orders of RetrieveComicOpt.
+const (
+	COMIC_SORT_UPDATED = "updated"
+	COMIC_SORT_LIKES   = "likes"
+)
+
 type CreateComicArgs struct {
 	WorksetID   string  `json:"workset_id"`
 	Author      string  `json:"author"`
@@ -98,10 +133,21 @@ type CreateComicArgs struct {
 	// Pre-assignments when creating the comic.
 	// So the ComicID is not known yet, only user IDs and roles can be specified.
 	PreAsgns []PreAsgnArgs `json:"pre_asgns,omitempty"`
+
+	// A team whose members are assigned along with PreAsgns.
+	// A user in both holds the roles of either.
+	TeamID *string `json:"team_id,omitempty"`
+
+	// Gallery metadata of the raw, as returned by the e-hentai gallery API
+	// or the pica comic detail API. Tags are resolved from it and attached.
+	GalleryMeta json.RawMessage `json:"gallery_meta,omitempty"`
 }
 
 type CreateComicReply struct {
 	ID string `json:"id"`
+
+	// Tags resolved from the gallery metadata.
+	Tags []TagBrief `json:"tags,omitempty"`
 }
 
 type UpdateComicArgs struct {
@@ -111,7 +157,8 @@ type UpdateComicArgs struct {
 	Description *string `json:"description,omitempty"`
 	Comment     *string `json:"comment,omitempty"`
 
-	// Workflow status toggles - true sets timestamp to now, false/nil ignores
+	// Stage toggles of the old API, only read to reject them,
+	// as the stage is now changed through PUT /comics/{id}/stage.
 	TranslatingStarted    *bool `json:"translating_started,omitempty"`
 	TranslatingCompleted  *bool `json:"translating_completed,omitempty"`
 	ProofreadingStarted   *bool `json:"proofreading_started,omitempty"`
@@ -122,6 +169,67 @@ type UpdateComicArgs struct {
 	UploadingCompleted    *bool `json:"uploading_completed,omitempty"`
 }
 
+// Workflow stages of a comic, in order.
+// Work starts at translating; pending and completed bracket the workflow.
+const (
+	COMIC_STAGE_PENDING      = "pending"
+	COMIC_STAGE_TRANSLATING  = "translating"
+	COMIC_STAGE_PROOFREADING = "proofreading"
+	COMIC_STAGE_TYPESETTING  = "typesetting"
+	COMIC_STAGE_REVIEWING    = "reviewing"
+	COMIC_STAGE_UPLOADING    = "uploading"
+	COMIC_STAGE_COMPLETED    = "completed"
+)
+
+type ComicWorkflowInfo struct {
+	ComicID string `json:"comic_id"`
+	Stage   string `json:"stage"`
+
+	// Stages the requesting user may move the comic to.
+	// Moving back to an earlier stage needs a reason.
+	Transitions []string `json:"transitions"`
+
+	// What keeps the comic from completing its current stage, if anything.
+	Gate *StageGateReport `json:"gate,omitempty"`
+}
+
+type TransitionComicStageArgs struct {
+	ComicID string `json:"comic_id"`
+	Stage   string `json:"stage"`
+
+	// Required when reopening an earlier stage.
+	Reason *string `json:"reason,omitempty"`
+
+	// Complete the current stage despite blocking units. Admins only.
+	Force bool `json:"force,omitempty"`
+}
+
+// Reasons a unit blocks the completion of a stage.
+const (
+	UNIT_BLOCK_UNTRANSLATED       = "untranslated"
+	UNIT_BLOCK_UNPROVED           = "unproved"
+	UNIT_BLOCK_UNRESOLVED_COMMENT = "unresolved_comment"
+)
+
+// StageGateReport lists the units keeping a comic from completing a stage.
+type StageGateReport struct {
+	// The stage to be completed.
+	Stage string               `json:"stage"`
+	Pages []StageGateBlockPage `json:"pages"`
+}
+
+type StageGateBlockPage struct {
+	PageID string               `json:"page_id"`
+	Index  int64                `json:"index"`
+	Units  []StageGateBlockUnit `json:"units"`
+}
+
+type StageGateBlockUnit struct {
+	UnitID  string   `json:"unit_id"`
+	Index   int64    `json:"index"`
+	Reasons []string `json:"reasons"`
+}
+
 type ExportComicReply struct {
 	ExportURI string `json:"export_uri"`
 }
@@ -10,27 +10,105 @@ type ComicAsgnInfo struct {
 	AssignedTypesetterAt  *int64 `json:"assigned_typesetter_at,omitempty"`
 	AssignedRedrawerAt    *int64 `json:"assigned_redrawer_at,omitempty"`
 	AssignedReviewerAt    *int64 `json:"assigned_reviewer_at,omitempty"`
+	TranslatorDueAt       *int64 `json:"translator_due_at,omitempty"`
+	ProofreaderDueAt      *int64 `json:"proofreader_due_at,omitempty"`
+	TypesetterDueAt       *int64 `json:"typesetter_due_at,omitempty"`
+	RedrawerDueAt         *int64 `json:"redrawer_due_at,omitempty"`
+	ReviewerDueAt         *int64 `json:"reviewer_due_at,omitempty"`
+	StalledAt             *int64 `json:"stalled_at,omitempty"`
 	CreatedAt             int64  `json:"created_at"`
 	UpdatedAt             int64  `json:"updated_at"`
+
+	// Pages the translator works on, the whole comic if empty.
+	PageRanges []PageRange `json:"page_ranges,omitempty"`
 }
 
+// Due dates are Unix seconds, each for a role being assigned.
 type CreateComicAsgnArgs struct {
-	ComicID       string `json:"comic_id"`
-	AssigneeID    string `json:"assignee_id"`
-	IsTranslator  *bool  `json:"is_translator"`
-	IsProofreader *bool  `json:"is_proofreader"`
-	IsTypesetter  *bool  `json:"is_typesetter"`
-	IsRedrawer    *bool  `json:"is_redrawer"`
-	IsReviewer    *bool  `json:"is_reviewer"`
+	ComicID          string `json:"comic_id"`
+	AssigneeID       string `json:"assignee_id"`
+	IsTranslator     *bool  `json:"is_translator"`
+	IsProofreader    *bool  `json:"is_proofreader"`
+	IsTypesetter     *bool  `json:"is_typesetter"`
+	IsRedrawer       *bool  `json:"is_redrawer"`
+	IsReviewer       *bool  `json:"is_reviewer"`
+	TranslatorDueAt  *int64 `json:"translator_due_at,omitempty"`
+	ProofreaderDueAt *int64 `json:"proofreader_due_at,omitempty"`
+	TypesetterDueAt  *int64 `json:"typesetter_due_at,omitempty"`
+	RedrawerDueAt    *int64 `json:"redrawer_due_at,omitempty"`
+	ReviewerDueAt    *int64 `json:"reviewer_due_at,omitempty"`
+
+	// Only for translators, who work on the whole comic if not given.
+	PageRanges []PageRange `json:"page_ranges,omitempty"`
 }
 
+// Due dates are Unix seconds, each for a role held after the update; 0 erases one.
+// Removing a role erases its due date, and removing the translator role its page ranges.
 type UpdateComicAsgnArgs struct {
-	ID            string `json:"id"`
-	IsTranslator  *bool  `json:"is_translator,omitempty"`
-	IsProofreader *bool  `json:"is_proofreader,omitempty"`
-	IsTypesetter  *bool  `json:"is_typesetter,omitempty"`
-	IsRedrawer    *bool  `json:"is_redrawer,omitempty"`
-	IsReviewer    *bool  `json:"is_reviewer,omitempty"`
+	ID               string `json:"id"`
+	IsTranslator     *bool  `json:"is_translator,omitempty"`
+	IsProofreader    *bool  `json:"is_proofreader,omitempty"`
+	IsTypesetter     *bool  `json:"is_typesetter,omitempty"`
+	IsRedrawer       *bool  `json:"is_redrawer,omitempty"`
+	IsReviewer       *bool  `json:"is_reviewer,omitempty"`
+	TranslatorDueAt  *int64 `json:"translator_due_at,omitempty"`
+	ProofreaderDueAt *int64 `json:"proofreader_due_at,omitempty"`
+	TypesetterDueAt  *int64 `json:"typesetter_due_at,omitempty"`
+	RedrawerDueAt    *int64 `json:"redrawer_due_at,omitempty"`
+	ReviewerDueAt    *int64 `json:"reviewer_due_at,omitempty"`
+
+	// Only for translators. An empty array erases the page ranges.
+	PageRanges *[]PageRange `json:"page_ranges,omitempty"`
+}
+
+// PageRange is a range of page indexes with both ends inclusive.
+type PageRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// PageCoverage reports how the pages of a comic are split among its translators.
+type PageCoverage struct {
+	// Whether any translator has page ranges. If not, all of them work on the whole comic
+	// and neither gaps nor overlaps are reported.
+	Split bool `json:"split"`
+
+	// Pages no translator works on.
+	Gaps []PageRange `json:"gaps"`
+	// Pages several translators work on.
+	Overlaps []PageOverlap `json:"overlaps"`
+}
+
+type PageOverlap struct {
+	PageRange
+
+	UserIDs []string `json:"user_ids"`
+}
+
+type RetrieveAsgnOpt struct {
+	ComicID *string `url:"comic_id,omitempty"`
+	UserID  *string `url:"user_id,omitempty"`
+
+	// Only assignments with a role past its due date
+	// whose stage the comic has not completed yet.
+	Overdue bool `url:"overdue,omitempty"`
+	// Only assignments flagged by the stall checker.
+	Stalled bool `url:"stalled,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+// AsgnDeadline is the due date of a role whose stage the comic has not completed yet.
+type AsgnDeadline struct {
+	AsgnID     string `json:"assignment_id"`
+	ComicID    string `json:"comic_id"`
+	ComicTitle string `json:"comic_title"`
+	ComicStage string `json:"comic_stage"`
+
+	Role    string `json:"role"`
+	DueAt   int64  `json:"due_at"`
+	Overdue bool   `json:"overdue"`
 }
 
 type PreAsgnArgs struct {
@@ -0,0 +1,25 @@
+package model
+
+import "encoding/json"
+
+type ComicEventInfo struct {
+	ID      string `json:"id"`
+	ComicID string `json:"comic_id"`
+
+	ActorID string `json:"actor_id"`
+	// Nil if the actor no longer exists.
+	ActorNickname *string `json:"actor_nickname"`
+
+	Kind   string          `json:"kind"`
+	Detail json.RawMessage `json:"detail,omitempty"`
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+type RetrieveComicEventOpt struct {
+	// Event kinds, each given as a repeated query parameter.
+	Kinds []string `url:"kind,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
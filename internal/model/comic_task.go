@@ -0,0 +1,43 @@
+package model
+
+type ComicTaskInfo struct {
+	ID         string `json:"id"`
+	ComicID    string `json:"comic_id"`
+	ComicTitle string `json:"comic_title"`
+	ComicStage string `json:"comic_stage"`
+
+	Role  string  `json:"role"`
+	Note  *string `json:"note,omitempty"`
+	DueAt *int64  `json:"due_at,omitempty"`
+
+	CreatorID *string `json:"creator_id,omitempty"`
+
+	// Nil while the task is open.
+	ClaimedBy       *string `json:"claimed_by,omitempty"`
+	ClaimerNickname *string `json:"claimer_nickname,omitempty"`
+	ClaimedAt       *int64  `json:"claimed_at,omitempty"`
+
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+type CreateComicTaskArgs struct {
+	ComicID string  `json:"comic_id"`
+	Role    string  `json:"role"`
+	Note    *string `json:"note,omitempty"`
+
+	// Unix seconds, set as the due date of the role once claimed.
+	DueAt *int64 `json:"due_at,omitempty"`
+}
+
+type RetrieveComicTaskOpt struct {
+	ComicID *string `url:"comic_id,omitempty"`
+	Role    *string `url:"role,omitempty"`
+
+	// Open tasks if false, claimed ones if true, all if not given.
+	Claimed   *bool   `url:"claimed,omitempty"`
+	ClaimedBy *string `url:"claimed_by,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
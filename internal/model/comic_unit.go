@@ -23,8 +23,14 @@ type ComicUnitInfo struct {
 	// TODO
 	CreatorID *string `json:"creator_id,omitempty"`
 
+	Version int64 `json:"version"`
+
 	CreatedAt int64 `json:"created_at"`
 	UpdatedAt int64 `json:"updated_at"`
+
+	// Only filled when glossary annotation is requested.
+	TermHits            []TermHit           `json:"term_hits,omitempty"`
+	TermInconsistencies []TermInconsistency `json:"term_inconsistencies,omitempty"`
 }
 
 type NewComicUnitArgs struct {
@@ -56,4 +62,21 @@ type PatchComicUnitArgs struct {
 	ProvedText         *string `json:"proved_text,omitempty"`
 	Proved             *bool   `json:"proved,omitempty"`
 	ProofreaderComment *string `json:"proofreader_comment,omitempty"`
+
+	// The version of the unit the changes are based on.
+	// If omitted, the unit is overwritten whatever its version.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// UnitConflictReport lists the units changed by others since the versions expected.
+type UnitConflictReport struct {
+	Units []UnitConflict `json:"units"`
+}
+
+type UnitConflict struct {
+	UnitID          string `json:"unit_id"`
+	ExpectedVersion int64  `json:"expected_version"`
+
+	// The unit as it is now, absent if deleted.
+	Current *ComicUnitInfo `json:"current,omitempty"`
 }
@@ -1,30 +1,68 @@
 package model
 
 type InvitationInfo struct {
-	ID                string `json:"id"`
-	InvitorID         string `json:"invitor_id"`
-	InviteeQQ         string `json:"invitee_qq"`
-	InvCode           string `json:"invitation_code"`
-	AssignTranslator  bool   `json:"assign_translator"`
-	AssignProofreader bool   `json:"assign_proofreader"`
-	AssignTypesetter  bool   `json:"assign_typesetter"`
-	AssignRedrawer    bool   `json:"assign_redrawer"`
-	AssignReviewer    bool   `json:"assign_reviewer"`
-	AssignUploader    bool   `json:"assign_uploader"`
-	Pending           bool   `json:"pending"`
-	CreatedAt         int64  `json:"created_at"`
+	ID                string  `json:"id"`
+	InvitorID         string  `json:"invitor_id"`
+	InviteeQQ         string  `json:"invitee_qq"`
+	AssignTranslator  bool    `json:"assign_translator"`
+	AssignProofreader bool    `json:"assign_proofreader"`
+	AssignTypesetter  bool    `json:"assign_typesetter"`
+	AssignRedrawer    bool    `json:"assign_redrawer"`
+	AssignReviewer    bool    `json:"assign_reviewer"`
+	AssignUploader    bool    `json:"assign_uploader"`
+	Note              *string `json:"note"`
+	Pending           bool    `json:"pending"`
+	Status            string  `json:"status"`
+	ExpiresAt         int64   `json:"expires_at"`
+	CreatedAt         int64   `json:"created_at"`
 }
 
 type CreateInvitationArgs struct {
-	InviteeQQ         string `json:"invitee_qq"`
-	AssignTranslator  *bool  `json:"assign_translator,omitempty"`
-	AssignProofreader *bool  `json:"assign_proofreader,omitempty"`
-	AssignTypesetter  *bool  `json:"assign_typesetter,omitempty"`
-	AssignRedrawer    *bool  `json:"assign_redrawer,omitempty"`
-	AssignReviewer    *bool  `json:"assign_reviewer,omitempty"`
-	AssignUploader    *bool  `json:"assign_uploader,omitempty"`
+	InviteeQQ         string  `json:"invitee_qq"`
+	AssignTranslator  *bool   `json:"assign_translator,omitempty"`
+	AssignProofreader *bool   `json:"assign_proofreader,omitempty"`
+	AssignTypesetter  *bool   `json:"assign_typesetter,omitempty"`
+	AssignRedrawer    *bool   `json:"assign_redrawer,omitempty"`
+	AssignReviewer    *bool   `json:"assign_reviewer,omitempty"`
+	AssignUploader    *bool   `json:"assign_uploader,omitempty"`
+	Note              *string `json:"note,omitempty"`
 }
 
 type CreateInvitationReply struct {
-	InvCode string `json:"invitation_code"`
+	ID        string `json:"id"`
+	InvCode   string `json:"invitation_code"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type BulkCreateInvitationsReply struct {
+	DryRun bool `json:"dry_run"`
+
+	// Number of rows in the file.
+	Total int `json:"total"`
+	// Number of invitations created, or to be created in dry-run mode.
+	Created int `json:"created"`
+
+	Rows []BulkInvitationRow `json:"rows"`
+
+	// CSV of the created invitations with their codes, empty in dry-run mode.
+	// The codes cannot be retrieved again, so clients should offer it as a download.
+	CodeSheet     string `json:"code_sheet"`
+	CodeSheetName string `json:"code_sheet_name"`
+}
+
+// Result of one row of a bulk invitation file.
+type BulkInvitationRow struct {
+	Line      int      `json:"line"`
+	InviteeQQ string   `json:"invitee_qq"`
+	Roles     []string `json:"roles"`
+	Note      *string  `json:"note"`
+
+	// One of "created", "valid" in dry-run mode, or "rejected" with Error set.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	// Set once created.
+	ID        string `json:"id,omitempty"`
+	InvCode   string `json:"invitation_code,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
 }
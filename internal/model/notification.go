@@ -0,0 +1,40 @@
+package model
+
+import "encoding/json"
+
+type NotificationInfo struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+
+	ComicID    *string `json:"comic_id,omitempty"`
+	ComicTitle *string `json:"comic_title,omitempty"`
+
+	ActorID       *string `json:"actor_id,omitempty"`
+	ActorNickname *string `json:"actor_nickname,omitempty"`
+
+	Detail json.RawMessage `json:"detail,omitempty"`
+
+	// Nil while unread.
+	ReadAt    *int64 `json:"read_at,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type RetrieveNotificationOpt struct {
+	// Unread notifications only if true.
+	Unread bool `url:"unread"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type RetrieveNotificationReply struct {
+	Notifications []NotificationInfo `json:"notifications"`
+
+	// Unread notifications in total, regardless of paging.
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkAllNotificationsReadReply struct {
+	// Notifications marked as read.
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,103 @@
+package po
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// StringArray maps a PostgreSQL TEXT[] column.
+// Only one-dimensional arrays are supported, and NULL elements are dropped.
+type StringArray []string
+
+// Scan implements sql.Scanner for the text representation of an array,
+// e.g. {a,"b c","d\"e"}.
+func (a *StringArray) Scan(src any) error {
+	var s string
+
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringArray", src)
+	}
+
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return fmt.Errorf("malformed array literal: %q", s)
+	}
+	s = s[1 : len(s)-1]
+
+	elems := StringArray{}
+	if s == "" {
+		*a = elems
+		return nil
+	}
+
+	var (
+		sb      strings.Builder
+		quoted  bool
+		inQuote bool
+		escaped bool
+	)
+
+	flush := func() {
+		elem := sb.String()
+		if quoted || elem != "NULL" {
+			elems = append(elems, elem)
+		}
+		sb.Reset()
+		quoted = false
+	}
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+			quoted = true
+		case r == ',' && !inQuote:
+			flush()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	flush()
+
+	*a = elems
+
+	return nil
+}
+
+var arrayElemEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// Value implements driver.Valuer. Every element is quoted.
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+
+	var sb strings.Builder
+
+	sb.WriteByte('{')
+	for i, elem := range a {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteByte('"')
+		sb.WriteString(arrayElemEscaper.Replace(elem))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+
+	return sb.String(), nil
+}
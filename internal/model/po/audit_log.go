@@ -0,0 +1,48 @@
+package po
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AUDIT_LOG_TABLE = "audit_log_tbl"
+)
+
+// Used when recording an audit log entry.
+type NewAuditLog struct {
+	ID      string `gorm:"column:id;primaryKey"`
+	ActorID string `gorm:"column:actor_id"`
+
+	Action     string `gorm:"column:action"`
+	EntityType string `gorm:"column:entity_type"`
+	EntityID   string `gorm:"column:entity_id"`
+
+	Before json.RawMessage `gorm:"column:before"`
+	After  json.RawMessage `gorm:"column:after"`
+
+	IP        string `gorm:"column:ip"`
+	UserAgent string `gorm:"column:user_agent"`
+}
+
+// Used when retrieving audit log entries.
+type BasicAuditLog struct {
+	ID      string `gorm:"column:id;primaryKey"`
+	ActorID string `gorm:"column:actor_id"`
+
+	Action     string `gorm:"column:action"`
+	EntityType string `gorm:"column:entity_type"`
+	EntityID   string `gorm:"column:entity_id"`
+
+	Before json.RawMessage `gorm:"column:before"`
+	After  json.RawMessage `gorm:"column:after"`
+
+	IP        string `gorm:"column:ip"`
+	UserAgent string `gorm:"column:user_agent"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (*NewAuditLog) TableName() string { return AUDIT_LOG_TABLE }
+
+func (*BasicAuditLog) TableName() string { return AUDIT_LOG_TABLE }
@@ -33,7 +33,10 @@ type BriefComic struct {
 	Author string `gorm:"column:author"`
 	Title  string `gorm:"column:title"`
 
-	PageCount int64 `gorm:"column:page_count"`
+	PageCount  int64 `gorm:"column:page_count"`
+	LikesCount int64 `gorm:"column:likes_count"`
+
+	Stage string `gorm:"column:stage"`
 
 	TranslatingStartedAt    *time.Time `gorm:"column:translating_started_at"`
 	TranslatingCompletedAt  *time.Time `gorm:"column:translating_completed_at"`
@@ -61,7 +64,10 @@ type BasicComic struct {
 	Comment     *string `gorm:"column:comment"`
 	Description *string `gorm:"column:description"`
 
-	PageCount int64 `gorm:"column:page_count"`
+	PageCount  int64 `gorm:"column:page_count"`
+	LikesCount int64 `gorm:"column:likes_count"`
+
+	Stage string `gorm:"column:stage"`
 
 	TranslatingStartedAt    *time.Time `gorm:"column:translating_started_at"`
 	TranslatingCompletedAt  *time.Time `gorm:"column:translating_completed_at"`
@@ -86,6 +92,9 @@ type PatchComic struct {
 	Comment     *string `gorm:"column:comment"`
 	Description *string `gorm:"column:description"`
 
+	Stage *string `gorm:"column:stage"`
+
+	// A zero time clears the column.
 	TranslatingStartedAt    *time.Time `gorm:"column:translating_started_at"`
 	TranslatingCompletedAt  *time.Time `gorm:"column:translating_completed_at"`
 	ProofreadingStartedAt   *time.Time `gorm:"column:proofreading_started_at"`
@@ -1,6 +1,7 @@
 package po
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -8,6 +9,9 @@ const (
 	COMIC_ASSIGNMENT_TABLE = "comic_assignment_tbl"
 )
 
+// Roles of assignments, as in the assigned_<role>_at and <role>_due_at columns.
+var COMIC_ASGN_ROLES = []string{"translator", "proofreader", "typesetter", "redrawer", "reviewer"}
+
 // Used when creating a new comic assignment.
 type NewComicAsgn struct {
 	ID      string `gorm:"column:id;primaryKey"`
@@ -28,10 +32,29 @@ type BasicComicAsgn struct {
 	AssignedRedrawerAt    *time.Time `gorm:"column:assigned_redrawer_at"`
 	AssignedReviewerAt    *time.Time `gorm:"column:assigned_reviewer_at"`
 
+	TranslatorDueAt  *time.Time `gorm:"column:translator_due_at"`
+	ProofreaderDueAt *time.Time `gorm:"column:proofreader_due_at"`
+	TypesetterDueAt  *time.Time `gorm:"column:typesetter_due_at"`
+	RedrawerDueAt    *time.Time `gorm:"column:redrawer_due_at"`
+	ReviewerDueAt    *time.Time `gorm:"column:reviewer_due_at"`
+
+	StalledAt *time.Time `gorm:"column:stalled_at"`
+
+	// Nil if the translator works on the whole comic.
+	PageRanges json.RawMessage `gorm:"column:page_ranges"`
+
 	CreatedAt time.Time `gorm:"column:created_at"`
 	UpdatedAt time.Time `gorm:"column:updated_at"`
 }
 
+// Used when retrieving assignments with due dates, along with their comic.
+type DueComicAsgn struct {
+	BasicComicAsgn `gorm:"embedded"`
+
+	ComicTitle string `gorm:"column:comic_title"`
+	ComicStage string `gorm:"column:comic_stage"`
+}
+
 // Used when updating comic assignment info.
 type PatchComicAsgn struct {
 	ID      string  `gorm:"column:id;primaryKey"`
@@ -44,6 +67,16 @@ type PatchComicAsgn struct {
 	AssignedTypesetterAt  *time.Time `gorm:"column:assigned_typesetter_at"`
 	AssignedRedrawerAt    *time.Time `gorm:"column:assigned_redrawer_at"`
 	AssignedReviewerAt    *time.Time `gorm:"column:assigned_reviewer_at"`
+
+	// Likewise, zero time erases the due date.
+	TranslatorDueAt  *time.Time `gorm:"column:translator_due_at"`
+	ProofreaderDueAt *time.Time `gorm:"column:proofreader_due_at"`
+	TypesetterDueAt  *time.Time `gorm:"column:typesetter_due_at"`
+	RedrawerDueAt    *time.Time `gorm:"column:redrawer_due_at"`
+	ReviewerDueAt    *time.Time `gorm:"column:reviewer_due_at"`
+
+	// Empty but not nil erases the page ranges.
+	PageRanges json.RawMessage `gorm:"column:page_ranges"`
 }
 
 func (*NewComicAsgn) TableName() string { return COMIC_ASSIGNMENT_TABLE }
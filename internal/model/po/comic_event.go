@@ -0,0 +1,37 @@
+package po
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	COMIC_EVENT_TABLE = "comic_event_tbl"
+)
+
+// Used when recording a comic event.
+type NewComicEvent struct {
+	ID      string `gorm:"column:id;primaryKey"`
+	ComicID string `gorm:"column:comic_id"`
+	ActorID string `gorm:"column:actor_id"`
+
+	Kind   string          `gorm:"column:kind"`
+	Detail json.RawMessage `gorm:"column:detail"`
+}
+
+// Used when retrieving comic events.
+type BasicComicEvent struct {
+	ID            string  `gorm:"column:id;primaryKey"`
+	ComicID       string  `gorm:"column:comic_id"`
+	ActorID       string  `gorm:"column:actor_id"`
+	ActorNickname *string `gorm:"column:actor_nickname"`
+
+	Kind   string          `gorm:"column:kind"`
+	Detail json.RawMessage `gorm:"column:detail"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (*NewComicEvent) TableName() string { return COMIC_EVENT_TABLE }
+
+func (*BasicComicEvent) TableName() string { return COMIC_EVENT_TABLE }
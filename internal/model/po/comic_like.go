@@ -0,0 +1,13 @@
+package po
+
+const (
+	COMIC_LIKE_TABLE = "comic_like_tbl"
+)
+
+// Used when a user likes a comic.
+type NewComicLike struct {
+	UserID  string `gorm:"column:user_id;primaryKey"`
+	ComicID string `gorm:"column:comic_id;primaryKey"`
+}
+
+func (*NewComicLike) TableName() string { return COMIC_LIKE_TABLE }
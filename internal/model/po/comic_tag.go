@@ -0,0 +1,13 @@
+package po
+
+const (
+	COMIC_TAG_TABLE = "comic_tag_tbl"
+)
+
+// Used when attaching a tag to a comic.
+type NewComicTag struct {
+	ComicID string `gorm:"column:comic_id;primaryKey"`
+	TagID   string `gorm:"column:tag_id;primaryKey"`
+}
+
+func (*NewComicTag) TableName() string { return COMIC_TAG_TABLE }
@@ -0,0 +1,43 @@
+package po
+
+import (
+	"time"
+)
+
+const (
+	COMIC_TASK_TABLE = "comic_task_tbl"
+)
+
+// Used when opening a new comic task.
+type NewComicTask struct {
+	ID        string     `gorm:"column:id;primaryKey"`
+	ComicID   string     `gorm:"column:comic_id"`
+	Role      string     `gorm:"column:role"`
+	Note      *string    `gorm:"column:note"`
+	DueAt     *time.Time `gorm:"column:due_at"`
+	CreatorID string     `gorm:"column:creator_id"`
+}
+
+// Used when retrieving comic tasks, along with their comic and claimer.
+type BasicComicTask struct {
+	ID         string `gorm:"column:id;primaryKey"`
+	ComicID    string `gorm:"column:comic_id"`
+	ComicTitle string `gorm:"column:comic_title"`
+	ComicStage string `gorm:"column:comic_stage"`
+
+	Role      string     `gorm:"column:role"`
+	Note      *string    `gorm:"column:note"`
+	DueAt     *time.Time `gorm:"column:due_at"`
+	CreatorID *string    `gorm:"column:creator_id"`
+
+	ClaimedBy       *string    `gorm:"column:claimed_by"`
+	ClaimerNickname *string    `gorm:"column:claimer_nickname"`
+	ClaimedAt       *time.Time `gorm:"column:claimed_at"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (*NewComicTask) TableName() string { return COMIC_TASK_TABLE }
+
+func (*BasicComicTask) TableName() string { return COMIC_TASK_TABLE }
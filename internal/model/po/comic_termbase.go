@@ -0,0 +1,13 @@
+package po
+
+const (
+	COMIC_TERMBASE_TABLE = "comic_termbase_tbl"
+)
+
+// Used when linking a termbase to a comic.
+type NewComicTermbase struct {
+	ComicID    string `gorm:"column:comic_id;primaryKey"`
+	TermbaseID string `gorm:"column:termbase_id;primaryKey"`
+}
+
+func (*NewComicTermbase) TableName() string { return COMIC_TERMBASE_TABLE }
@@ -49,6 +49,9 @@ type BasicComicUnit struct {
 
 	CreatorID *string `gorm:"column:creator_id"`
 
+	// Incremented on every update.
+	Version int64 `gorm:"column:version"`
+
 	CreatedAt time.Time `gorm:"column:created_at"`
 	UpdatedAt time.Time `gorm:"column:updated_at"`
 }
@@ -72,6 +75,9 @@ type PatchComicUnit struct {
 	ProofreaderComment *string `gorm:"column:proofreader_comment"`
 
 	CreatorID *string `gorm:"column:creator_id"`
+
+	// If set, the unit is only updated if still of this version.
+	Version *int64 `gorm:"column:version"`
 }
 
 type UnitCounts struct {
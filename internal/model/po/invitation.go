@@ -9,9 +9,9 @@ const (
 type BasicInvitation struct {
 	ID string `gorm:"column:id;primaryKey"`
 
-	InvitorID string `gorm:"column:invitor_id"`
-	InviteeQQ string `gorm:"column:invitee_qq"`
-	InvCode   string `gorm:"column:invitation_code"`
+	InvitorID   string `gorm:"column:invitor_id"`
+	InviteeQQ   string `gorm:"column:invitee_qq"`
+	InvCodeHash string `gorm:"column:invitation_code_hash"`
 
 	AssignTranslator  bool `gorm:"column:assign_translator"`
 	AssignProofreader bool `gorm:"column:assign_proofreader"`
@@ -20,17 +20,31 @@ type BasicInvitation struct {
 	AssignReviewer    bool `gorm:"column:assign_reviewer"`
 	AssignUploader    bool `gorm:"column:assign_uploader"`
 
+	Note *string `gorm:"column:note"`
+
+	// False once consumed.
 	Pending bool `gorm:"column:pending"`
 
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at"`
+	ConsumedBy *string    `gorm:"column:consumed_by"`
+
 	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// Usable reports whether the invitation can still be consumed.
+func (inv *BasicInvitation) Usable(now time.Time) bool {
+	return inv.Pending && inv.RevokedAt == nil && now.Before(inv.ExpiresAt)
 }
 
 type NewInvitation struct {
 	ID string `gorm:"column:id;primaryKey"`
 
-	InvitorID string `gorm:"column:invitor_id"`
-	InviteeQQ string `gorm:"column:invitee_qq"`
-	InvCode   string `gorm:"column:invitation_code"`
+	InvitorID   string `gorm:"column:invitor_id"`
+	InviteeQQ   string `gorm:"column:invitee_qq"`
+	InvCodeHash string `gorm:"column:invitation_code_hash"`
 
 	AssignTranslator  bool `gorm:"column:assign_translator"`
 	AssignProofreader bool `gorm:"column:assign_proofreader"`
@@ -38,6 +52,10 @@ type NewInvitation struct {
 	AssignRedrawer    bool `gorm:"column:assign_redrawer"`
 	AssignReviewer    bool `gorm:"column:assign_reviewer"`
 	AssignUploader    bool `gorm:"column:assign_uploader"`
+
+	Note *string `gorm:"column:note"`
+
+	ExpiresAt time.Time `gorm:"column:expires_at"`
 }
 
 func (*BasicInvitation) TableName() string { return INVITATION_TABLE }
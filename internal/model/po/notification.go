@@ -0,0 +1,48 @@
+package po
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NOTIFICATION_TABLE = "notification_tbl"
+)
+
+// Used when sending a notification.
+type NewNotification struct {
+	ID      string  `gorm:"column:id;primaryKey"`
+	UserID  string  `gorm:"column:user_id"`
+	Kind    string  `gorm:"column:kind"`
+	ComicID *string `gorm:"column:comic_id"`
+	ActorID *string `gorm:"column:actor_id"`
+
+	Detail json.RawMessage `gorm:"column:detail"`
+}
+
+// Used when retrieving notifications, along with the comic title and actor nickname.
+type BasicNotification struct {
+	ID            string  `gorm:"column:id;primaryKey"`
+	UserID        string  `gorm:"column:user_id"`
+	Kind          string  `gorm:"column:kind"`
+	ComicID       *string `gorm:"column:comic_id"`
+	ComicTitle    *string `gorm:"column:comic_title"`
+	ActorID       *string `gorm:"column:actor_id"`
+	ActorNickname *string `gorm:"column:actor_nickname"`
+
+	Detail json.RawMessage `gorm:"column:detail"`
+
+	ReadAt    *time.Time `gorm:"column:read_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+// Used when pushing notifications to QQ, along with the QQ of the recipient.
+type PushNotification struct {
+	BasicNotification
+
+	UserQQ string `gorm:"column:user_qq"`
+}
+
+func (*NewNotification) TableName() string { return NOTIFICATION_TABLE }
+
+func (*BasicNotification) TableName() string { return NOTIFICATION_TABLE }
@@ -0,0 +1,46 @@
+package po
+
+import "time"
+
+const (
+	SESSION_TABLE = "session_tbl"
+)
+
+// Used when creating a new session on login.
+type NewSession struct {
+	ID     string `gorm:"column:id;primaryKey"`
+	UserID string `gorm:"column:user_id"`
+
+	RefreshTokenHash string `gorm:"column:refresh_token_hash"`
+
+	Device string `gorm:"column:device"`
+	IP     string `gorm:"column:ip"`
+
+	ExpiresAt time.Time `gorm:"column:expires_at"`
+}
+
+// Used when retrieving session info.
+type BasicSession struct {
+	ID     string `gorm:"column:id;primaryKey"`
+	UserID string `gorm:"column:user_id"`
+
+	RefreshTokenHash     string  `gorm:"column:refresh_token_hash"`
+	PrevRefreshTokenHash *string `gorm:"column:prev_refresh_token_hash"`
+
+	Device string `gorm:"column:device"`
+	IP     string `gorm:"column:ip"`
+
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	LastUsedAt time.Time  `gorm:"column:last_used_at"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+}
+
+// Active reports whether the session is neither revoked nor expired.
+func (s *BasicSession) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+func (*NewSession) TableName() string { return SESSION_TABLE }
+
+func (*BasicSession) TableName() string { return SESSION_TABLE }
@@ -0,0 +1,58 @@
+package po
+
+import (
+	"time"
+)
+
+const (
+	TAG_TABLE = "tag_tbl"
+)
+
+// Used when creating a new tag.
+type NewTag struct {
+	ID                string      `gorm:"column:id;primaryKey"`
+	Name              string      `gorm:"column:name"`
+	PicaCandidates    StringArray `gorm:"column:pica_candidates"`
+	EhentaiCandidates StringArray `gorm:"column:ehentai_candidates"`
+	CreatorID         string      `gorm:"column:creator_id"`
+}
+
+// Used when retrieving basic tag info.
+type BasicTag struct {
+	ID                string      `gorm:"column:id;primaryKey"`
+	Name              string      `gorm:"column:name"`
+	PicaCandidates    StringArray `gorm:"column:pica_candidates"`
+	EhentaiCandidates StringArray `gorm:"column:ehentai_candidates"`
+
+	CreatorID       string `gorm:"column:creator_id"`
+	CreatorNickname string `gorm:"column:creator_nickname"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// Used when retrieving tags attached to comics or users.
+type BriefTag struct {
+	ID   string `gorm:"column:id;primaryKey"`
+	Name string `gorm:"column:name"`
+
+	// The comic or user the tag is attached to.
+	OwnerID string `gorm:"column:owner_id"`
+}
+
+// Used when updating tag info.
+// Any fields with default zero values (nil) will not be updated.
+type PatchTag struct {
+	ID                string       `gorm:"column:id;primaryKey"`
+	Name              *string      `gorm:"column:name"`
+	PicaCandidates    *StringArray `gorm:"column:pica_candidates"`
+	EhentaiCandidates *StringArray `gorm:"column:ehentai_candidates"`
+}
+
+func (*NewTag) TableName() string { return TAG_TABLE }
+
+func (*BasicTag) TableName() string { return TAG_TABLE }
+
+func (*BriefTag) TableName() string { return TAG_TABLE }
+
+func (*PatchTag) TableName() string { return TAG_TABLE }
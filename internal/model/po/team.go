@@ -0,0 +1,71 @@
+package po
+
+import (
+	"time"
+)
+
+const (
+	TEAM_TABLE        = "team_tbl"
+	TEAM_MEMBER_TABLE = "team_member_tbl"
+)
+
+// Used when creating a new team.
+type NewTeam struct {
+	ID          string  `gorm:"column:id;primaryKey"`
+	Name        string  `gorm:"column:name"`
+	Description *string `gorm:"column:description"`
+	CreatorID   string  `gorm:"column:creator_id"`
+}
+
+// Used when retrieving team info.
+type BasicTeam struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	Description *string   `gorm:"column:description"`
+	CreatorID   *string   `gorm:"column:creator_id"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// Used when updating team info.
+// Any fields with default zero values (nil) will not be updated.
+type PatchTeam struct {
+	ID          string  `gorm:"column:id;primaryKey"`
+	Name        *string `gorm:"column:name"`
+	Description *string `gorm:"column:description"`
+}
+
+// Used when adding members to a team.
+type NewTeamMember struct {
+	TeamID string `gorm:"column:team_id;primaryKey"`
+	UserID string `gorm:"column:user_id;primaryKey"`
+
+	IsTranslator  bool `gorm:"column:is_translator"`
+	IsProofreader bool `gorm:"column:is_proofreader"`
+	IsTypesetter  bool `gorm:"column:is_typesetter"`
+	IsRedrawer    bool `gorm:"column:is_redrawer"`
+	IsReviewer    bool `gorm:"column:is_reviewer"`
+}
+
+// Used when retrieving team members, along with their nickname.
+type BasicTeamMember struct {
+	TeamID       string `gorm:"column:team_id;primaryKey"`
+	UserID       string `gorm:"column:user_id;primaryKey"`
+	UserNickname string `gorm:"column:user_nickname"`
+
+	IsTranslator  bool `gorm:"column:is_translator"`
+	IsProofreader bool `gorm:"column:is_proofreader"`
+	IsTypesetter  bool `gorm:"column:is_typesetter"`
+	IsRedrawer    bool `gorm:"column:is_redrawer"`
+	IsReviewer    bool `gorm:"column:is_reviewer"`
+}
+
+func (*NewTeam) TableName() string { return TEAM_TABLE }
+
+func (*BasicTeam) TableName() string { return TEAM_TABLE }
+
+func (*PatchTeam) TableName() string { return TEAM_TABLE }
+
+func (*NewTeamMember) TableName() string { return TEAM_MEMBER_TABLE }
+
+func (*BasicTeamMember) TableName() string { return TEAM_MEMBER_TABLE }
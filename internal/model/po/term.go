@@ -0,0 +1,49 @@
+package po
+
+import (
+	"time"
+)
+
+const (
+	TERM_TABLE = "term_tbl"
+)
+
+// Used when creating a new term.
+type NewTerm struct {
+	ID         string  `gorm:"column:id;primaryKey"`
+	TermbaseID string  `gorm:"column:termbase_id"`
+	SourceText string  `gorm:"column:source_text"`
+	TargetText string  `gorm:"column:target_text"`
+	Note       *string `gorm:"column:note"`
+	CreatorID  string  `gorm:"column:creator_id"`
+}
+
+// Used when retrieving basic term info.
+type BasicTerm struct {
+	ID         string  `gorm:"column:id;primaryKey"`
+	TermbaseID string  `gorm:"column:termbase_id"`
+	SourceText string  `gorm:"column:source_text"`
+	TargetText string  `gorm:"column:target_text"`
+	Note       *string `gorm:"column:note"`
+
+	CreatorID       string `gorm:"column:creator_id"`
+	CreatorNickname string `gorm:"column:creator_nickname"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// Used when updating term info.
+// Any fields with default zero values (nil) will not be updated.
+type PatchTerm struct {
+	ID         string  `gorm:"column:id;primaryKey"`
+	SourceText *string `gorm:"column:source_text"`
+	TargetText *string `gorm:"column:target_text"`
+	Note       *string `gorm:"column:note"`
+}
+
+func (*NewTerm) TableName() string { return TERM_TABLE }
+
+func (*BasicTerm) TableName() string { return TERM_TABLE }
+
+func (*PatchTerm) TableName() string { return TERM_TABLE }
@@ -0,0 +1,46 @@
+package po
+
+import (
+	"time"
+)
+
+const (
+	TERMBASE_TABLE = "termbase_tbl"
+)
+
+// Used when creating a new termbase.
+type NewTermbase struct {
+	ID          string  `gorm:"column:id;primaryKey"`
+	Name        string  `gorm:"column:name"`
+	Description *string `gorm:"column:description"`
+	CreatorID   string  `gorm:"column:creator_id"`
+}
+
+// Used when retrieving basic termbase info.
+type BasicTermbase struct {
+	ID          string  `gorm:"column:id;primaryKey"`
+	Name        string  `gorm:"column:name"`
+	Description *string `gorm:"column:description"`
+
+	CreatorID       string `gorm:"column:creator_id"`
+	CreatorNickname string `gorm:"column:creator_nickname"`
+
+	TermCount int64 `gorm:"column:term_count"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// Used when updating termbase info.
+// Any fields with default zero values (nil) will not be updated.
+type PatchTermbase struct {
+	ID          string  `gorm:"column:id;primaryKey"`
+	Name        *string `gorm:"column:name"`
+	Description *string `gorm:"column:description"`
+}
+
+func (*NewTermbase) TableName() string { return TERMBASE_TABLE }
+
+func (*BasicTermbase) TableName() string { return TERMBASE_TABLE }
+
+func (*PatchTermbase) TableName() string { return TERMBASE_TABLE }
@@ -0,0 +1,13 @@
+package po
+
+const (
+	USER_TAG_TABLE = "user_tag_tbl"
+)
+
+// Used when attaching a tag to a user.
+type NewUserTag struct {
+	UserID string `gorm:"column:user_id;primaryKey"`
+	TagID  string `gorm:"column:tag_id;primaryKey"`
+}
+
+func (*NewUserTag) TableName() string { return USER_TAG_TABLE }
@@ -0,0 +1,104 @@
+package po
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WEBHOOK_TABLE          = "webhook_tbl"
+	WEBHOOK_DELIVERY_TABLE = "webhook_delivery_tbl"
+)
+
+// Used when registering a new webhook.
+type NewWebhook struct {
+	ID          string          `gorm:"column:id;primaryKey"`
+	URL         string          `gorm:"column:url"`
+	Secret      string          `gorm:"column:secret"`
+	Events      json.RawMessage `gorm:"column:events"`
+	Description *string         `gorm:"column:description"`
+	Active      bool            `gorm:"column:active"`
+	CreatorID   string          `gorm:"column:creator_id"`
+}
+
+// Used when retrieving webhooks.
+type BasicWebhook struct {
+	ID          string          `gorm:"column:id;primaryKey"`
+	URL         string          `gorm:"column:url"`
+	Secret      string          `gorm:"column:secret"`
+	Events      json.RawMessage `gorm:"column:events"`
+	Description *string         `gorm:"column:description"`
+	Active      bool            `gorm:"column:active"`
+	CreatorID   *string         `gorm:"column:creator_id"`
+	CreatedAt   time.Time       `gorm:"column:created_at"`
+	UpdatedAt   time.Time       `gorm:"column:updated_at"`
+}
+
+// Used when updating webhooks.
+// Any fields with default zero values (nil) will not be updated.
+type PatchWebhook struct {
+	ID          string          `gorm:"column:id;primaryKey"`
+	URL         *string         `gorm:"column:url"`
+	Secret      *string         `gorm:"column:secret"`
+	Events      json.RawMessage `gorm:"column:events"`
+	Description *string         `gorm:"column:description"`
+	Active      *bool           `gorm:"column:active"`
+}
+
+// Used when queueing a webhook delivery.
+type NewWebhookDelivery struct {
+	ID        string          `gorm:"column:id;primaryKey"`
+	WebhookID string          `gorm:"column:webhook_id"`
+	Event     string          `gorm:"column:event"`
+	Payload   json.RawMessage `gorm:"column:payload"`
+}
+
+// Used when retrieving webhook deliveries.
+type BasicWebhookDelivery struct {
+	ID        string          `gorm:"column:id;primaryKey"`
+	WebhookID string          `gorm:"column:webhook_id"`
+	Event     string          `gorm:"column:event"`
+	Payload   json.RawMessage `gorm:"column:payload"`
+
+	Status        string    `gorm:"column:status"`
+	Attempts      int       `gorm:"column:attempts"`
+	NextAttemptAt time.Time `gorm:"column:next_attempt_at"`
+
+	LastStatusCode *int       `gorm:"column:last_status_code"`
+	LastError      *string    `gorm:"column:last_error"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// Used when sending a due webhook delivery, along with where and how to sign it.
+type DueWebhookDelivery struct {
+	BasicWebhookDelivery
+
+	URL    string `gorm:"column:url"`
+	Secret string `gorm:"column:secret"`
+}
+
+// Used when recording the result of a delivery attempt.
+type PatchWebhookDelivery struct {
+	ID             string     `gorm:"column:id;primaryKey"`
+	Status         string     `gorm:"column:status"`
+	Attempts       int        `gorm:"column:attempts"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at"`
+	LastStatusCode *int       `gorm:"column:last_status_code"`
+	LastError      *string    `gorm:"column:last_error"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+}
+
+func (*NewWebhook) TableName() string { return WEBHOOK_TABLE }
+
+func (*BasicWebhook) TableName() string { return WEBHOOK_TABLE }
+
+func (*PatchWebhook) TableName() string { return WEBHOOK_TABLE }
+
+func (*NewWebhookDelivery) TableName() string { return WEBHOOK_DELIVERY_TABLE }
+
+func (*BasicWebhookDelivery) TableName() string { return WEBHOOK_DELIVERY_TABLE }
+
+func (*PatchWebhookDelivery) TableName() string { return WEBHOOK_DELIVERY_TABLE }
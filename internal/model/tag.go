@@ -0,0 +1,46 @@
+package model
+
+type TagInfo struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	PicaCandidates    []string `json:"pica_candidates"`
+	EhentaiCandidates []string `json:"ehentai_candidates"`
+	CreatorID         string   `json:"creator_id"`
+	CreatorNickname   string   `json:"creator_nickname"`
+	CreatedAt         int64    `json:"created_at"`
+	UpdatedAt         int64    `json:"updated_at"`
+}
+
+// Embedded in ComicInfo and UserInfo.
+type TagBrief struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type RetrieveTagOpt struct {
+	Name *string `url:"nm,omitempty"` // Fuzzy
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type CreateTagArgs struct {
+	Name              string   `json:"name"`
+	PicaCandidates    []string `json:"pica_candidates,omitempty"`
+	EhentaiCandidates []string `json:"ehentai_candidates,omitempty"`
+}
+
+type CreateTagReply struct {
+	ID string `json:"id"`
+}
+
+type UpdateTagArgs struct {
+	ID                string    `json:"id"`
+	Name              *string   `json:"name,omitempty"`
+	PicaCandidates    *[]string `json:"pica_candidates,omitempty"`
+	EhentaiCandidates *[]string `json:"ehentai_candidates,omitempty"`
+}
+
+type AttachTagsArgs struct {
+	TagIDs []string `json:"tag_ids"`
+}
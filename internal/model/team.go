@@ -0,0 +1,64 @@
+package model
+
+type TeamInfo struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	CreatorID   *string          `json:"creator_id,omitempty"`
+	Members     []TeamMemberInfo `json:"members"`
+	CreatedAt   int64            `json:"created_at"`
+	UpdatedAt   int64            `json:"updated_at"`
+}
+
+type TeamMemberInfo struct {
+	UserID        string `json:"user_id"`
+	UserNickname  string `json:"user_nickname"`
+	IsTranslator  bool   `json:"is_translator"`
+	IsProofreader bool   `json:"is_proofreader"`
+	IsTypesetter  bool   `json:"is_typesetter"`
+	IsRedrawer    bool   `json:"is_redrawer"`
+	IsReviewer    bool   `json:"is_reviewer"`
+}
+
+// Each member must have at least one role and be qualified for it.
+type TeamMemberArgs struct {
+	UserID        string `json:"user_id"`
+	IsTranslator  bool   `json:"is_translator"`
+	IsProofreader bool   `json:"is_proofreader"`
+	IsTypesetter  bool   `json:"is_typesetter"`
+	IsRedrawer    bool   `json:"is_redrawer"`
+	IsReviewer    bool   `json:"is_reviewer"`
+}
+
+type CreateTeamArgs struct {
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	Members     []TeamMemberArgs `json:"members"`
+}
+
+type UpdateTeamArgs struct {
+	ID          string  `json:"id"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+
+	// Replaces all members if given.
+	Members *[]TeamMemberArgs `json:"members,omitempty"`
+}
+
+type RetrieveTeamOpt struct {
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type ApplyTeamArgs struct {
+	TeamID  string `json:"team_id"`
+	ComicID string `json:"comic_id"`
+}
+
+// ApplyTeamReply lists the assignments touched by applying a team.
+type ApplyTeamReply struct {
+	// Assignments created for members not assigned to the comic yet.
+	Created []string `json:"created"`
+	// Assignments of members given roles they did not hold.
+	Updated []string `json:"updated"`
+}
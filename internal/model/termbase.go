@@ -0,0 +1,141 @@
+package model
+
+type TermbaseInfo struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Description     *string `json:"description,omitempty"`
+	CreatorID       string  `json:"creator_id"`
+	CreatorNickname string  `json:"creator_nickname"`
+	TermCount       int64   `json:"term_count"`
+	CreatedAt       int64   `json:"created_at"`
+	UpdatedAt       int64   `json:"updated_at"`
+}
+
+type RetrieveTermbaseOpt struct {
+	Name *string `url:"nm,omitempty"` // Fuzzy
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type CreateTermbaseArgs struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+type CreateTermbaseReply struct {
+	ID string `json:"id"`
+}
+
+type UpdateTermbaseArgs struct {
+	ID          string  `json:"id"`
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+type TermInfo struct {
+	ID              string  `json:"id"`
+	TermbaseID      string  `json:"termbase_id"`
+	SourceText      string  `json:"source_text"`
+	TargetText      string  `json:"target_text"`
+	Note            *string `json:"note,omitempty"`
+	CreatorID       string  `json:"creator_id"`
+	CreatorNickname string  `json:"creator_nickname"`
+	CreatedAt       int64   `json:"created_at"`
+	UpdatedAt       int64   `json:"updated_at"`
+}
+
+type RetrieveTermOpt struct {
+	// Fuzzy, ordered by trigram similarity.
+	SourceText *string `url:"src,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type CreateTermArgs struct {
+	TermbaseID string  `json:"termbase_id"`
+	SourceText string  `json:"source_text"`
+	TargetText string  `json:"target_text"`
+	Note       *string `json:"note,omitempty"`
+}
+
+type CreateTermReply struct {
+	ID string `json:"id"`
+}
+
+type UpdateTermArgs struct {
+	ID         string  `json:"id"`
+	TermbaseID string  `json:"termbase_id"`
+	SourceText *string `json:"source_text,omitempty"`
+	TargetText *string `json:"target_text,omitempty"`
+	Note       *string `json:"note,omitempty"`
+}
+
+type LinkComicTermbaseArgs struct {
+	TermbaseID string `json:"termbase_id"`
+}
+
+// A term whose target text appears in a unit.
+type TermHit struct {
+	TermID     string `json:"term_id"`
+	TermbaseID string `json:"termbase_id"`
+	SourceText string `json:"source_text"`
+	TargetText string `json:"target_text"`
+
+	// Either "translated_text" or "proved_text".
+	Field string `json:"field"`
+}
+
+// A source text rendered as TargetText in a unit,
+// but as OtherTargetText in other units of the same comic.
+type TermInconsistency struct {
+	SourceText      string   `json:"source_text"`
+	TargetText      string   `json:"target_text"`
+	OtherTargetText string   `json:"other_target_text"`
+	UnitIDs         []string `json:"unit_ids"`
+}
+
+type ImportTermsReply struct {
+	DryRun bool `json:"dry_run"`
+
+	// Number of entries in the file.
+	Total int `json:"total"`
+	// Number of terms created, or to be created in dry-run mode.
+	Created int `json:"created"`
+
+	// Duplicates are skipped, conflicts are imported as additional renderings.
+	Duplicates []TermImportIssue `json:"duplicates"`
+	Conflicts  []TermImportIssue `json:"conflicts"`
+
+	// Set alone when the file is rejected, in which case nothing is imported.
+	Invalid *TermFileIssue `json:"invalid,omitempty"`
+}
+
+// Why an imported file was rejected.
+type TermFileIssue struct {
+	// As in TermImportIssue, 0 if the file is malformed as a whole.
+	Pos    int    `json:"pos"`
+	Reason string `json:"reason"`
+}
+
+// An imported entry duplicating or conflicting with an existing term
+// or with an earlier entry of the same file.
+type TermImportIssue struct {
+	// Line number for CSV, entry number for TBX.
+	Pos        int    `json:"pos"`
+	SourceText string `json:"source_text"`
+	TargetText string `json:"target_text"`
+
+	// Exactly one of ExistingTermID and ExistingPos is set.
+	ExistingTermID     *string `json:"existing_term_id,omitempty"`
+	ExistingPos        *int    `json:"existing_pos,omitempty"`
+	ExistingTargetText string  `json:"existing_target_text"`
+}
+
+// Written as a file download rather than as JSON.
+type ExportTermsReply struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
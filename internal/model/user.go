@@ -12,6 +12,8 @@ type UserInfo struct {
 	AssignedUploaderAt    *int64 `json:"assigned_uploader_at"`
 	IsAdmin               bool   `json:"is_admin"`
 	CreatedAt             int64  `json:"created_at"`
+
+	Tags []TagBrief `json:"tags"`
 }
 
 type LoginArgs struct {
@@ -19,10 +21,44 @@ type LoginArgs struct {
 	Password string `json:"password"`
 	Nickname string `json:"nickname,omitempty"`
 	InvCode  string `json:"invitation_code,omitempty"`
+
+	// Device name shown in the session list, the User-Agent if empty.
+	Device string `json:"device,omitempty"`
+	// Filled from the request.
+	IP string `json:"-"`
 }
 
 type LoginReply struct {
+	// Short-lived access token.
 	Token string `json:"token"`
+	// Unix timestamp when Token expires.
+	ExpiresAt int64 `json:"expires_at"`
+
+	// Single-use token to get a new pair when Token expires.
+	RefreshToken string `json:"refresh_token"`
+	// Unix timestamp when RefreshToken expires.
+	RefreshExpiresAt int64 `json:"refresh_expires_at"`
+
+	SessionID string `json:"session_id"`
+}
+
+type RefreshSessionArgs struct {
+	RefreshToken string `json:"refresh_token"`
+
+	// Filled from the request.
+	IP string `json:"-"`
+}
+
+type SessionInfo struct {
+	ID         string `json:"id"`
+	Device     string `json:"device"`
+	IP         string `json:"ip"`
+	CreatedAt  int64  `json:"created_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+
+	// Whether it is the session of the requesting token.
+	Current bool `json:"current"`
 }
 
 type UpdateUserArgs struct {
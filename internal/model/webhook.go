@@ -0,0 +1,73 @@
+package model
+
+import "encoding/json"
+
+// The secret is left out, being shown only when the webhook is created.
+type WebhookInfo struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+
+	// Events posted, all of them if empty.
+	Events      []string `json:"events"`
+	Description *string  `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+
+	CreatorID *string `json:"creator_id,omitempty"`
+	CreatedAt int64   `json:"created_at"`
+	UpdatedAt int64   `json:"updated_at"`
+}
+
+type CreateWebhookArgs struct {
+	URL string `json:"url"`
+
+	// Generated if not given.
+	Secret *string `json:"secret,omitempty"`
+
+	// Events posted, all of them if empty.
+	Events      []string `json:"events"`
+	Description *string  `json:"description,omitempty"`
+}
+
+type CreateWebhookReply struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+type UpdateWebhookArgs struct {
+	ID          string    `json:"id"`
+	URL         *string   `json:"url,omitempty"`
+	Secret      *string   `json:"secret,omitempty"`
+	Events      *[]string `json:"events,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+}
+
+type RetrieveWebhookOpt struct {
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
+
+type WebhookDeliveryInfo struct {
+	ID        string          `json:"id"`
+	WebhookID string          `json:"webhook_id"`
+	Event     string          `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+
+	// One of pending, succeeded and failed.
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt *int64 `json:"next_attempt_at,omitempty"`
+
+	LastStatusCode *int    `json:"last_status_code,omitempty"`
+	LastError      *string `json:"last_error,omitempty"`
+	DeliveredAt    *int64  `json:"delivered_at,omitempty"`
+
+	CreatedAt int64 `json:"created_at"`
+}
+
+type RetrieveWebhookDeliveryOpt struct {
+	Status *string `url:"status,omitempty"`
+
+	Offset int `url:"offset"`
+	Limit  int `url:"limit"`
+}
@@ -0,0 +1,123 @@
+package onebot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type httpClient struct {
+	client *http.Client
+
+	apiURL      string
+	accessToken string
+	secret      string
+}
+
+// NewHTTPClient creates a Client calling the HTTP API at apiURL.
+// ONEBOT_ACCESS_TOKEN, if set, is sent along every action.
+// ONEBOT_SECRET must be set, as posted events act on behalf of members
+// and are only trusted if signed with it.
+func NewHTTPClient(apiURL string, timeout time.Duration) Client {
+	if apiURL == "" {
+		panic("apiURL cannot be empty")
+	}
+
+	secret := os.Getenv("ONEBOT_SECRET")
+	if secret == "" {
+		panic("ONEBOT_SECRET environment variable is not set")
+	}
+
+	return &httpClient{
+		client:      &http.Client{Timeout: timeout},
+		apiURL:      strings.TrimRight(apiURL, "/"),
+		accessToken: os.Getenv("ONEBOT_ACCESS_TOKEN"),
+		secret:      secret,
+	}
+}
+
+func (c *httpClient) SendPrivateMsg(ctx context.Context, userID int64, message string) error {
+	return c.call(ctx, "send_private_msg", map[string]any{
+		"user_id":     userID,
+		"message":     message,
+		"auto_escape": true,
+	})
+}
+
+func (c *httpClient) SendGroupMsg(ctx context.Context, groupID int64, message string) error {
+	return c.call(ctx, "send_group_msg", map[string]any{
+		"group_id":    groupID,
+		"message":     message,
+		"auto_escape": true,
+	})
+}
+
+// VerifyEvent checks signature, in the form "sha1=<hex>", against the HMAC-SHA1 of body.
+// Nothing passes without a secret.
+func (c *httpClient) VerifyEvent(body []byte, signature string) bool {
+	if c.secret == "" {
+		return false
+	}
+
+	mac := hmac.New(sha1.New, []byte(c.secret))
+	mac.Write(body)
+
+	want := "sha1=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(signature), []byte(want))
+}
+
+// actionResp is the response of the HTTP API.
+type actionResp struct {
+	// ok, async or failed.
+	Status  string `json:"status"`
+	RetCode int    `json:"retcode"`
+	Wording string `json:"wording"`
+}
+
+// call calls an action with params, failing unless the bot reports it done or accepted.
+func (c *httpClient) call(ctx context.Context, action string, params any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params of %s: %w", action, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/"+action, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request of %s: %w", action, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call %s: unexpected status %d", action, resp.StatusCode)
+	}
+
+	var ar actionResp
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", action, err)
+	}
+
+	if ar.Status != "ok" && ar.Status != "async" {
+		return fmt.Errorf("failed to call %s: retcode %d %s", action, ar.RetCode, ar.Wording)
+	}
+
+	return nil
+}
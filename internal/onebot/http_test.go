@@ -0,0 +1,51 @@
+package onebot
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewHTTPClientRequiresSecret(t *testing.T) {
+	t.Setenv("ONEBOT_SECRET", "")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("client created without ONEBOT_SECRET")
+		}
+	}()
+
+	NewHTTPClient("http://127.0.0.1:5700", time.Second)
+}
+
+func TestVerifyEvent(t *testing.T) {
+	body := []byte(`{"post_type":"message","message_type":"group","user_id":10001,"raw_message":"/领取 task-1"}`)
+
+	t.Setenv("ONEBOT_SECRET", "secret")
+	c := NewHTTPClient("http://127.0.0.1:5700", time.Second)
+
+	if !c.VerifyEvent(body, sign("secret", body)) {
+		t.Fatal("signed event rejected")
+	}
+	for _, sig := range []string{"", sign("other", body), sign("", body)} {
+		if c.VerifyEvent(body, sig) {
+			t.Fatalf("event accepted with signature %q", sig)
+		}
+	}
+
+	// Without a secret, unsigned events and those signed with an empty key are rejected too.
+	noSecret := &httpClient{}
+	for _, sig := range []string{"", sign("", body)} {
+		if noSecret.VerifyEvent(body, sig) {
+			t.Fatalf("event accepted without a secret, signature %q", sig)
+		}
+	}
+}
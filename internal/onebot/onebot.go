@@ -0,0 +1,46 @@
+// Package onebot talks to QQ bots implementing OneBot v11 over HTTP.
+// Actions are called through the HTTP API of the bot,
+// and events are posted to us by its HTTP POST reporting.
+package onebot
+
+import "context"
+
+type Client interface {
+	SendPrivateMsg(ctx context.Context, userID int64, message string) error
+	SendGroupMsg(ctx context.Context, groupID int64, message string) error
+
+	// VerifyEvent checks the X-Signature header of a posted event.
+	VerifyEvent(body []byte, signature string) bool
+}
+
+// Types of posted events and messages.
+const (
+	POST_TYPE_MESSAGE = "message"
+
+	MESSAGE_TYPE_GROUP   = "group"
+	MESSAGE_TYPE_PRIVATE = "private"
+)
+
+// Event is a posted event. Only the fields of message events are kept.
+type Event struct {
+	Time     int64  `json:"time"`
+	SelfID   int64  `json:"self_id"`
+	PostType string `json:"post_type"`
+
+	MessageType string `json:"message_type"`
+	SubType     string `json:"sub_type"`
+	MessageID   int64  `json:"message_id"`
+	GroupID     int64  `json:"group_id"`
+	UserID      int64  `json:"user_id"`
+
+	// The message in CQ code.
+	RawMessage string `json:"raw_message"`
+}
+
+// QuickReply answers a message event, sent back as the response to its post.
+type QuickReply struct {
+	Reply string `json:"reply"`
+
+	// Mention the sender, in groups only.
+	AtSender bool `json:"at_sender"`
+}
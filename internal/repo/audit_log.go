@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+)
+
+// AuditLogRepo defines repository operations for the audit log.
+// Entries are append-only.
+type AuditLogRepo interface {
+	Repo
+
+	CreateAuditLog(ex Exct, newLog *po.NewAuditLog) error
+
+	RetrieveAuditLogs(ex Exct, opt model.RetrieveAuditLogOpt) ([]po.BasicAuditLog, error)
+}
+
+type auditLogRepo struct {
+	ex Exct
+}
+
+func NewAuditLogRepo(ex Exct) AuditLogRepo {
+	return &auditLogRepo{ex: ex}
+}
+
+func (alr *auditLogRepo) Exct() Exct { return alr.ex }
+
+func (alr *auditLogRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return alr.ex
+}
+
+func (alr *auditLogRepo) CreateAuditLog(ex Exct, newLog *po.NewAuditLog) error {
+	ex = alr.withTrx(ex)
+
+	if err := ex.Create(newLog).Error; err != nil {
+		return fmt.Errorf("Failed to create audit log: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveAuditLogs returns entries matching opt, newest first.
+func (alr *auditLogRepo) RetrieveAuditLogs(ex Exct, opt model.RetrieveAuditLogOpt) ([]po.BasicAuditLog, error) {
+	ex = alr.withTrx(ex)
+
+	query := ex.Model(&po.BasicAuditLog{})
+
+	if opt.ActorID != nil {
+		query = query.Where("actor_id = ?", *opt.ActorID)
+	}
+
+	if opt.Action != nil {
+		query = query.Where("action = ?", *opt.Action)
+	}
+
+	if opt.EntityType != nil {
+		query = query.Where("entity_type = ?", *opt.EntityType)
+	}
+
+	if opt.EntityID != nil {
+		query = query.Where("entity_id = ?", *opt.EntityID)
+	}
+
+	if opt.From != nil {
+		query = query.Where("created_at >= ?", time.Unix(*opt.From, 0))
+	}
+
+	if opt.To != nil {
+		query = query.Where("created_at < ?", time.Unix(*opt.To, 0))
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicAuditLog
+
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve audit logs: %w", err)
+	}
+
+	return lst, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/model/po"
@@ -18,9 +19,10 @@ type ComicRepo interface {
 	GetComicsByWorksetID(ex Exct, worksetID string, offset, limit int) ([]po.BriefComic, error)
 	RetrieveComics(ex Exct, opt model.RetrieveComicOpt) ([]po.BriefComic, error)
 
-	CreateComic(newComic *po.NewComic) error
+	CreateComic(ex Exct, newComic *po.NewComic) error
 
 	UpdateComicByID(ex Exct, patchComic *po.PatchComic) error
+	UpdateComicStageByID(ex Exct, patchComic *po.PatchComic, fromStage string) error
 
 	DeleteComicByID(ex Exct, comicID string) error
 }
@@ -43,8 +45,10 @@ func (cr *comicRepo) withTrx(tx Exct) Exct {
 	return cr.ex
 }
 
-func (cr *comicRepo) CreateComic(newComic *po.NewComic) error {
-	if err := cr.Exct().Transaction(func(ex Exct) error {
+// CreateComic creates a comic and counts it in its workset.
+// Pass a transaction as ex to create the comic along with other changes.
+func (cr *comicRepo) CreateComic(ex Exct, newComic *po.NewComic) error {
+	if err := cr.withTrx(ex).Transaction(func(ex Exct) error {
 		// Query workset index
 		var workset po.DetailedWorkset
 		if err := ex.Model(&po.DetailedWorkset{}).
@@ -103,6 +107,9 @@ func (cr *comicRepo) GetComicByID(ex Exct, comicID string) (*po.BasicComic, erro
 		Where("comic_tbl.id = ?", comicID).
 		First(c).
 		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
 		return nil, fmt.Errorf("Failed to get comic by ID: %w", err)
 	}
 
@@ -156,6 +163,47 @@ func (cr *comicRepo) UpdateComicByID(ex Exct, patchComic *po.PatchComic) error {
 
 	ex = cr.withTrx(ex)
 
+	updates := patchComicUpdates(patchComic)
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return ex.Model(&po.PatchComic{}).
+		Where("id = ?", patchComic.ID).
+		Updates(updates).
+		Error
+}
+
+// UpdateComicStageByID applies a patch carrying a stage change,
+// provided the comic is still at fromStage.
+// REC_NOT_FOUND is returned if it is not, or if the comic does not exist.
+func (cr *comicRepo) UpdateComicStageByID(ex Exct, patchComic *po.PatchComic, fromStage string) error {
+	if patchComic.ID == "" {
+		return errors.New("comic ID is required for update")
+	}
+
+	ex = cr.withTrx(ex)
+
+	updates := patchComicUpdates(patchComic)
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	result := ex.Model(&po.PatchComic{}).
+		Where("id = ? AND stage = ?", patchComic.ID, fromStage).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("Failed to update comic stage: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// patchComicUpdates converts the set fields of a patch to column updates.
+func patchComicUpdates(patchComic *po.PatchComic) map[string]any {
 	updates := map[string]any{}
 
 	if patchComic.Author != nil {
@@ -170,6 +218,9 @@ func (cr *comicRepo) UpdateComicByID(ex Exct, patchComic *po.PatchComic) error {
 	if patchComic.Description != nil {
 		updates["description"] = *patchComic.Description
 	}
+	if patchComic.Stage != nil {
+		updates["stage"] = *patchComic.Stage
+	}
 
 	if patchComic.TranslatingStartedAt != nil {
 		if patchComic.TranslatingStartedAt.IsZero() {
@@ -232,14 +283,7 @@ func (cr *comicRepo) UpdateComicByID(ex Exct, patchComic *po.PatchComic) error {
 		}
 	}
 
-	if len(updates) == 0 {
-		return nil
-	}
-
-	return ex.Model(&po.PatchComic{}).
-		Where("id = ?", patchComic.ID).
-		Updates(updates).
-		Error
+	return updates
 }
 
 // RetrieveComics returns a slice of BriefComic with filtering and pagination.
@@ -311,6 +355,10 @@ func (cr *comicRepo) RetrieveComics(ex Exct, opt model.RetrieveComicOpt) ([]po.B
 		query = query.Where("comic_tbl.uploading_completed_at IS NOT NULL")
 	}
 
+	if len(opt.Stages) > 0 {
+		query = query.Where("comic_tbl.stage IN ?", opt.Stages)
+	}
+
 	if opt.AssignedUserID != nil {
 		query = query.Where(`EXISTS (
 			SELECT 1 FROM comic_assignment_tbl 
@@ -319,6 +367,33 @@ func (cr *comicRepo) RetrieveComics(ex Exct, opt model.RetrieveComicOpt) ([]po.B
 		)`, *opt.AssignedUserID)
 	}
 
+	// Tag filters go through idx_comic_tag_tag_id.
+	if len(opt.AnyTagIDs) > 0 {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM comic_tag_tbl
+			WHERE comic_tag_tbl.comic_id = comic_tbl.id
+			AND comic_tag_tbl.tag_id IN ?
+		)`, opt.AnyTagIDs)
+	}
+
+	// Duplicates would make the HAVING count unreachable.
+	if allTagIDs := slices.Compact(slices.Sorted(slices.Values(opt.AllTagIDs))); len(allTagIDs) > 0 {
+		query = query.Where(`comic_tbl.id IN (
+			SELECT comic_tag_tbl.comic_id FROM comic_tag_tbl
+			WHERE comic_tag_tbl.tag_id IN ?
+			GROUP BY comic_tag_tbl.comic_id
+			HAVING COUNT(*) = ?
+		)`, allTagIDs, len(allTagIDs))
+	}
+
+	if len(opt.ExcludedTagIDs) > 0 {
+		query = query.Where(`NOT EXISTS (
+			SELECT 1 FROM comic_tag_tbl
+			WHERE comic_tag_tbl.comic_id = comic_tbl.id
+			AND comic_tag_tbl.tag_id IN ?
+		)`, opt.ExcludedTagIDs)
+	}
+
 	if opt.Offset > 0 {
 		query = query.Offset(opt.Offset)
 	}
@@ -327,8 +402,14 @@ func (cr *comicRepo) RetrieveComics(ex Exct, opt model.RetrieveComicOpt) ([]po.B
 		query = query.Limit(opt.Limit)
 	}
 
+	if opt.Sort != nil && *opt.Sort == model.COMIC_SORT_LIKES {
+		// Matches idx_comic_likes_count_desc_updated_at_desc.
+		query = query.Order("comic_tbl.likes_count DESC, comic_tbl.updated_at DESC")
+	} else {
+		query = query.Order("comic_tbl.updated_at DESC")
+	}
+
 	if err := query.
-		Order("comic_tbl.updated_at DESC").
 		Find(&lst).
 		Error; err != nil {
 		return nil, fmt.Errorf("Failed to retrieve comics: %w", err)
@@ -338,7 +419,7 @@ func (cr *comicRepo) RetrieveComics(ex Exct, opt model.RetrieveComicOpt) ([]po.B
 }
 
 func (cr *comicRepo) DeleteComicByID(ex Exct, comicID string) error {
-	return cr.Exct().Transaction(func(tx Exct) error {
+	return cr.withTrx(ex).Transaction(func(tx Exct) error {
 		// Get comic first to get workset_id
 		comic := &po.BasicComic{}
 		if err := tx.Where("id = ?", comicID).First(comic).Error; err != nil {
@@ -348,6 +429,11 @@ func (cr *comicRepo) DeleteComicByID(ex Exct, comicID string) error {
 			return fmt.Errorf("Failed to get comic for deletion: %w", err)
 		}
 
+		// comic_tag_tbl references comic_tbl without cascading.
+		if err := tx.Where("comic_id = ?", comicID).Delete(&po.NewComicTag{}).Error; err != nil {
+			return fmt.Errorf("Failed to detach tags from comic: %w", err)
+		}
+
 		// Delete the comic
 		if err := tx.Where("id = ?", comicID).Delete(&po.BasicComic{}).Error; err != nil {
 			return fmt.Errorf("Failed to delete comic: %w", err)
@@ -3,8 +3,14 @@ package repo
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
 )
 
 // ComicAsgnRepo defines repository operations for comic assignments.
@@ -15,12 +21,16 @@ type ComicAsgnRepo interface {
 	GetAsgnsByComicID(ex Exct, comicID string, offset, limit int) ([]po.BasicComicAsgn, error)
 	GetAsgnsByUserID(ex Exct, userID string, offset, limit int) ([]po.BasicComicAsgn, error)
 	GetAsgnsByUserAndComicID(ex Exct, userID, comicID string) (*po.BasicComicAsgn, error)
+	RetrieveAsgns(ex Exct, opt model.RetrieveAsgnOpt, openStages map[string][]string, now time.Time) ([]po.BasicComicAsgn, error)
+	GetDueAsgnsByUserID(ex Exct, userID string, openStages map[string][]string, until time.Time) ([]po.DueComicAsgn, error)
 
 	CreateAsgn(ex Exct, newAssign *po.NewComicAsgn) error
 
 	UpdateAsgnByID(ex Exct, patchAssign *po.PatchComicAsgn) error
 
 	DeleteAsgnByID(ex Exct, assignmentID string) error
+
+	MarkStalledAsgns(ex Exct, activeStages map[string][]string, idleSince time.Time, now time.Time) (flagged, cleared int64, err error)
 }
 
 type comicAsgnRepo struct {
@@ -59,6 +69,9 @@ func (car *comicAsgnRepo) GetAsgnByID(ex Exct, assignmentID string) (*po.BasicCo
 		Where(po.COMIC_ASSIGNMENT_TABLE+".id = ?", assignmentID).
 		First(a).
 		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
 		return nil, fmt.Errorf("Failed to get assignment by ID: %w", err)
 	}
 
@@ -133,6 +146,9 @@ func (car *comicAsgnRepo) GetAsgnsByUserAndComicID(ex Exct, userID, comicID stri
 		Where(po.COMIC_ASSIGNMENT_TABLE+".user_id = ? AND "+po.COMIC_ASSIGNMENT_TABLE+".comic_id = ?", userID, comicID).
 		First(a).
 		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
 		return nil, fmt.Errorf("Failed to get assignment by user ID and comic ID: %w", err)
 	}
 
@@ -195,10 +211,37 @@ func (car *comicAsgnRepo) UpdateAsgnByID(ex Exct, patchAssign *po.PatchComicAsgn
 		}
 	}
 
+	for col, dueAt := range map[string]*time.Time{
+		"translator_due_at":  patchAssign.TranslatorDueAt,
+		"proofreader_due_at": patchAssign.ProofreaderDueAt,
+		"typesetter_due_at":  patchAssign.TypesetterDueAt,
+		"redrawer_due_at":    patchAssign.RedrawerDueAt,
+		"reviewer_due_at":    patchAssign.ReviewerDueAt,
+	} {
+		if dueAt == nil {
+			continue
+		}
+		if dueAt.IsZero() {
+			updates[col] = nil
+		} else {
+			updates[col] = *dueAt
+		}
+	}
+
+	if patchAssign.PageRanges != nil {
+		if len(patchAssign.PageRanges) == 0 {
+			updates["page_ranges"] = nil
+		} else {
+			updates["page_ranges"] = patchAssign.PageRanges
+		}
+	}
+
 	if len(updates) == 0 {
 		return nil
 	}
 
+	updates["updated_at"] = gorm.Expr("NOW()")
+
 	return ex.Model(&po.PatchComicAsgn{}).
 		Where("id = ?", patchAssign.ID).
 		Updates(updates).
@@ -219,3 +262,196 @@ func (car *comicAsgnRepo) DeleteAsgnByID(ex Exct, assignmentID string) error {
 
 	return nil
 }
+
+// RetrieveAsgns returns assignments matching opt, most recently updated first.
+// openStages maps each role to the comic stages in which its work is not done,
+// deciding whether a role past its due date is overdue.
+func (car *comicAsgnRepo) RetrieveAsgns(
+	ex Exct,
+	opt model.RetrieveAsgnOpt,
+	openStages map[string][]string,
+	now time.Time,
+) ([]po.BasicComicAsgn, error) {
+	ex = car.withTrx(ex)
+
+	query := ex.
+		Table(po.COMIC_ASSIGNMENT_TABLE).
+		Select(po.COMIC_ASSIGNMENT_TABLE + ".*, " + po.USER_TABLE + ".nickname AS user_nickname").
+		Joins("LEFT JOIN " + po.USER_TABLE + " ON " + po.COMIC_ASSIGNMENT_TABLE + ".user_id = " + po.USER_TABLE + ".id")
+
+	if opt.ComicID != nil {
+		query = query.Where(po.COMIC_ASSIGNMENT_TABLE+".comic_id = ?", *opt.ComicID)
+	}
+
+	if opt.UserID != nil {
+		query = query.Where(po.COMIC_ASSIGNMENT_TABLE+".user_id = ?", *opt.UserID)
+	}
+
+	if opt.Overdue {
+		clause, vars, err := asgnRoleClause(openStages, "%[1]s.%[2]s_due_at < ?", now)
+		if err != nil {
+			return nil, err
+		}
+
+		query = query.
+			Joins("JOIN "+po.COMIC_TABLE+" ON "+po.COMIC_ASSIGNMENT_TABLE+".comic_id = "+po.COMIC_TABLE+".id").
+			Where(clause, vars...)
+	}
+
+	if opt.Stalled {
+		query = query.Where(po.COMIC_ASSIGNMENT_TABLE + ".stalled_at IS NOT NULL")
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicComicAsgn
+
+	if err := query.
+		Order(po.COMIC_ASSIGNMENT_TABLE + ".updated_at DESC, " + po.COMIC_ASSIGNMENT_TABLE + ".id DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve assignments: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetDueAsgnsByUserID returns the assignments of a user with a role due by until
+// whose stage, as given by openStages, the comic has not completed yet.
+func (car *comicAsgnRepo) GetDueAsgnsByUserID(
+	ex Exct,
+	userID string,
+	openStages map[string][]string,
+	until time.Time,
+) ([]po.DueComicAsgn, error) {
+	ex = car.withTrx(ex)
+
+	clause, vars, err := asgnRoleClause(openStages, "%[1]s.%[2]s_due_at <= ?", until)
+	if err != nil {
+		return nil, err
+	}
+
+	var lst []po.DueComicAsgn
+
+	if err := ex.
+		Table(po.COMIC_ASSIGNMENT_TABLE).
+		Select(po.COMIC_ASSIGNMENT_TABLE+".*, "+
+			po.USER_TABLE+".nickname AS user_nickname, "+
+			po.COMIC_TABLE+".title AS comic_title, "+
+			po.COMIC_TABLE+".stage AS comic_stage").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.COMIC_ASSIGNMENT_TABLE+".user_id = "+po.USER_TABLE+".id").
+		Joins("JOIN "+po.COMIC_TABLE+" ON "+po.COMIC_ASSIGNMENT_TABLE+".comic_id = "+po.COMIC_TABLE+".id").
+		Where(po.COMIC_ASSIGNMENT_TABLE+".user_id = ?", userID).
+		Where(clause, vars...).
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get due assignments by user ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+// MarkStalledAsgns flags the assignments with a role whose stage, as given by activeStages,
+// the comic is in, but without activity on the comic since idleSince.
+// Activity is any change to the comic, the assignment or the units of the comic.
+// Flags no longer holding are cleared.
+func (car *comicAsgnRepo) MarkStalledAsgns(
+	ex Exct,
+	activeStages map[string][]string,
+	idleSince time.Time,
+	now time.Time,
+) (flagged, cleared int64, err error) {
+	active, vars, err := asgnRoleClause(activeStages, "")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lastActivity := "GREATEST(" +
+		po.COMIC_TABLE + ".updated_at, " +
+		po.COMIC_ASSIGNMENT_TABLE + ".updated_at, " +
+		"(SELECT MAX(u.updated_at) FROM " + po.COMIC_UNIT_TABLE + " AS u" +
+		" JOIN " + po.COMIC_PAGE_TABLE + " AS p ON p.id = u.page_id" +
+		" WHERE p.comic_id = " + po.COMIC_TABLE + ".id))"
+
+	stalled := active + " AND " + lastActivity + " < ?"
+	stalledVars := append(slices.Clone(vars), idleSince)
+
+	// updated_at is left alone, as it counts as activity.
+	from := "UPDATE " + po.COMIC_ASSIGNMENT_TABLE + " SET stalled_at = %s FROM " + po.COMIC_TABLE +
+		" WHERE " + po.COMIC_TABLE + ".id = " + po.COMIC_ASSIGNMENT_TABLE + ".comic_id AND "
+
+	err = car.withTrx(ex).Transaction(func(tx Exct) error {
+		res := tx.Exec(
+			fmt.Sprintf(from, "?")+po.COMIC_ASSIGNMENT_TABLE+".stalled_at IS NULL AND "+stalled,
+			append([]any{now}, stalledVars...)...,
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		flagged = res.RowsAffected
+
+		res = tx.Exec(
+			fmt.Sprintf(from, "NULL")+po.COMIC_ASSIGNMENT_TABLE+".stalled_at IS NOT NULL AND NOT ("+stalled+")",
+			stalledVars...,
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		cleared = res.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed to mark stalled assignments: %w", err)
+	}
+
+	return flagged, cleared, nil
+}
+
+// asgnRoleClause builds a condition holding for assignments with any of the roles in roleStages
+// assigned while the comic is in one of the stages of the role, and cond holding for the role.
+// In cond, %[1]s stands for the assignment table and %[2]s for the role; args fill it per role.
+// The comic table must be joined.
+func asgnRoleClause(roleStages map[string][]string, cond string, args ...any) (string, []any, error) {
+	var (
+		clauses []string
+		vars    []any
+	)
+
+	// In a fixed order, so that statements can be cached.
+	for _, role := range po.COMIC_ASGN_ROLES {
+		stages, ok := roleStages[role]
+		if !ok {
+			continue
+		}
+
+		clause := fmt.Sprintf("%[1]s.assigned_%[2]s_at IS NOT NULL AND %[3]s.stage IN ?",
+			po.COMIC_ASSIGNMENT_TABLE, role, po.COMIC_TABLE)
+		vars = append(vars, stages)
+
+		if cond != "" {
+			clause += " AND " + fmt.Sprintf(cond, po.COMIC_ASSIGNMENT_TABLE, role)
+			vars = append(vars, args...)
+		}
+
+		clauses = append(clauses, "("+clause+")")
+	}
+
+	for role := range roleStages {
+		if !slices.Contains(po.COMIC_ASGN_ROLES, role) {
+			return "", nil, fmt.Errorf("unknown assignment role: %s", role)
+		}
+	}
+
+	if len(clauses) == 0 {
+		return "FALSE", nil, nil
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", vars, nil
+}
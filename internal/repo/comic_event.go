@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"fmt"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+)
+
+// ComicEventRepo defines repository operations for comic events.
+// Events are append-only.
+type ComicEventRepo interface {
+	Repo
+
+	CreateComicEvent(ex Exct, newEvent *po.NewComicEvent) error
+
+	GetEventsByComicID(ex Exct, comicID string, opt model.RetrieveComicEventOpt) ([]po.BasicComicEvent, error)
+}
+
+type comicEventRepo struct {
+	ex Exct
+}
+
+func NewComicEventRepo(ex Exct) ComicEventRepo {
+	return &comicEventRepo{ex: ex}
+}
+
+func (cer *comicEventRepo) Exct() Exct { return cer.ex }
+
+func (cer *comicEventRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return cer.ex
+}
+
+func (cer *comicEventRepo) CreateComicEvent(ex Exct, newEvent *po.NewComicEvent) error {
+	ex = cer.withTrx(ex)
+
+	if err := ex.Create(newEvent).Error; err != nil {
+		return fmt.Errorf("Failed to create comic event: %w", err)
+	}
+
+	return nil
+}
+
+// GetEventsByComicID returns the events of a comic matching opt, oldest first.
+func (cer *comicEventRepo) GetEventsByComicID(
+	ex Exct,
+	comicID string,
+	opt model.RetrieveComicEventOpt,
+) ([]po.BasicComicEvent, error) {
+	ex = cer.withTrx(ex)
+
+	query := ex.Model(&po.BasicComicEvent{}).
+		Select(`comic_event_tbl.*,
+			user_tbl.nickname AS actor_nickname`).
+		Joins("LEFT JOIN user_tbl ON comic_event_tbl.actor_id = user_tbl.id").
+		Where("comic_event_tbl.comic_id = ?", comicID)
+
+	if len(opt.Kinds) > 0 {
+		query = query.Where("comic_event_tbl.kind IN ?", opt.Kinds)
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicComicEvent
+
+	if err := query.
+		Order("comic_event_tbl.created_at ASC, comic_event_tbl.id ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get comic events: %w", err)
+	}
+
+	return lst, nil
+}
@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"fmt"
+
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComicLikeRepo defines repository operations for comic likes.
+// comic_tbl.likes_count is kept in sync in the same transaction,
+// and by a trigger when deleting a user removes their likes.
+type ComicLikeRepo interface {
+	Repo
+
+	GetLikedComicIDs(ex Exct, userID string, comicIDs []string) (map[string]bool, error)
+
+	LikeComic(ex Exct, userID, comicID string) error
+	UnlikeComic(ex Exct, userID, comicID string) error
+}
+
+type comicLikeRepo struct {
+	ex Exct
+}
+
+func NewComicLikeRepo(ex Exct) ComicLikeRepo {
+	return &comicLikeRepo{ex: ex}
+}
+
+func (clr *comicLikeRepo) Exct() Exct { return clr.ex }
+
+func (clr *comicLikeRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return clr.ex
+}
+
+// GetLikedComicIDs returns which of the comics are liked by the user.
+// Comics not liked are absent from the map.
+func (clr *comicLikeRepo) GetLikedComicIDs(ex Exct, userID string, comicIDs []string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	if len(comicIDs) == 0 {
+		return res, nil
+	}
+
+	ex = clr.withTrx(ex)
+
+	var liked []string
+
+	if err := ex.
+		Model(&po.NewComicLike{}).
+		Where("user_id = ? AND comic_id IN ?", userID, comicIDs).
+		Pluck("comic_id", &liked).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get liked comic IDs: %w", err)
+	}
+
+	for _, id := range liked {
+		res[id] = true
+	}
+
+	return res, nil
+}
+
+// LikeComic records that the user likes the comic and increments likes_count.
+// Liking a comic twice is a no-op.
+func (clr *comicLikeRepo) LikeComic(ex Exct, userID, comicID string) error {
+	ex = clr.withTrx(ex)
+
+	return ex.Transaction(func(tx Exct) error {
+		result := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&po.NewComicLike{UserID: userID, ComicID: comicID})
+		if result.Error != nil {
+			return fmt.Errorf("Failed to like comic: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// UpdateColumn leaves updated_at alone, likes are not edits.
+		if err := tx.Model(&po.BasicComic{}).
+			Where("id = ?", comicID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count + ?", 1)).
+			Error; err != nil {
+			return fmt.Errorf("Failed to increment comic likes_count: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// UnlikeComic removes the like of the user and decrements likes_count.
+// REC_NOT_FOUND is returned if the user does not like the comic.
+func (clr *comicLikeRepo) UnlikeComic(ex Exct, userID, comicID string) error {
+	ex = clr.withTrx(ex)
+
+	return ex.Transaction(func(tx Exct) error {
+		result := tx.
+			Where("user_id = ? AND comic_id = ?", userID, comicID).
+			Delete(&po.NewComicLike{})
+		if result.Error != nil {
+			return fmt.Errorf("Failed to unlike comic: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return REC_NOT_FOUND
+		}
+
+		if err := tx.Model(&po.BasicComic{}).
+			Where("id = ?", comicID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count - ?", 1)).
+			Error; err != nil {
+			return fmt.Errorf("Failed to decrement comic likes_count: %w", err)
+		}
+
+		return nil
+	})
+}
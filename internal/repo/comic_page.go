@@ -16,7 +16,7 @@ type ComicPageRepo interface {
 	GetCoverByComicID(ex Exct, comicID string) (*po.BasicComicPage, error)
 	GetPagesByComicID(ex Exct, comicID string) ([]po.BasicComicPage, error)
 
-	CreatePages(newPages []po.NewComicPage) error
+	CreatePages(ex Exct, newPages []po.NewComicPage) error
 
 	UpdatePageByID(ex Exct, patchPage *po.PatchComicPage) error
 
@@ -41,8 +41,8 @@ func (cpr *comicPageRepo) withTrx(tx Exct) Exct {
 	return cpr.ex
 }
 
-func (cpr *comicPageRepo) CreatePages(newPages []po.NewComicPage) error {
-	if err := cpr.Exct().Transaction(func(ex Exct) error {
+func (cpr *comicPageRepo) CreatePages(ex Exct, newPages []po.NewComicPage) error {
+	if err := cpr.withTrx(ex).Transaction(func(ex Exct) error {
 		cnt := len(newPages)
 		if cnt == 0 {
 			return nil
@@ -78,6 +78,9 @@ func (cpr *comicPageRepo) GetPageByID(ex Exct, pageID string) (*po.BasicComicPag
 		Where("id = ?", pageID).
 		First(p).
 		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
 		return nil, err
 	}
 
@@ -0,0 +1,284 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComicTaskRepo defines repository operations for comic tasks,
+// the role slots of comics open for users to claim.
+type ComicTaskRepo interface {
+	Repo
+
+	GetTaskByID(ex Exct, taskID string) (*po.BasicComicTask, error)
+	GetOpenTaskByComicAndRole(ex Exct, comicID, role string) (*po.BasicComicTask, error)
+	RetrieveTasks(ex Exct, opt model.RetrieveComicTaskOpt) ([]po.BasicComicTask, error)
+	CountOpenClaimsByUserID(ex Exct, userID string, openStages map[string][]string) (int64, error)
+
+	// LockClaimsByUserID serializes the claims of a user till the end of the transaction ex.
+	LockClaimsByUserID(ex Exct, userID string) error
+
+	CreateTask(ex Exct, newTask *po.NewComicTask) error
+
+	ClaimTask(ex Exct, taskID, userID string, now time.Time) error
+	ReleaseTask(ex Exct, taskID, claimerID string) error
+
+	DeleteTaskByID(ex Exct, taskID string) error
+}
+
+type comicTaskRepo struct {
+	ex Exct
+}
+
+func NewComicTaskRepo(ex Exct) ComicTaskRepo {
+	return &comicTaskRepo{ex: ex}
+}
+
+func (ctr *comicTaskRepo) Exct() Exct { return ctr.ex }
+
+func (ctr *comicTaskRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return ctr.ex
+}
+
+func (ctr *comicTaskRepo) baseQuery(ex Exct) *gorm.DB {
+	return ex.
+		Table(po.COMIC_TASK_TABLE).
+		Select(po.COMIC_TASK_TABLE + ".*, " +
+			po.COMIC_TABLE + ".title AS comic_title, " +
+			po.COMIC_TABLE + ".stage AS comic_stage, " +
+			po.USER_TABLE + ".nickname AS claimer_nickname").
+		Joins("JOIN " + po.COMIC_TABLE + " ON " + po.COMIC_TASK_TABLE + ".comic_id = " + po.COMIC_TABLE + ".id").
+		Joins("LEFT JOIN " + po.USER_TABLE + " ON " + po.COMIC_TASK_TABLE + ".claimed_by = " + po.USER_TABLE + ".id")
+}
+
+func (ctr *comicTaskRepo) GetTaskByID(ex Exct, taskID string) (*po.BasicComicTask, error) {
+	ex = ctr.withTrx(ex)
+
+	t := &po.BasicComicTask{}
+
+	if err := ctr.baseQuery(ex).
+		Where(po.COMIC_TASK_TABLE+".id = ?", taskID).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get task by ID: %w", err)
+	}
+
+	return t, nil
+}
+
+func (ctr *comicTaskRepo) GetOpenTaskByComicAndRole(ex Exct, comicID, role string) (*po.BasicComicTask, error) {
+	ex = ctr.withTrx(ex)
+
+	t := &po.BasicComicTask{}
+
+	if err := ctr.baseQuery(ex).
+		Where(po.COMIC_TASK_TABLE+".comic_id = ? AND "+po.COMIC_TASK_TABLE+".role = ?", comicID, role).
+		Where(po.COMIC_TASK_TABLE + ".claimed_by IS NULL").
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get open task by comic ID and role: %w", err)
+	}
+
+	return t, nil
+}
+
+// RetrieveTasks returns the tasks matching opt, newest first.
+func (ctr *comicTaskRepo) RetrieveTasks(ex Exct, opt model.RetrieveComicTaskOpt) ([]po.BasicComicTask, error) {
+	ex = ctr.withTrx(ex)
+
+	query := ctr.baseQuery(ex)
+
+	if opt.ComicID != nil {
+		query = query.Where(po.COMIC_TASK_TABLE+".comic_id = ?", *opt.ComicID)
+	}
+
+	if opt.Role != nil {
+		query = query.Where(po.COMIC_TASK_TABLE+".role = ?", *opt.Role)
+	}
+
+	if opt.Claimed != nil {
+		if *opt.Claimed {
+			query = query.Where(po.COMIC_TASK_TABLE + ".claimed_by IS NOT NULL")
+		} else {
+			query = query.Where(po.COMIC_TASK_TABLE + ".claimed_by IS NULL")
+		}
+	}
+
+	if opt.ClaimedBy != nil {
+		query = query.Where(po.COMIC_TASK_TABLE+".claimed_by = ?", *opt.ClaimedBy)
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicComicTask
+
+	if err := query.
+		Order(po.COMIC_TASK_TABLE + ".created_at DESC, " + po.COMIC_TASK_TABLE + ".id DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve tasks: %w", err)
+	}
+
+	return lst, nil
+}
+
+// CountOpenClaimsByUserID counts the tasks claimed by a user
+// whose stage, as given by openStages, the comic has not completed yet.
+func (ctr *comicTaskRepo) CountOpenClaimsByUserID(ex Exct, userID string, openStages map[string][]string) (int64, error) {
+	ex = ctr.withTrx(ex)
+
+	var (
+		clauses []string
+		vars    []any
+	)
+
+	// In a fixed order, so that statements can be cached.
+	for _, role := range po.COMIC_ASGN_ROLES {
+		stages, ok := openStages[role]
+		if !ok {
+			continue
+		}
+
+		clauses = append(clauses, "("+po.COMIC_TASK_TABLE+".role = ? AND "+po.COMIC_TABLE+".stage IN ?)")
+		vars = append(vars, role, stages)
+	}
+
+	if len(clauses) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+
+	if err := ex.
+		Table(po.COMIC_TASK_TABLE).
+		Joins("JOIN "+po.COMIC_TABLE+" ON "+po.COMIC_TASK_TABLE+".comic_id = "+po.COMIC_TABLE+".id").
+		Where(po.COMIC_TASK_TABLE+".claimed_by = ?", userID).
+		Where(strings.Join(clauses, " OR "), vars...).
+		Count(&count).
+		Error; err != nil {
+		return 0, fmt.Errorf("Failed to count open claims by user ID: %w", err)
+	}
+
+	return count, nil
+}
+
+// LockClaimsByUserID locks the row of the user, which claims of the user lock alike.
+// The lock is weaker than FOR UPDATE, so rows referencing the user may still be inserted meanwhile.
+// Returns REC_NOT_FOUND if the user does not exist.
+func (ctr *comicTaskRepo) LockClaimsByUserID(ex Exct, userID string) error {
+	ex = ctr.withTrx(ex)
+
+	if err := ex.Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Where("id = ?", userID).
+		First(&po.BasicUser{}).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return REC_NOT_FOUND
+		}
+		return fmt.Errorf("Failed to lock claims by user ID: %w", err)
+	}
+
+	return nil
+}
+
+func (ctr *comicTaskRepo) CreateTask(ex Exct, newTask *po.NewComicTask) error {
+	ex = ctr.withTrx(ex)
+
+	if err := ex.Create(newTask).Error; err != nil {
+		return fmt.Errorf("Failed to create task: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimTask claims an open task for a user.
+// Returns REC_NOT_FOUND if the task does not exist or has been claimed already.
+func (ctr *comicTaskRepo) ClaimTask(ex Exct, taskID, userID string, now time.Time) error {
+	ex = ctr.withTrx(ex)
+
+	res := ex.
+		Table(po.COMIC_TASK_TABLE).
+		Where("id = ? AND claimed_by IS NULL", taskID).
+		Updates(map[string]any{
+			"claimed_by": userID,
+			"claimed_at": now,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to claim task: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// ReleaseTask reopens a task claimed by claimerID.
+// Returns REC_NOT_FOUND if the task does not exist or is not claimed by claimerID.
+func (ctr *comicTaskRepo) ReleaseTask(ex Exct, taskID, claimerID string) error {
+	ex = ctr.withTrx(ex)
+
+	res := ex.
+		Table(po.COMIC_TASK_TABLE).
+		Where("id = ? AND claimed_by = ?", taskID, claimerID).
+		Updates(map[string]any{
+			"claimed_by": nil,
+			"claimed_at": nil,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to release task: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+func (ctr *comicTaskRepo) DeleteTaskByID(ex Exct, taskID string) error {
+	ex = ctr.withTrx(ex)
+
+	res := ex.
+		Where("id = ?", taskID).
+		Delete(&po.NewComicTask{})
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete task: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
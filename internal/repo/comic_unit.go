@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
 )
 
 // ComicUnitRepo defines repository operations for comic units.
@@ -11,6 +13,8 @@ type ComicUnitRepo interface {
 	Repo
 
 	GetUnitsByPageID(ex Exct, pageID string) ([]po.BasicComicUnit, error)
+	GetUnitsByComicID(ex Exct, comicID string) ([]po.BasicComicUnit, error)
+	GetUnitsByIDs(ex Exct, unitIDs []string) ([]po.BasicComicUnit, error)
 
 	GetUnitCountsByPageID(ex Exct, pageID string) (po.UnitCounts, error)
 
@@ -18,6 +22,7 @@ type ComicUnitRepo interface {
 
 	CreateUnits(ex Exct, newUnits []po.NewComicUnit) error
 
+	// UpdateUnitsByIDs returns REC_NOT_FOUND if a unit is not of the version expected.
 	UpdateUnitsByIDs(ex Exct, patchUnits []po.PatchComicUnit) error
 
 	DeleteUnitByIDs(ex Exct, unitIDs []string) error
@@ -66,6 +71,46 @@ func (cur *comicUnitRepo) GetUnitsByPageID(ex Exct, pageID string) ([]po.BasicCo
 	return lst, nil
 }
 
+// GetUnitsByComicID returns the units of all pages of a comic.
+func (cur *comicUnitRepo) GetUnitsByComicID(ex Exct, comicID string) ([]po.BasicComicUnit, error) {
+	ex = cur.withTrx(ex)
+
+	var lst []po.BasicComicUnit
+
+	if err := ex.
+		Model(&po.BasicComicUnit{}).
+		Select(po.COMIC_UNIT_TABLE+".*").
+		Joins("JOIN "+po.COMIC_PAGE_TABLE+" ON "+po.COMIC_PAGE_TABLE+".id = "+po.COMIC_UNIT_TABLE+".page_id").
+		Where(po.COMIC_PAGE_TABLE+".comic_id = ?", comicID).
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get units by comic ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetUnitsByIDs returns the units with the given IDs.
+// IDs with no unit are skipped.
+func (cur *comicUnitRepo) GetUnitsByIDs(ex Exct, unitIDs []string) ([]po.BasicComicUnit, error) {
+	if len(unitIDs) == 0 {
+		return nil, nil
+	}
+
+	ex = cur.withTrx(ex)
+
+	var lst []po.BasicComicUnit
+
+	if err := ex.
+		Where("id IN ?", unitIDs).
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get units by IDs: %w", err)
+	}
+
+	return lst, nil
+}
+
 func (cur *comicUnitRepo) UpdateUnitsByIDs(ex Exct, patchUnits []po.PatchComicUnit) error {
 	if len(patchUnits) == 0 {
 		return nil
@@ -122,11 +167,22 @@ func (cur *comicUnitRepo) UpdateUnitsByIDs(ex Exct, patchUnits []po.PatchComicUn
 			continue
 		}
 
-		if err := ex.Model(&po.PatchComicUnit{}).
-			Where("id = ?", patchUnit.ID).
-			Updates(updates).
-			Error; err != nil {
-			return err
+		updates["updated_at"] = gorm.Expr("NOW()")
+		updates["version"] = gorm.Expr("version + 1")
+
+		query := ex.Model(&po.PatchComicUnit{}).Where("id = ?", patchUnit.ID)
+		if patchUnit.Version != nil {
+			query = query.Where("version = ?", *patchUnit.Version)
+		}
+
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Changed by someone else in the meantime.
+		if patchUnit.Version != nil && result.RowsAffected == 0 {
+			return REC_NOT_FOUND
 		}
 	}
 
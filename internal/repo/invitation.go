@@ -1,13 +1,32 @@
 package repo
 
-import "poprako-main-server/internal/model/po"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/model/po"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// A QQ has at most one invitation that is neither consumed nor revoked.
+const uidxPendingInviteeQQ = "uidx_invitation_pending_invitee_qq"
 
 type InvitationRepo interface {
+	Repo
+
 	RetrieveInvitations(ex Exct) ([]po.BasicInvitation, error)
+	GetInvitationByID(ex Exct, invitationID string) (*po.BasicInvitation, error)
 	GetInvitationByQQ(ex Exct, inviteeQQ string) (*po.BasicInvitation, error)
 
 	CreateInvitations(ex Exct, newInvitation *po.NewInvitation) error
-	MarkInvitationAsUsed(ex Exct, invitationID string) error
+	SupersedeExpiredInvitations(ex Exct, inviteeQQ string) error
+
+	ConsumeInvitation(ex Exct, invitationID, userID string) error
+	RevokeInvitationByID(ex Exct, invitationID string) error
+	RegenerateInvitationCode(ex Exct, invitationID, codeHash string, expiresAt time.Time) error
 }
 
 type invitationRepo struct {
@@ -20,7 +39,7 @@ func NewInvitationRepo(ex Exct) InvitationRepo {
 	}
 }
 
-func (ir *invitationRepo) Exec() Exct { return ir.ex }
+func (ir *invitationRepo) Exct() Exct { return ir.ex }
 
 func (ir *invitationRepo) withTrx(tx Exct) Exct {
 	if tx != nil {
@@ -30,6 +49,8 @@ func (ir *invitationRepo) withTrx(tx Exct) Exct {
 	return ir.ex
 }
 
+// RetrieveInvitations returns the invitations that are neither consumed nor revoked,
+// expired ones included so that they can be regenerated.
 func (ir *invitationRepo) RetrieveInvitations(
 	ex Exct,
 ) (
@@ -41,7 +62,7 @@ func (ir *invitationRepo) RetrieveInvitations(
 	var invitations []po.BasicInvitation
 
 	if err := ex.
-		Where("pending = ?", true).
+		Where("pending = ? AND revoked_at IS NULL", true).
 		Order("created_at DESC").
 		Find(&invitations).
 		Error; err != nil {
@@ -51,9 +72,11 @@ func (ir *invitationRepo) RetrieveInvitations(
 	return invitations, nil
 }
 
-func (ir *invitationRepo) GetInvitationByQQ(
+// GetInvitationByID returns an invitation in any state.
+// REC_NOT_FOUND is returned if no invitation is found.
+func (ir *invitationRepo) GetInvitationByID(
 	ex Exct,
-	inviteeQQ string,
+	invitationID string,
 ) (
 	*po.BasicInvitation,
 	error,
@@ -62,15 +85,42 @@ func (ir *invitationRepo) GetInvitationByQQ(
 
 	var invitation po.BasicInvitation
 
-	result := ex.
-		Where("invitee_qq = ? AND pending = ?", inviteeQQ, true).
-		First(&invitation)
-	if result.Error != nil {
-		return nil, result.Error
+	if err := ex.
+		Where("id = ?", invitationID).
+		First(&invitation).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get invitation by ID: %w", err)
 	}
 
-	if result.RowsAffected == 0 {
-		return nil, REC_NOT_FOUND
+	return &invitation, nil
+}
+
+// GetInvitationByQQ returns the latest usable invitation for a QQ,
+// that is one neither consumed, revoked nor expired.
+// REC_NOT_FOUND is returned if there is none.
+func (ir *invitationRepo) GetInvitationByQQ(
+	ex Exct,
+	inviteeQQ string,
+) (
+	*po.BasicInvitation,
+	error,
+) {
+	ex = ir.withTrx(ex)
+
+	var invitation po.BasicInvitation
+
+	if err := ex.
+		Where("invitee_qq = ? AND pending = ? AND revoked_at IS NULL AND expires_at > NOW()", inviteeQQ, true).
+		Order("created_at DESC").
+		First(&invitation).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get invitation by QQ: %w", err)
 	}
 
 	return &invitation, nil
@@ -82,18 +132,100 @@ func (ir *invitationRepo) CreateInvitations(
 ) error {
 	ex = ir.withTrx(ex)
 
-	return ex.Create(newInvitation).Error
+	if err := ex.Create(newInvitation).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == uidxPendingInviteeQQ {
+			return DUPICATE_RECORD
+		}
+		return fmt.Errorf("Failed to create invitation: %w", err)
+	}
+
+	return nil
+}
+
+// SupersedeExpiredInvitations revokes the expired invitations of a QQ that are
+// neither consumed nor revoked, so that a new one can be created for it.
+func (ir *invitationRepo) SupersedeExpiredInvitations(ex Exct, inviteeQQ string) error {
+	ex = ir.withTrx(ex)
+
+	if err := ex.Model(&po.BasicInvitation{}).
+		Where("invitee_qq = ? AND pending = ? AND revoked_at IS NULL AND expires_at <= NOW()", inviteeQQ, true).
+		Updates(map[string]any{
+			"revoked_at": gorm.Expr("NOW()"),
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+		return fmt.Errorf("Failed to supersede expired invitations: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeInvitation marks a usable invitation as consumed by userID.
+// The update is conditional, so that one invitation cannot be consumed twice.
+// REC_NOT_FOUND is returned if the invitation is no longer usable.
+func (ir *invitationRepo) ConsumeInvitation(
+	ex Exct,
+	invitationID string,
+	userID string,
+) error {
+	return ir.updateUsable(ex, invitationID, map[string]any{
+		"pending":     false,
+		"consumed_at": gorm.Expr("NOW()"),
+		"consumed_by": userID,
+		"updated_at":  gorm.Expr("NOW()"),
+	}, true)
+}
+
+// RevokeInvitationByID revokes an invitation that is neither consumed nor revoked.
+// REC_NOT_FOUND is returned otherwise.
+func (ir *invitationRepo) RevokeInvitationByID(ex Exct, invitationID string) error {
+	return ir.updateUsable(ex, invitationID, map[string]any{
+		"revoked_at": gorm.Expr("NOW()"),
+		"updated_at": gorm.Expr("NOW()"),
+	}, false)
 }
 
-func (ir *invitationRepo) MarkInvitationAsUsed(
+// RegenerateInvitationCode replaces the code of an invitation that is
+// neither consumed nor revoked, expired ones included, and extends its expiry.
+// REC_NOT_FOUND is returned otherwise.
+func (ir *invitationRepo) RegenerateInvitationCode(
 	ex Exct,
 	invitationID string,
+	codeHash string,
+	expiresAt time.Time,
+) error {
+	return ir.updateUsable(ex, invitationID, map[string]any{
+		"invitation_code_hash": codeHash,
+		"expires_at":           expiresAt,
+		"updated_at":           gorm.Expr("NOW()"),
+	}, false)
+}
+
+// updateUsable updates an invitation that is neither consumed nor revoked,
+// and, if unexpired is set, not expired either.
+func (ir *invitationRepo) updateUsable(
+	ex Exct,
+	invitationID string,
+	updates map[string]any,
+	unexpired bool,
 ) error {
 	ex = ir.withTrx(ex)
 
-	return ex.
-		Model(&po.BasicInvitation{}).
-		Where("id = ?", invitationID).
-		Update("pending", false).
-		Error
+	query := ex.Model(&po.BasicInvitation{}).
+		Where("id = ? AND pending = ? AND revoked_at IS NULL", invitationID, true)
+
+	if unexpired {
+		query = query.Where("expires_at > NOW()")
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("Failed to update invitation: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
 }
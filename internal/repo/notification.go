@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"fmt"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepo defines repository operations for the notifications of users.
+type NotificationRepo interface {
+	Repo
+
+	CreateNotifications(ex Exct, newNotifications []po.NewNotification) error
+
+	RetrieveNotifications(ex Exct, userID string, opt model.RetrieveNotificationOpt) ([]po.BasicNotification, error)
+	CountUnreadByUserID(ex Exct, userID string) (int64, error)
+
+	MarkRead(ex Exct, userID, notificationID string) error
+	MarkAllRead(ex Exct, userID string) (int64, error)
+
+	ClaimUnpushed(ex Exct, kinds []string, limit int) ([]po.PushNotification, error)
+}
+
+type notificationRepo struct {
+	ex Exct
+}
+
+func NewNotificationRepo(ex Exct) NotificationRepo {
+	return &notificationRepo{ex: ex}
+}
+
+func (nr *notificationRepo) Exct() Exct { return nr.ex }
+
+func (nr *notificationRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return nr.ex
+}
+
+func (nr *notificationRepo) CreateNotifications(ex Exct, newNotifications []po.NewNotification) error {
+	if len(newNotifications) == 0 {
+		return nil
+	}
+
+	ex = nr.withTrx(ex)
+
+	if err := ex.Create(&newNotifications).Error; err != nil {
+		return fmt.Errorf("Failed to create notifications: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveNotifications returns the notifications of a user matching opt, newest first.
+func (nr *notificationRepo) RetrieveNotifications(
+	ex Exct,
+	userID string,
+	opt model.RetrieveNotificationOpt,
+) ([]po.BasicNotification, error) {
+	ex = nr.withTrx(ex)
+
+	query := ex.
+		Table(po.NOTIFICATION_TABLE).
+		Select(po.NOTIFICATION_TABLE+".*, "+
+			po.COMIC_TABLE+".title AS comic_title, "+
+			po.USER_TABLE+".nickname AS actor_nickname").
+		Joins("LEFT JOIN "+po.COMIC_TABLE+" ON "+po.NOTIFICATION_TABLE+".comic_id = "+po.COMIC_TABLE+".id").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.NOTIFICATION_TABLE+".actor_id = "+po.USER_TABLE+".id").
+		Where(po.NOTIFICATION_TABLE+".user_id = ?", userID)
+
+	if opt.Unread {
+		query = query.Where(po.NOTIFICATION_TABLE + ".read_at IS NULL")
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicNotification
+
+	if err := query.
+		Order(po.NOTIFICATION_TABLE + ".created_at DESC, " + po.NOTIFICATION_TABLE + ".id DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve notifications: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (nr *notificationRepo) CountUnreadByUserID(ex Exct, userID string) (int64, error) {
+	ex = nr.withTrx(ex)
+
+	var count int64
+
+	if err := ex.
+		Table(po.NOTIFICATION_TABLE).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).
+		Error; err != nil {
+		return 0, fmt.Errorf("Failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a notification of userID as read, keeping the time it was first read.
+// REC_NOT_FOUND is returned if userID has no such notification.
+func (nr *notificationRepo) MarkRead(ex Exct, userID, notificationID string) error {
+	ex = nr.withTrx(ex)
+
+	res := ex.
+		Table(po.NOTIFICATION_TABLE).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, NOW())"))
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to mark notification read: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// MarkAllRead marks the unread notifications of userID as read and returns how many there were.
+func (nr *notificationRepo) MarkAllRead(ex Exct, userID string) (int64, error) {
+	ex = nr.withTrx(ex)
+
+	res := ex.
+		Table(po.NOTIFICATION_TABLE).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", gorm.Expr("NOW()"))
+
+	if res.Error != nil {
+		return 0, fmt.Errorf("Failed to mark all notifications read: %w", res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
+
+// ClaimUnpushed marks up to limit notifications of kinds not yet pushed to QQ as pushed and returns them, oldest first.
+// Each is claimed once whether or not the push succeeds, so that other workers leave them alone.
+func (nr *notificationRepo) ClaimUnpushed(ex Exct, kinds []string, limit int) ([]po.PushNotification, error) {
+	ex = nr.withTrx(ex)
+
+	var lst []po.PushNotification
+
+	if err := ex.Raw(`
+		WITH claimed AS (
+			UPDATE notification_tbl SET qq_pushed_at = NOW()
+			WHERE id IN (
+				SELECT id FROM notification_tbl
+				WHERE qq_pushed_at IS NULL AND kind IN ?
+				ORDER BY created_at ASC
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT claimed.*, u.qq AS user_qq, c.title AS comic_title, a.nickname AS actor_nickname
+		FROM claimed
+		JOIN user_tbl AS u ON claimed.user_id = u.id
+		LEFT JOIN comic_tbl AS c ON claimed.comic_id = c.id
+		LEFT JOIN user_tbl AS a ON claimed.actor_id = a.id
+		ORDER BY claimed.created_at ASC, claimed.id ASC`,
+		kinds, limit,
+	).Scan(&lst).Error; err != nil {
+		return nil, fmt.Errorf("Failed to claim unpushed notifications: %w", err)
+	}
+
+	return lst, nil
+}
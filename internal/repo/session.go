@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepo defines repository operations for login sessions.
+type SessionRepo interface {
+	Repo
+
+	GetSessionByID(ex Exct, sessionID string) (*po.BasicSession, error)
+	GetSessionByRefreshHash(ex Exct, hash string) (*po.BasicSession, error)
+	GetActiveSessionsByUserID(ex Exct, userID string) ([]po.BasicSession, error)
+
+	CreateSession(ex Exct, newSession *po.NewSession) error
+
+	RotateRefreshToken(ex Exct, sessionID, oldHash, newHash, ip string, expiresAt time.Time) error
+
+	RevokeSessionByID(ex Exct, sessionID string) error
+	RevokeSessionsByUserID(ex Exct, userID string) (int64, error)
+}
+
+type sessionRepo struct {
+	ex Exct
+}
+
+func NewSessionRepo(ex Exct) SessionRepo {
+	return &sessionRepo{ex: ex}
+}
+
+func (sr *sessionRepo) Exct() Exct { return sr.ex }
+
+func (sr *sessionRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return sr.ex
+}
+
+func (sr *sessionRepo) CreateSession(ex Exct, newSession *po.NewSession) error {
+	ex = sr.withTrx(ex)
+
+	return ex.Create(newSession).Error
+}
+
+// Get session by ID, revoked and expired ones included.
+// REC_NOT_FOUND is returned if no session is found.
+func (sr *sessionRepo) GetSessionByID(ex Exct, sessionID string) (*po.BasicSession, error) {
+	ex = sr.withTrx(ex)
+
+	s := &po.BasicSession{}
+
+	if err := ex.
+		Where("id = ?", sessionID).
+		First(s).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get session by ID: %w", err)
+	}
+
+	return s, nil
+}
+
+// GetSessionByRefreshHash returns the session whose current or previous
+// refresh token hashes to hash, so that reuse of a rotated token can be told apart.
+// REC_NOT_FOUND is returned if no session is found.
+func (sr *sessionRepo) GetSessionByRefreshHash(ex Exct, hash string) (*po.BasicSession, error) {
+	ex = sr.withTrx(ex)
+
+	s := &po.BasicSession{}
+
+	if err := ex.
+		Where("refresh_token_hash = ? OR prev_refresh_token_hash = ?", hash, hash).
+		First(s).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get session by refresh token hash: %w", err)
+	}
+
+	return s, nil
+}
+
+// GetActiveSessionsByUserID returns the sessions of a user
+// that are neither revoked nor expired, most recently used first.
+func (sr *sessionRepo) GetActiveSessionsByUserID(ex Exct, userID string) ([]po.BasicSession, error) {
+	ex = sr.withTrx(ex)
+
+	var lst []po.BasicSession
+
+	if err := ex.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > NOW()", userID).
+		Order("last_used_at DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get active sessions by user ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+// RotateRefreshToken replaces the refresh token of an active session.
+// The update only happens if oldHash is still the current hash,
+// so two concurrent refreshes with the same token cannot both succeed.
+// REC_NOT_FOUND is returned if the session was rotated or revoked meanwhile.
+func (sr *sessionRepo) RotateRefreshToken(
+	ex Exct,
+	sessionID, oldHash, newHash, ip string,
+	expiresAt time.Time,
+) error {
+	ex = sr.withTrx(ex)
+
+	result := ex.Model(&po.BasicSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", sessionID, oldHash).
+		Updates(map[string]any{
+			"refresh_token_hash":      newHash,
+			"prev_refresh_token_hash": oldHash,
+			"ip":                      ip,
+			"expires_at":              expiresAt,
+			"last_used_at":            gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("Failed to rotate refresh token: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// RevokeSessionByID revokes a session.
+// REC_NOT_FOUND is returned if no unrevoked session is found.
+func (sr *sessionRepo) RevokeSessionByID(ex Exct, sessionID string) error {
+	ex = sr.withTrx(ex)
+
+	result := ex.Model(&po.BasicSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return fmt.Errorf("Failed to revoke session: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// RevokeSessionsByUserID revokes all sessions of a user
+// and returns how many were revoked.
+func (sr *sessionRepo) RevokeSessionsByUserID(ex Exct, userID string) (int64, error) {
+	ex = sr.withTrx(ex)
+
+	result := ex.Model(&po.BasicSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		return 0, fmt.Errorf("Failed to revoke sessions by user ID: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,367 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepo defines repository operations for tags
+// and their attachment to comics and users.
+type TagRepo interface {
+	Repo
+
+	GetTagByID(ex Exct, tagID string) (*po.BasicTag, error)
+	GetTagByName(ex Exct, name string) (*po.BasicTag, error)
+	RetrieveTags(ex Exct, opt model.RetrieveTagOpt) ([]po.BasicTag, error)
+
+	GetTagsByComicID(ex Exct, comicID string) ([]po.BriefTag, error)
+	GetTagsByUserIDs(ex Exct, userIDs []string) (map[string][]po.BriefTag, error)
+
+	GetTagsByPicaCandidates(ex Exct, candidates []string) ([]po.BriefTag, error)
+	GetTagsByEhentaiCandidates(ex Exct, candidates []string) ([]po.BriefTag, error)
+
+	CreateTag(ex Exct, newTag *po.NewTag) error
+
+	UpdateTagByID(ex Exct, patchTag *po.PatchTag) error
+
+	DeleteTagByID(ex Exct, tagID string) error
+
+	AttachTagsToComic(ex Exct, comicID string, tagIDs []string) error
+	DetachTagFromComic(ex Exct, comicID, tagID string) error
+
+	AttachTagsToUser(ex Exct, userID string, tagIDs []string) error
+	DetachTagFromUser(ex Exct, userID, tagID string) error
+}
+
+type tagRepo struct {
+	ex Exct
+}
+
+func NewTagRepo(ex Exct) TagRepo {
+	return &tagRepo{ex: ex}
+}
+
+func (tr *tagRepo) Exct() Exct { return tr.ex }
+
+func (tr *tagRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return tr.ex
+}
+
+func (tr *tagRepo) CreateTag(ex Exct, newTag *po.NewTag) error {
+	ex = tr.withTrx(ex)
+
+	return ex.Create(newTag).Error
+}
+
+// Get tag by ID.
+// REC_NOT_FOUND is returned if no tag is found.
+func (tr *tagRepo) GetTagByID(ex Exct, tagID string) (*po.BasicTag, error) {
+	ex = tr.withTrx(ex)
+
+	t := &po.BasicTag{}
+
+	if err := ex.
+		Model(&po.BasicTag{}).
+		Select(po.TAG_TABLE+".*, "+po.USER_TABLE+".nickname AS creator_nickname").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TAG_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TAG_TABLE+".id = ?", tagID).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get tag by ID: %w", err)
+	}
+
+	return t, nil
+}
+
+// Get tag by name.
+// REC_NOT_FOUND is returned if no tag is found.
+func (tr *tagRepo) GetTagByName(ex Exct, name string) (*po.BasicTag, error) {
+	ex = tr.withTrx(ex)
+
+	t := &po.BasicTag{}
+
+	if err := ex.
+		Model(&po.BasicTag{}).
+		Select(po.TAG_TABLE+".*, "+po.USER_TABLE+".nickname AS creator_nickname").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TAG_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TAG_TABLE+".name = ?", name).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get tag by name: %w", err)
+	}
+
+	return t, nil
+}
+
+// RetrieveTags returns a slice of BasicTag with filtering and pagination.
+// A zero-length slice is returned if no tags are found.
+func (tr *tagRepo) RetrieveTags(ex Exct, opt model.RetrieveTagOpt) ([]po.BasicTag, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTag
+
+	query := ex.
+		Model(&po.BasicTag{}).
+		Select(po.TAG_TABLE + ".*, " + po.USER_TABLE + ".nickname AS creator_nickname").
+		Joins("LEFT JOIN " + po.USER_TABLE + " ON " + po.TAG_TABLE + ".creator_id = " + po.USER_TABLE + ".id")
+
+	if opt.Name != nil {
+		query = query.Where(po.TAG_TABLE+".name LIKE ?", "%"+*opt.Name+"%")
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	if err := query.
+		Order(po.TAG_TABLE + ".name ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve tags: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetTagsByComicID returns the tags attached to a comic.
+func (tr *tagRepo) GetTagsByComicID(ex Exct, comicID string) ([]po.BriefTag, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BriefTag
+
+	if err := ex.
+		Model(&po.BriefTag{}).
+		Select(po.TAG_TABLE+".id, "+po.TAG_TABLE+".name, "+po.COMIC_TAG_TABLE+".comic_id AS owner_id").
+		Joins("JOIN "+po.COMIC_TAG_TABLE+" ON "+po.COMIC_TAG_TABLE+".tag_id = "+po.TAG_TABLE+".id").
+		Where(po.COMIC_TAG_TABLE+".comic_id = ?", comicID).
+		Order(po.TAG_TABLE + ".name ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get tags by comic ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetTagsByUserIDs returns the tags attached to each of the users.
+// Users without tags are absent from the map.
+func (tr *tagRepo) GetTagsByUserIDs(ex Exct, userIDs []string) (map[string][]po.BriefTag, error) {
+	res := make(map[string][]po.BriefTag)
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	var lst []po.BriefTag
+
+	if err := ex.
+		Model(&po.BriefTag{}).
+		Select(po.TAG_TABLE+".id, "+po.TAG_TABLE+".name, "+po.USER_TAG_TABLE+".user_id AS owner_id").
+		Joins("JOIN "+po.USER_TAG_TABLE+" ON "+po.USER_TAG_TABLE+".tag_id = "+po.TAG_TABLE+".id").
+		Where(po.USER_TAG_TABLE+".user_id IN ?", userIDs).
+		Order(po.TAG_TABLE + ".name ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get tags by user IDs: %w", err)
+	}
+
+	for _, t := range lst {
+		res[t.OwnerID] = append(res[t.OwnerID], t)
+	}
+
+	return res, nil
+}
+
+// GetTagsByPicaCandidates returns the tags whose pica_candidates
+// contain any of the candidates.
+func (tr *tagRepo) GetTagsByPicaCandidates(ex Exct, candidates []string) ([]po.BriefTag, error) {
+	return tr.getTagsByCandidates(ex, "pica_candidates", candidates)
+}
+
+// GetTagsByEhentaiCandidates returns the tags whose ehentai_candidates
+// contain any of the candidates.
+func (tr *tagRepo) GetTagsByEhentaiCandidates(ex Exct, candidates []string) ([]po.BriefTag, error) {
+	return tr.getTagsByCandidates(ex, "ehentai_candidates", candidates)
+}
+
+// getTagsByCandidates matches candidates against a candidate column with the array overlap operator.
+// column is never user input.
+func (tr *tagRepo) getTagsByCandidates(ex Exct, column string, candidates []string) ([]po.BriefTag, error) {
+	if len(candidates) == 0 {
+		return []po.BriefTag{}, nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	var lst []po.BriefTag
+
+	if err := ex.
+		Model(&po.BriefTag{}).
+		Select(po.TAG_TABLE+".id, "+po.TAG_TABLE+".name").
+		Where(po.TAG_TABLE+"."+column+" && ?::TEXT[]", po.StringArray(candidates)).
+		Order(po.TAG_TABLE + ".name ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get tags by %s: %w", column, err)
+	}
+
+	return lst, nil
+}
+
+func (tr *tagRepo) UpdateTagByID(ex Exct, patchTag *po.PatchTag) error {
+	if patchTag.ID == "" {
+		return errors.New("tag ID is required for update")
+	}
+
+	ex = tr.withTrx(ex)
+
+	updates := map[string]any{}
+
+	if patchTag.Name != nil {
+		updates["name"] = *patchTag.Name
+	}
+	if patchTag.PicaCandidates != nil {
+		updates["pica_candidates"] = *patchTag.PicaCandidates
+	}
+	if patchTag.EhentaiCandidates != nil {
+		updates["ehentai_candidates"] = *patchTag.EhentaiCandidates
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	return ex.Model(&po.PatchTag{}).
+		Where("id = ?", patchTag.ID).
+		Updates(updates).
+		Error
+}
+
+// DeleteTagByID deletes a tag and detaches it from all comics and users.
+// REC_NOT_FOUND is returned if no tag is found.
+func (tr *tagRepo) DeleteTagByID(ex Exct, tagID string) error {
+	ex = tr.withTrx(ex)
+
+	return ex.Transaction(func(tx Exct) error {
+		// comic_tag_tbl and user_tag_tbl reference tag_tbl without cascading.
+		if err := tx.Where("tag_id = ?", tagID).Delete(&po.NewComicTag{}).Error; err != nil {
+			return fmt.Errorf("Failed to detach tag from comics: %w", err)
+		}
+
+		if err := tx.Where("tag_id = ?", tagID).Delete(&po.NewUserTag{}).Error; err != nil {
+			return fmt.Errorf("Failed to detach tag from users: %w", err)
+		}
+
+		result := tx.Where("id = ?", tagID).Delete(&po.BasicTag{})
+		if result.Error != nil {
+			return fmt.Errorf("Failed to delete tag: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return REC_NOT_FOUND
+		}
+
+		return nil
+	})
+}
+
+// AttachTagsToComic attaches tags to a comic.
+// Tags already attached are ignored.
+func (tr *tagRepo) AttachTagsToComic(ex Exct, comicID string, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	rows := make([]po.NewComicTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		rows = append(rows, po.NewComicTag{ComicID: comicID, TagID: tagID})
+	}
+
+	return ex.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).
+		Error
+}
+
+// DetachTagFromComic detaches a tag from a comic.
+// REC_NOT_FOUND is returned if the tag is not attached.
+func (tr *tagRepo) DetachTagFromComic(ex Exct, comicID, tagID string) error {
+	ex = tr.withTrx(ex)
+
+	result := ex.
+		Where("comic_id = ? AND tag_id = ?", comicID, tagID).
+		Delete(&po.NewComicTag{})
+	if result.Error != nil {
+		return fmt.Errorf("Failed to detach tag from comic: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// AttachTagsToUser attaches tags to a user.
+// Tags already attached are ignored.
+func (tr *tagRepo) AttachTagsToUser(ex Exct, userID string, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	rows := make([]po.NewUserTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		rows = append(rows, po.NewUserTag{UserID: userID, TagID: tagID})
+	}
+
+	return ex.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).
+		Error
+}
+
+// DetachTagFromUser detaches a tag from a user.
+// REC_NOT_FOUND is returned if the tag is not attached.
+func (tr *tagRepo) DetachTagFromUser(ex Exct, userID, tagID string) error {
+	ex = tr.withTrx(ex)
+
+	result := ex.
+		Where("user_id = ? AND tag_id = ?", userID, tagID).
+		Delete(&po.NewUserTag{})
+	if result.Error != nil {
+		return fmt.Errorf("Failed to detach tag from user: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
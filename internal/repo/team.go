@@ -0,0 +1,213 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+)
+
+// TeamRepo defines repository operations for teams and their members.
+type TeamRepo interface {
+	Repo
+
+	GetTeamByID(ex Exct, teamID string) (*po.BasicTeam, error)
+	GetTeamByName(ex Exct, name string) (*po.BasicTeam, error)
+	RetrieveTeams(ex Exct, offset, limit int) ([]po.BasicTeam, error)
+	GetMembersByTeamIDs(ex Exct, teamIDs []string) ([]po.BasicTeamMember, error)
+
+	CreateTeam(ex Exct, newTeam *po.NewTeam) error
+	CreateMembers(ex Exct, newMembers []po.NewTeamMember) error
+
+	UpdateTeamByID(ex Exct, patchTeam *po.PatchTeam) error
+
+	DeleteTeamByID(ex Exct, teamID string) error
+	DeleteMembersByTeamID(ex Exct, teamID string) error
+}
+
+type teamRepo struct {
+	ex Exct
+}
+
+func NewTeamRepo(ex Exct) TeamRepo {
+	return &teamRepo{ex: ex}
+}
+
+func (tr *teamRepo) Exct() Exct { return tr.ex }
+
+func (tr *teamRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return tr.ex
+}
+
+func (tr *teamRepo) GetTeamByID(ex Exct, teamID string) (*po.BasicTeam, error) {
+	ex = tr.withTrx(ex)
+
+	t := &po.BasicTeam{}
+
+	if err := ex.
+		Where("id = ?", teamID).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get team by ID: %w", err)
+	}
+
+	return t, nil
+}
+
+func (tr *teamRepo) GetTeamByName(ex Exct, name string) (*po.BasicTeam, error) {
+	ex = tr.withTrx(ex)
+
+	t := &po.BasicTeam{}
+
+	if err := ex.
+		Where("name = ?", name).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get team by name: %w", err)
+	}
+
+	return t, nil
+}
+
+// RetrieveTeams returns teams ordered by name.
+func (tr *teamRepo) RetrieveTeams(ex Exct, offset, limit int) ([]po.BasicTeam, error) {
+	ex = tr.withTrx(ex)
+
+	q := ex.Model(&po.BasicTeam{}).Order("name ASC")
+
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	var lst []po.BasicTeam
+
+	if err := q.
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve teams: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (tr *teamRepo) GetMembersByTeamIDs(ex Exct, teamIDs []string) ([]po.BasicTeamMember, error) {
+	if len(teamIDs) == 0 {
+		return []po.BasicTeamMember{}, nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTeamMember
+
+	if err := ex.
+		Table(po.TEAM_MEMBER_TABLE).
+		Select(po.TEAM_MEMBER_TABLE+".*, "+po.USER_TABLE+".nickname AS user_nickname").
+		Joins("JOIN "+po.USER_TABLE+" ON "+po.TEAM_MEMBER_TABLE+".user_id = "+po.USER_TABLE+".id").
+		Where(po.TEAM_MEMBER_TABLE+".team_id IN ?", teamIDs).
+		Order(po.USER_TABLE + ".nickname ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get members by team IDs: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (tr *teamRepo) CreateTeam(ex Exct, newTeam *po.NewTeam) error {
+	ex = tr.withTrx(ex)
+
+	if err := ex.Create(newTeam).Error; err != nil {
+		return fmt.Errorf("Failed to create team: %w", err)
+	}
+
+	return nil
+}
+
+func (tr *teamRepo) CreateMembers(ex Exct, newMembers []po.NewTeamMember) error {
+	if len(newMembers) == 0 {
+		return nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	if err := ex.Create(&newMembers).Error; err != nil {
+		return fmt.Errorf("Failed to create team members: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTeamByID updates a team, bumping updated_at even if only its members changed.
+func (tr *teamRepo) UpdateTeamByID(ex Exct, patchTeam *po.PatchTeam) error {
+	if patchTeam.ID == "" {
+		return errors.New("team ID is required for update")
+	}
+
+	ex = tr.withTrx(ex)
+
+	updates := map[string]any{
+		"updated_at": gorm.Expr("NOW()"),
+	}
+
+	if patchTeam.Name != nil {
+		updates["name"] = *patchTeam.Name
+	}
+	if patchTeam.Description != nil {
+		updates["description"] = *patchTeam.Description
+	}
+
+	res := ex.Model(&po.PatchTeam{}).
+		Where("id = ?", patchTeam.ID).
+		Updates(updates)
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to update team: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+func (tr *teamRepo) DeleteTeamByID(ex Exct, teamID string) error {
+	ex = tr.withTrx(ex)
+
+	res := ex.Where("id = ?", teamID).Delete(&po.BasicTeam{})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete team: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+func (tr *teamRepo) DeleteMembersByTeamID(ex Exct, teamID string) error {
+	ex = tr.withTrx(ex)
+
+	if err := ex.Where("team_id = ?", teamID).Delete(&po.BasicTeamMember{}).Error; err != nil {
+		return fmt.Errorf("Failed to delete team members: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+)
+
+// TermRepo defines repository operations for terms in termbases.
+type TermRepo interface {
+	Repo
+
+	GetTermByID(ex Exct, termID string) (*po.BasicTerm, error)
+	RetrieveTerms(ex Exct, termbaseID string, opt model.RetrieveTermOpt) ([]po.BasicTerm, error)
+	GetTermsByComicID(ex Exct, comicID string) ([]po.BasicTerm, error)
+
+	CreateTerms(ex Exct, newTerms []po.NewTerm) error
+
+	UpdateTermByID(ex Exct, patchTerm *po.PatchTerm) error
+
+	DeleteTermByID(ex Exct, termID string) error
+}
+
+type termRepo struct {
+	ex Exct
+}
+
+func NewTermRepo(ex Exct) TermRepo {
+	return &termRepo{ex: ex}
+}
+
+func (tr *termRepo) Exct() Exct { return tr.ex }
+
+func (tr *termRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return tr.ex
+}
+
+// Create terms in batch and touch the owning termbases.
+func (tr *termRepo) CreateTerms(ex Exct, newTerms []po.NewTerm) error {
+	if len(newTerms) == 0 {
+		return nil
+	}
+
+	ex = tr.withTrx(ex)
+
+	return ex.Transaction(func(tx Exct) error {
+		// Imported glossaries may exceed the bind parameter limit of a single INSERT.
+		if err := tx.CreateInBatches(&newTerms, 500).Error; err != nil {
+			return err
+		}
+
+		termbaseIDs := make([]string, 0, 1)
+		seen := make(map[string]struct{})
+		for _, t := range newTerms {
+			if _, ok := seen[t.TermbaseID]; ok {
+				continue
+			}
+			seen[t.TermbaseID] = struct{}{}
+			termbaseIDs = append(termbaseIDs, t.TermbaseID)
+		}
+
+		return tx.Model(&po.PatchTermbase{}).
+			Where("id IN ?", termbaseIDs).
+			UpdateColumn("updated_at", gorm.Expr("NOW()")).
+			Error
+	})
+}
+
+// Get term by ID.
+// REC_NOT_FOUND is returned if no term is found.
+func (tr *termRepo) GetTermByID(ex Exct, termID string) (*po.BasicTerm, error) {
+	ex = tr.withTrx(ex)
+
+	t := &po.BasicTerm{}
+
+	if err := ex.
+		Model(&po.BasicTerm{}).
+		Select(po.TERM_TABLE+".*, "+po.USER_TABLE+".nickname AS creator_nickname").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERM_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TERM_TABLE+".id = ?", termID).
+		First(t).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get term by ID: %w", err)
+	}
+
+	return t, nil
+}
+
+// RetrieveTerms returns terms of a termbase with filtering and pagination.
+// When SourceText is given, terms are matched both by substring and by
+// trigram similarity (served by idx_trgm_term_source_text),
+// and the most similar ones come first.
+// A zero-length slice is returned if no terms are found.
+func (tr *termRepo) RetrieveTerms(ex Exct, termbaseID string, opt model.RetrieveTermOpt) ([]po.BasicTerm, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTerm
+
+	query := ex.
+		Model(&po.BasicTerm{}).
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERM_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TERM_TABLE+".termbase_id = ?", termbaseID)
+
+	if opt.SourceText != nil && *opt.SourceText != "" {
+		src := *opt.SourceText
+
+		query = query.
+			Select(po.TERM_TABLE+".*, "+po.USER_TABLE+".nickname AS creator_nickname, similarity("+po.TERM_TABLE+".source_text, ?) AS score", src).
+			Where("("+po.TERM_TABLE+".source_text ILIKE ? OR "+po.TERM_TABLE+".source_text % ?)", "%"+src+"%", src).
+			Order("score DESC")
+	} else {
+		query = query.Select(po.TERM_TABLE + ".*, " + po.USER_TABLE + ".nickname AS creator_nickname")
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	if err := query.
+		Order(po.TERM_TABLE + ".updated_at DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve terms: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetTermsByComicID returns all terms of the termbases linked to a comic.
+// A zero-length slice is returned if no terms are found.
+func (tr *termRepo) GetTermsByComicID(ex Exct, comicID string) ([]po.BasicTerm, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTerm
+
+	if err := ex.
+		Model(&po.BasicTerm{}).
+		Select(po.TERM_TABLE+".*, "+po.USER_TABLE+".nickname AS creator_nickname").
+		Joins("JOIN "+po.COMIC_TERMBASE_TABLE+" ON "+po.COMIC_TERMBASE_TABLE+".termbase_id = "+po.TERM_TABLE+".termbase_id").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERM_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.COMIC_TERMBASE_TABLE+".comic_id = ?", comicID).
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get terms by comic ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (tr *termRepo) UpdateTermByID(ex Exct, patchTerm *po.PatchTerm) error {
+	if patchTerm.ID == "" {
+		return errors.New("term ID is required for update")
+	}
+
+	ex = tr.withTrx(ex)
+
+	updates := map[string]any{}
+
+	if patchTerm.SourceText != nil {
+		updates["source_text"] = *patchTerm.SourceText
+	}
+	if patchTerm.TargetText != nil {
+		updates["target_text"] = *patchTerm.TargetText
+	}
+	if patchTerm.Note != nil {
+		updates["note"] = *patchTerm.Note
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	return ex.Model(&po.PatchTerm{}).
+		Where("id = ?", patchTerm.ID).
+		Updates(updates).
+		Error
+}
+
+func (tr *termRepo) DeleteTermByID(ex Exct, termID string) error {
+	ex = tr.withTrx(ex)
+
+	result := ex.Where("id = ?", termID).Delete(&po.BasicTerm{})
+	if result.Error != nil {
+		return fmt.Errorf("Failed to delete term: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TermbaseRepo defines repository operations for termbases.
+type TermbaseRepo interface {
+	Repo
+
+	GetTermbaseByID(ex Exct, termbaseID string) (*po.BasicTermbase, error)
+	GetTermbaseByName(ex Exct, name string) (*po.BasicTermbase, error)
+	RetrieveTermbases(ex Exct, opt model.RetrieveTermbaseOpt) ([]po.BasicTermbase, error)
+	GetTermbasesByComicID(ex Exct, comicID string) ([]po.BasicTermbase, error)
+
+	CreateTermbase(ex Exct, newTermbase *po.NewTermbase) error
+
+	UpdateTermbaseByID(ex Exct, patchTermbase *po.PatchTermbase) error
+
+	DeleteTermbaseByID(ex Exct, termbaseID string) error
+
+	LinkTermbaseToComic(ex Exct, comicID, termbaseID string) error
+	UnlinkTermbaseFromComic(ex Exct, comicID, termbaseID string) error
+}
+
+type termbaseRepo struct {
+	ex Exct
+}
+
+func NewTermbaseRepo(ex Exct) TermbaseRepo {
+	return &termbaseRepo{ex: ex}
+}
+
+func (tr *termbaseRepo) Exct() Exct { return tr.ex }
+
+func (tr *termbaseRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return tr.ex
+}
+
+// Select clause shared by termbase queries,
+// including creator nickname and term count.
+var termbaseSelectStr = fmt.Sprintf(
+	"%s.*, %s.nickname AS creator_nickname, (SELECT COUNT(*) FROM %s WHERE %s.termbase_id = %s.id) AS term_count",
+	po.TERMBASE_TABLE, po.USER_TABLE, po.TERM_TABLE, po.TERM_TABLE, po.TERMBASE_TABLE,
+)
+
+func (tr *termbaseRepo) CreateTermbase(ex Exct, newTermbase *po.NewTermbase) error {
+	ex = tr.withTrx(ex)
+
+	return ex.Create(newTermbase).Error
+}
+
+// Get termbase by ID.
+// REC_NOT_FOUND is returned if no termbase is found.
+func (tr *termbaseRepo) GetTermbaseByID(ex Exct, termbaseID string) (*po.BasicTermbase, error) {
+	ex = tr.withTrx(ex)
+
+	tb := &po.BasicTermbase{}
+
+	if err := ex.
+		Model(&po.BasicTermbase{}).
+		Select(termbaseSelectStr).
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERMBASE_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TERMBASE_TABLE+".id = ?", termbaseID).
+		First(tb).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get termbase by ID: %w", err)
+	}
+
+	return tb, nil
+}
+
+// Get termbase by name.
+// REC_NOT_FOUND is returned if no termbase is found.
+func (tr *termbaseRepo) GetTermbaseByName(ex Exct, name string) (*po.BasicTermbase, error) {
+	ex = tr.withTrx(ex)
+
+	tb := &po.BasicTermbase{}
+
+	if err := ex.
+		Model(&po.BasicTermbase{}).
+		Select(termbaseSelectStr).
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERMBASE_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.TERMBASE_TABLE+".name = ?", name).
+		First(tb).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get termbase by name: %w", err)
+	}
+
+	return tb, nil
+}
+
+// RetrieveTermbases returns a slice of BasicTermbase with filtering and pagination.
+// A zero-length slice is returned if no termbases are found.
+func (tr *termbaseRepo) RetrieveTermbases(ex Exct, opt model.RetrieveTermbaseOpt) ([]po.BasicTermbase, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTermbase
+
+	query := ex.
+		Model(&po.BasicTermbase{}).
+		Select(termbaseSelectStr).
+		Joins("LEFT JOIN " + po.USER_TABLE + " ON " + po.TERMBASE_TABLE + ".creator_id = " + po.USER_TABLE + ".id")
+
+	if opt.Name != nil {
+		query = query.Where(po.TERMBASE_TABLE+".name LIKE ?", "%"+*opt.Name+"%")
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	if err := query.
+		Order(po.TERMBASE_TABLE + ".updated_at DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve termbases: %w", err)
+	}
+
+	return lst, nil
+}
+
+// GetTermbasesByComicID returns the termbases linked to a comic.
+// A zero-length slice is returned if no termbases are linked.
+func (tr *termbaseRepo) GetTermbasesByComicID(ex Exct, comicID string) ([]po.BasicTermbase, error) {
+	ex = tr.withTrx(ex)
+
+	var lst []po.BasicTermbase
+
+	if err := ex.
+		Model(&po.BasicTermbase{}).
+		Select(termbaseSelectStr).
+		Joins("JOIN "+po.COMIC_TERMBASE_TABLE+" ON "+po.COMIC_TERMBASE_TABLE+".termbase_id = "+po.TERMBASE_TABLE+".id").
+		Joins("LEFT JOIN "+po.USER_TABLE+" ON "+po.TERMBASE_TABLE+".creator_id = "+po.USER_TABLE+".id").
+		Where(po.COMIC_TERMBASE_TABLE+".comic_id = ?", comicID).
+		Order(po.COMIC_TERMBASE_TABLE + ".created_at ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get termbases by comic ID: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (tr *termbaseRepo) UpdateTermbaseByID(ex Exct, patchTermbase *po.PatchTermbase) error {
+	if patchTermbase.ID == "" {
+		return errors.New("termbase ID is required for update")
+	}
+
+	ex = tr.withTrx(ex)
+
+	updates := map[string]any{}
+
+	if patchTermbase.Name != nil {
+		updates["name"] = *patchTermbase.Name
+	}
+	if patchTermbase.Description != nil {
+		updates["description"] = *patchTermbase.Description
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updates["updated_at"] = gorm.Expr("NOW()")
+
+	return ex.Model(&po.PatchTermbase{}).
+		Where("id = ?", patchTermbase.ID).
+		Updates(updates).
+		Error
+}
+
+// DeleteTermbaseByID deletes a termbase together with all of its terms.
+func (tr *termbaseRepo) DeleteTermbaseByID(ex Exct, termbaseID string) error {
+	ex = tr.withTrx(ex)
+
+	return ex.Transaction(func(tx Exct) error {
+		// term_tbl references termbase_tbl without cascading,
+		// so terms must be removed first.
+		if err := tx.Where("termbase_id = ?", termbaseID).Delete(&po.BasicTerm{}).Error; err != nil {
+			return fmt.Errorf("Failed to delete terms of termbase: %w", err)
+		}
+
+		result := tx.Where("id = ?", termbaseID).Delete(&po.BasicTermbase{})
+		if result.Error != nil {
+			return fmt.Errorf("Failed to delete termbase: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return REC_NOT_FOUND
+		}
+
+		return nil
+	})
+}
+
+// LinkTermbaseToComic links a termbase to a comic.
+// Linking an already linked termbase is a no-op.
+func (tr *termbaseRepo) LinkTermbaseToComic(ex Exct, comicID, termbaseID string) error {
+	ex = tr.withTrx(ex)
+
+	return ex.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&po.NewComicTermbase{ComicID: comicID, TermbaseID: termbaseID}).
+		Error
+}
+
+// UnlinkTermbaseFromComic removes the link between a termbase and a comic.
+// REC_NOT_FOUND is returned if they are not linked.
+func (tr *termbaseRepo) UnlinkTermbaseFromComic(ex Exct, comicID, termbaseID string) error {
+	ex = tr.withTrx(ex)
+
+	result := ex.
+		Where("comic_id = ? AND termbase_id = ?", comicID, termbaseID).
+		Delete(&po.NewComicTermbase{})
+	if result.Error != nil {
+		return fmt.Errorf("Failed to unlink termbase from comic: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepo defines repository operations for webhooks and their deliveries.
+type WebhookRepo interface {
+	Repo
+
+	GetWebhookByID(ex Exct, webhookID string) (*po.BasicWebhook, error)
+	RetrieveWebhooks(ex Exct, offset, limit int) ([]po.BasicWebhook, error)
+	GetActiveWebhooks(ex Exct) ([]po.BasicWebhook, error)
+
+	CreateWebhook(ex Exct, newWebhook *po.NewWebhook) error
+	UpdateWebhookByID(ex Exct, patchWebhook *po.PatchWebhook) error
+	DeleteWebhookByID(ex Exct, webhookID string) error
+
+	CreateDeliveries(ex Exct, newDeliveries []po.NewWebhookDelivery) error
+	RetrieveDeliveries(ex Exct, webhookID string, opt model.RetrieveWebhookDeliveryOpt) ([]po.BasicWebhookDelivery, error)
+	ClaimDueDeliveries(ex Exct, now time.Time, leaseUntil time.Time, limit int) ([]po.DueWebhookDelivery, error)
+	UpdateDeliveryResult(ex Exct, patchDelivery *po.PatchWebhookDelivery) error
+}
+
+type webhookRepo struct {
+	ex Exct
+}
+
+func NewWebhookRepo(ex Exct) WebhookRepo {
+	return &webhookRepo{ex: ex}
+}
+
+func (wr *webhookRepo) Exct() Exct { return wr.ex }
+
+func (wr *webhookRepo) withTrx(tx Exct) Exct {
+	if tx != nil {
+		return tx
+	}
+
+	return wr.ex
+}
+
+func (wr *webhookRepo) GetWebhookByID(ex Exct, webhookID string) (*po.BasicWebhook, error) {
+	ex = wr.withTrx(ex)
+
+	w := &po.BasicWebhook{}
+
+	if err := ex.
+		Where("id = ?", webhookID).
+		First(w).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
+		return nil, fmt.Errorf("Failed to get webhook by ID: %w", err)
+	}
+
+	return w, nil
+}
+
+// RetrieveWebhooks returns webhooks, oldest first.
+func (wr *webhookRepo) RetrieveWebhooks(ex Exct, offset, limit int) ([]po.BasicWebhook, error) {
+	ex = wr.withTrx(ex)
+
+	query := ex.Model(&po.BasicWebhook{})
+
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var lst []po.BasicWebhook
+
+	if err := query.
+		Order("created_at ASC, id ASC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve webhooks: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (wr *webhookRepo) GetActiveWebhooks(ex Exct) ([]po.BasicWebhook, error) {
+	ex = wr.withTrx(ex)
+
+	var lst []po.BasicWebhook
+
+	if err := ex.
+		Where("active").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to get active webhooks: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (wr *webhookRepo) CreateWebhook(ex Exct, newWebhook *po.NewWebhook) error {
+	ex = wr.withTrx(ex)
+
+	if err := ex.Create(newWebhook).Error; err != nil {
+		return fmt.Errorf("Failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (wr *webhookRepo) UpdateWebhookByID(ex Exct, patchWebhook *po.PatchWebhook) error {
+	if patchWebhook.ID == "" {
+		return errors.New("webhook ID is required for update")
+	}
+
+	ex = wr.withTrx(ex)
+
+	updates := map[string]any{
+		"updated_at": gorm.Expr("NOW()"),
+	}
+
+	if patchWebhook.URL != nil {
+		updates["url"] = *patchWebhook.URL
+	}
+	if patchWebhook.Secret != nil {
+		updates["secret"] = *patchWebhook.Secret
+	}
+	if patchWebhook.Events != nil {
+		updates["events"] = patchWebhook.Events
+	}
+	if patchWebhook.Description != nil {
+		updates["description"] = *patchWebhook.Description
+	}
+	if patchWebhook.Active != nil {
+		updates["active"] = *patchWebhook.Active
+	}
+
+	res := ex.Model(&po.PatchWebhook{}).
+		Where("id = ?", patchWebhook.ID).
+		Updates(updates)
+
+	if res.Error != nil {
+		return fmt.Errorf("Failed to update webhook: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+// DeleteWebhookByID deletes a webhook along with its deliveries.
+func (wr *webhookRepo) DeleteWebhookByID(ex Exct, webhookID string) error {
+	ex = wr.withTrx(ex)
+
+	res := ex.Where("id = ?", webhookID).Delete(&po.BasicWebhook{})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete webhook: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return REC_NOT_FOUND
+	}
+
+	return nil
+}
+
+func (wr *webhookRepo) CreateDeliveries(ex Exct, newDeliveries []po.NewWebhookDelivery) error {
+	if len(newDeliveries) == 0 {
+		return nil
+	}
+
+	ex = wr.withTrx(ex)
+
+	if err := ex.Create(&newDeliveries).Error; err != nil {
+		return fmt.Errorf("Failed to create webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// RetrieveDeliveries returns the deliveries of a webhook matching opt, newest first.
+func (wr *webhookRepo) RetrieveDeliveries(
+	ex Exct,
+	webhookID string,
+	opt model.RetrieveWebhookDeliveryOpt,
+) ([]po.BasicWebhookDelivery, error) {
+	ex = wr.withTrx(ex)
+
+	query := ex.Model(&po.BasicWebhookDelivery{}).
+		Where("webhook_id = ?", webhookID)
+
+	if opt.Status != nil {
+		query = query.Where("status = ?", *opt.Status)
+	}
+
+	if opt.Offset > 0 {
+		query = query.Offset(opt.Offset)
+	}
+
+	if opt.Limit > 0 {
+		query = query.Limit(opt.Limit)
+	}
+
+	var lst []po.BasicWebhookDelivery
+
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Find(&lst).
+		Error; err != nil {
+		return nil, fmt.Errorf("Failed to retrieve webhook deliveries: %w", err)
+	}
+
+	return lst, nil
+}
+
+// ClaimDueDeliveries takes up to limit pending deliveries of active webhooks due by now,
+// pushing their next attempt to leaseUntil, so that other workers leave them alone
+// and they are retried should the attempt never be recorded.
+func (wr *webhookRepo) ClaimDueDeliveries(
+	ex Exct,
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) ([]po.DueWebhookDelivery, error) {
+	ex = wr.withTrx(ex)
+
+	var lst []po.DueWebhookDelivery
+
+	if err := ex.Raw(`
+		UPDATE webhook_delivery_tbl AS d
+		SET next_attempt_at = ?, updated_at = NOW()
+		FROM webhook_tbl AS w
+		WHERE d.webhook_id = w.id AND d.id IN (
+			SELECT d2.id FROM webhook_delivery_tbl AS d2
+			JOIN webhook_tbl AS w2 ON d2.webhook_id = w2.id
+			WHERE d2.status = 'pending' AND d2.next_attempt_at <= ? AND w2.active
+			ORDER BY d2.next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE OF d2 SKIP LOCKED
+		)
+		RETURNING d.*, w.url, w.secret`,
+		leaseUntil, now, limit,
+	).Scan(&lst).Error; err != nil {
+		return nil, fmt.Errorf("Failed to claim due webhook deliveries: %w", err)
+	}
+
+	return lst, nil
+}
+
+func (wr *webhookRepo) UpdateDeliveryResult(ex Exct, patchDelivery *po.PatchWebhookDelivery) error {
+	ex = wr.withTrx(ex)
+
+	if err := ex.Model(&po.PatchWebhookDelivery{}).
+		Where("id = ?", patchDelivery.ID).
+		Updates(map[string]any{
+			"status":           patchDelivery.Status,
+			"attempts":         patchDelivery.Attempts,
+			"next_attempt_at":  patchDelivery.NextAttemptAt,
+			"last_status_code": patchDelivery.LastStatusCode,
+			"last_error":       patchDelivery.LastError,
+			"delivered_at":     patchDelivery.DeliveredAt,
+			"updated_at":       gorm.Expr("NOW()"),
+		}).
+		Error; err != nil {
+		return fmt.Errorf("Failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
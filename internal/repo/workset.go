@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"poprako-main-server/internal/model/po"
+
+	"gorm.io/gorm"
 )
 
 // WorksetRepo defines repository operations for worksets.
@@ -69,6 +71,9 @@ func (wr *worksetRepo) GetWorksetByID(ex Exct, worksetID string) (*po.DetailedWo
 		Where("workset_tbl.id = ?", worksetID).
 		First(w).
 		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, REC_NOT_FOUND
+		}
 		return nil, fmt.Errorf("Failed to get workset by ID: %w", err)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"time"
 
+	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/model/po"
 	"poprako-main-server/internal/repo"
 
@@ -103,7 +104,7 @@ func Seed(ex repo.Exct) {
 		}
 
 		// Note: workset_index will be populated by CreateComic from the repo layer
-		if err := comicRepo.CreateComic(comic); err != nil {
+		if err := comicRepo.CreateComic(nil, comic); err != nil {
 			zap.L().Error("Failed to create comic during seeding", zap.Error(err))
 			continue
 		}
@@ -123,7 +124,7 @@ func Seed(ex repo.Exct) {
 			})
 		}
 
-		if err := pageRepo.CreatePages(newPages); err != nil {
+		if err := pageRepo.CreatePages(nil, newPages); err != nil {
 			zap.L().Error("Failed to create pages during seeding", zap.Error(err))
 			continue
 		}
@@ -337,6 +338,10 @@ func updateComicProgress(ex repo.Exct, comicRepo repo.ComicRepo, prog ComicProgr
 
 	patch := &po.PatchComic{ID: prog.ComicID}
 
+	// The stage follows the furthest timestamp set.
+	stage := model.COMIC_STAGE_TRANSLATING
+	patch.Stage = &stage
+
 	translatingStart := baseTime
 	patch.TranslatingStartedAt = &translatingStart
 
@@ -344,6 +349,7 @@ func updateComicProgress(ex repo.Exct, comicRepo repo.ComicRepo, prog ComicProgr
 	if translationComplete > 0.9 {
 		translatingEnd := baseTime.Add(time.Hour * 24 * 7)
 		patch.TranslatingCompletedAt = &translatingEnd
+		stage = model.COMIC_STAGE_PROOFREADING
 
 		if prog.HasProofreader {
 			proofStart := translatingEnd.Add(time.Hour * 24)
@@ -352,6 +358,7 @@ func updateComicProgress(ex repo.Exct, comicRepo repo.ComicRepo, prog ComicProgr
 			if prog.ProofreadRate > 0.9 {
 				proofEnd := proofStart.Add(time.Hour * 24 * 5)
 				patch.ProofreadingCompletedAt = &proofEnd
+				stage = model.COMIC_STAGE_TYPESETTING
 
 				if prog.HasTypesetter {
 					typeStart := proofEnd.Add(time.Hour * 24)
@@ -360,14 +367,17 @@ func updateComicProgress(ex repo.Exct, comicRepo repo.ComicRepo, prog ComicProgr
 					if prog.TypesettingDone {
 						typeEnd := typeStart.Add(time.Hour * 24 * 3)
 						patch.TypesettingCompletedAt = &typeEnd
+						stage = model.COMIC_STAGE_REVIEWING
 
 						if prog.HasReviewer && prog.ReviewingDone {
 							reviewEnd := typeEnd.Add(time.Hour * 24 * 2)
 							patch.ReviewingCompletedAt = &reviewEnd
+							stage = model.COMIC_STAGE_UPLOADING
 
 							if rand.Float64() < 0.5 {
 								uploadEnd := reviewEnd.Add(time.Hour * 24)
 								patch.UploadingCompletedAt = &uploadEnd
+								stage = model.COMIC_STAGE_COMPLETED
 							}
 						}
 					}
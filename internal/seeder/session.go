@@ -0,0 +1,45 @@
+package seeder
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/jwtcodec"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// SeedSession starts a session for userID and returns an access token bound to it,
+// so that tools such as the integration tests can authenticate without logging in.
+// The session has no usable refresh token and expires with the access token.
+func SeedSession(ex repo.Exct, userID string, codec *jwtcodec.Codec) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("Failed to generate refresh token: %w", err)
+	}
+	sum := sha256.Sum256(buf)
+
+	sessionID := uuid.NewString()
+
+	if err := repo.NewSessionRepo(ex).CreateSession(nil, &po.NewSession{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: hex.EncodeToString(sum[:]),
+		Device:           "seeder",
+		ExpiresAt:        time.Now().Add(time.Duration(codec.ExpSecs()) * time.Second),
+	}); err != nil {
+		return "", fmt.Errorf("Failed to create session: %w", err)
+	}
+
+	token, err := codec.Encode(userID, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode access token: %w", err)
+	}
+
+	return token, nil
+}
@@ -11,16 +11,29 @@ import (
 )
 
 type AppState struct {
-	Cfg           config.AppCfg
-	JWTCodec      *jwtcodec.Codec
-	UserSvc       svc.UserSvc
-	ComicSvc      svc.ComicSvc
-	WorksetSvc    svc.WorksetSvc
-	ComicUnitSvc  svc.ComicUnitSvc
-	ComicAsgnSvc  svc.ComicAsgnSvc
-	ComicPageSvc  svc.ComicPageSvc
-	InvitationSvc svc.InvitationSvc
-	OSSClient     oss.OSSClient
+	Cfg             config.AppCfg
+	JWTCodec        *jwtcodec.Codec
+	UserSvc         svc.UserSvc
+	ComicSvc        svc.ComicSvc
+	WorksetSvc      svc.WorksetSvc
+	ComicUnitSvc    svc.ComicUnitSvc
+	ComicAsgnSvc    svc.ComicAsgnSvc
+	ComicPageSvc    svc.ComicPageSvc
+	InvitationSvc   svc.InvitationSvc
+	TermbaseSvc     svc.TermbaseSvc
+	TagSvc          svc.TagSvc
+	SessionSvc      svc.SessionSvc
+	AuthzSvc        svc.AuthzSvc
+	AuditSvc        svc.AuditSvc
+	WorkflowSvc     svc.WorkflowSvc
+	ComicEventSvc   svc.ComicEventSvc
+	ComicTaskSvc    svc.ComicTaskSvc
+	TeamSvc         svc.TeamSvc
+	NotificationSvc svc.NotificationSvc
+	WebhookSvc      svc.WebhookSvc
+	OneBotSvc       svc.OneBotSvc
+	CollabSvc       svc.CollabSvc
+	OSSClient       oss.OSSClient
 }
 
 func NewAppState(
@@ -33,18 +46,44 @@ func NewAppState(
 	comicAsgnSvc svc.ComicAsgnSvc,
 	comicPageSvc svc.ComicPageSvc,
 	invitationSvc svc.InvitationSvc,
+	termbaseSvc svc.TermbaseSvc,
+	tagSvc svc.TagSvc,
+	sessionSvc svc.SessionSvc,
+	authzSvc svc.AuthzSvc,
+	auditSvc svc.AuditSvc,
+	workflowSvc svc.WorkflowSvc,
+	comicEventSvc svc.ComicEventSvc,
+	comicTaskSvc svc.ComicTaskSvc,
+	teamSvc svc.TeamSvc,
+	notificationSvc svc.NotificationSvc,
+	webhookSvc svc.WebhookSvc,
+	oneBotSvc svc.OneBotSvc,
+	collabSvc svc.CollabSvc,
 	ossClient oss.OSSClient,
 ) AppState {
 	return AppState{
-		Cfg:           cfg,
-		JWTCodec:      jwtCodec,
-		UserSvc:       userSvc,
-		ComicSvc:      comicSvc,
-		WorksetSvc:    worksetSvc,
-		ComicUnitSvc:  comicUnitSvc,
-		ComicAsgnSvc:  comicAsgnSvc,
-		ComicPageSvc:  comicPageSvc,
-		InvitationSvc: invitationSvc,
-		OSSClient:     ossClient,
+		Cfg:             cfg,
+		JWTCodec:        jwtCodec,
+		UserSvc:         userSvc,
+		ComicSvc:        comicSvc,
+		WorksetSvc:      worksetSvc,
+		ComicUnitSvc:    comicUnitSvc,
+		ComicAsgnSvc:    comicAsgnSvc,
+		ComicPageSvc:    comicPageSvc,
+		InvitationSvc:   invitationSvc,
+		TermbaseSvc:     termbaseSvc,
+		TagSvc:          tagSvc,
+		SessionSvc:      sessionSvc,
+		AuthzSvc:        authzSvc,
+		AuditSvc:        auditSvc,
+		WorkflowSvc:     workflowSvc,
+		ComicEventSvc:   comicEventSvc,
+		ComicTaskSvc:    comicTaskSvc,
+		TeamSvc:         teamSvc,
+		NotificationSvc: notificationSvc,
+		WebhookSvc:      webhookSvc,
+		OneBotSvc:       oneBotSvc,
+		CollabSvc:       collabSvc,
+		OSSClient:       ossClient,
 	}
 }
@@ -0,0 +1,210 @@
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Entity types of audit log entries.
+const (
+	AUDIT_ENTITY_USER       = "user"
+	AUDIT_ENTITY_INVITATION = "invitation"
+	AUDIT_ENTITY_COMIC      = "comic"
+	AUDIT_ENTITY_PAGE       = "page"
+	AUDIT_ENTITY_WORKSET    = "workset"
+	AUDIT_ENTITY_ASGN       = "assignment"
+	AUDIT_ENTITY_TASK       = "task"
+	AUDIT_ENTITY_TEAM       = "team"
+	AUDIT_ENTITY_WEBHOOK    = "webhook"
+)
+
+// Actions of audit log entries.
+const (
+	AUDIT_ACTION_CREATE      = "create"
+	AUDIT_ACTION_UPDATE      = "update"
+	AUDIT_ACTION_DELETE      = "delete"
+	AUDIT_ACTION_ASSIGN_ROLE = "assign_role"
+	AUDIT_ACTION_REVOKE      = "revoke"
+	AUDIT_ACTION_REGENERATE  = "regenerate"
+	AUDIT_ACTION_TRANSITION  = "transition"
+)
+
+const (
+	defaultAuditLogLimit = 50
+	maxAuditLogLimit     = 500
+)
+
+// AuditSvc defines service operations for querying the audit log.
+// Entries are written by the audited services themselves.
+type AuditSvc interface {
+	RetrieveAuditLogs(opID string, opt model.RetrieveAuditLogOpt) (SvcRslt[[]model.AuditLogInfo], SvcErr)
+}
+
+type auditSvc struct {
+	repo     repo.AuditLogRepo
+	userRepo repo.UserRepo
+}
+
+// NewAuditSvc creates a new AuditSvc. r and ur must not be nil.
+func NewAuditSvc(r repo.AuditLogRepo, ur repo.UserRepo) AuditSvc {
+	if r == nil {
+		panic("AuditLogRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+
+	return &auditSvc{repo: r, userRepo: ur}
+}
+
+// RetrieveAuditLogs lists audit log entries matching opt, newest first.
+// Only admins are allowed.
+func (as *auditSvc) RetrieveAuditLogs(
+	opID string,
+	opt model.RetrieveAuditLogOpt,
+) (SvcRslt[[]model.AuditLogInfo], SvcErr) {
+	op, err := as.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for audit logs", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[[]model.AuditLogInfo]{}, DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to read audit logs", zap.String("userID", opID))
+		return SvcRslt[[]model.AuditLogInfo]{}, PERMISSION_DENIED
+	}
+
+	if opt.Limit <= 0 {
+		opt.Limit = defaultAuditLogLimit
+	}
+	opt.Limit = min(opt.Limit, maxAuditLogLimit)
+
+	logs, err := as.repo.RetrieveAuditLogs(nil, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve audit logs", zap.Error(err))
+		return SvcRslt[[]model.AuditLogInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.AuditLogInfo, 0, len(logs))
+	for _, l := range logs {
+		infos = append(infos, model.AuditLogInfo{
+			ID:         l.ID,
+			ActorID:    l.ActorID,
+			Action:     l.Action,
+			EntityType: l.EntityType,
+			EntityID:   l.EntityID,
+			Before:     l.Before,
+			After:      l.After,
+			IP:         l.IP,
+			UserAgent:  l.UserAgent,
+			CreatedAt:  l.CreatedAt.Unix(),
+		})
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// auditRecorder writes audit log entries on behalf of the audited services.
+type auditRecorder struct {
+	repo repo.AuditLogRepo
+}
+
+func newAuditRecorder(r repo.AuditLogRepo) *auditRecorder {
+	if r == nil {
+		panic("AuditLogRepo cannot be nil")
+	}
+
+	return &auditRecorder{repo: r}
+}
+
+// auditEntry is one audited change.
+// Before and After are snapshots of the entity, nil when it did not exist.
+type auditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   string
+
+	Before any
+	After  any
+}
+
+// record writes an entry. Pass the transaction of the change as ex,
+// so that the change is rolled back if it cannot be recorded.
+func (ar *auditRecorder) record(ex repo.Exct, opID string, meta model.ReqMeta, entry auditEntry) error {
+	id, err := genUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate audit log ID: %w", err)
+	}
+
+	before, err := auditSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := auditSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	return ar.repo.CreateAuditLog(ex, &po.NewAuditLog{
+		ID:         id,
+		ActorID:    opID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+		IP:         meta.IP,
+		UserAgent:  meta.UserAgent,
+	})
+}
+
+// auditSnapshot marshals v for the audit log.
+// Persistence objects are keyed by column name, as they appear in the database,
+// with hashed credentials left out; anything else is marshalled as is.
+func auditSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if rv.Kind() == reflect.Struct {
+		cols := map[string]any{}
+
+		for i := range rv.NumField() {
+			f := rv.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			for _, opt := range strings.Split(f.Tag.Get("gorm"), ";") {
+				if col, ok := strings.CutPrefix(opt, "column:"); ok && !strings.HasSuffix(col, "_hash") {
+					cols[col] = rv.Field(i).Interface()
+				}
+			}
+		}
+
+		if len(cols) > 0 {
+			v = cols
+		}
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+
+	return raw, nil
+}
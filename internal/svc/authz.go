@@ -0,0 +1,203 @@
+package svc
+
+import (
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// AuthzSvc resolves the facts that route policies are checked against.
+type AuthzSvc interface {
+	IsAdmin(userID string) (bool, SvcErr)
+
+	// GetQualifications returns the ROLE_* strings the user is qualified for.
+	GetQualifications(userID string) (map[string]bool, SvcErr)
+
+	GetComicAccess(userID string, comicID string) (ComicAccess, SvcErr)
+
+	GetTermbaseCreatorID(termbaseID string) (string, SvcErr)
+
+	GetComicIDByPageID(pageID string) (string, SvcErr)
+	GetComicIDByAsgnID(asgnID string) (string, SvcErr)
+}
+
+// ComicAccess describes how a user relates to a comic.
+type ComicAccess struct {
+	IsCreator bool
+
+	// ROLE_* strings the user is assigned to the comic as.
+	Roles map[string]bool
+
+	// Pages the user translates, nil for the whole comic.
+	PageRanges []model.PageRange
+}
+
+type authzSvc struct {
+	userRepo      repo.UserRepo
+	comicRepo     repo.ComicRepo
+	comicAsgnRepo repo.ComicAsgnRepo
+	comicPageRepo repo.ComicPageRepo
+	termbaseRepo  repo.TermbaseRepo
+}
+
+// NewAuthzSvc creates a new AuthzSvc. None of the repos may be nil.
+func NewAuthzSvc(
+	ur repo.UserRepo,
+	cr repo.ComicRepo,
+	car repo.ComicAsgnRepo,
+	cpr repo.ComicPageRepo,
+	tbr repo.TermbaseRepo,
+) AuthzSvc {
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+	if car == nil {
+		panic("ComicAsgnRepo cannot be nil")
+	}
+	if cpr == nil {
+		panic("ComicPageRepo cannot be nil")
+	}
+	if tbr == nil {
+		panic("TermbaseRepo cannot be nil")
+	}
+
+	return &authzSvc{
+		userRepo:      ur,
+		comicRepo:     cr,
+		comicAsgnRepo: car,
+		comicPageRepo: cpr,
+		termbaseRepo:  tbr,
+	}
+}
+
+// IsAdmin reports whether the user is an admin.
+func (as *authzSvc) IsAdmin(userID string) (bool, SvcErr) {
+	user, err := as.userRepo.GetUserByID(nil, userID)
+	if err != nil {
+		zap.L().Error("Failed to get user for authorization", zap.String("userID", userID), zap.Error(err))
+		return false, DB_FAILURE
+	}
+
+	return user.IsAdmin, NO_ERROR
+}
+
+// requireAdmin returns PERMISSION_DENIED unless the user is an admin.
+func requireAdmin(az AuthzSvc, userID string) SvcErr {
+	isAdmin, svcErr := az.IsAdmin(userID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if !isAdmin {
+		zap.L().Warn("Non-admin user attempted an admin operation", zap.String("userID", userID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// GetQualifications returns the roles the user is qualified for.
+func (as *authzSvc) GetQualifications(userID string) (map[string]bool, SvcErr) {
+	user, err := as.userRepo.GetUserByID(nil, userID)
+	if err != nil {
+		zap.L().Error("Failed to get user for authorization", zap.String("userID", userID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	return map[string]bool{
+		ROLE_TRANSLATOR:  user.AssignedTranslatorAt != nil,
+		ROLE_PROOFREADER: user.AssignedProofreaderAt != nil,
+		ROLE_TYPESETTER:  user.AssignedTypesetterAt != nil,
+		ROLE_REDRAWER:    user.AssignedRedrawerAt != nil,
+		ROLE_REVIEWER:    user.AssignedReviewerAt != nil,
+		ROLE_UPLOADER:    user.AssignedUploaderAt != nil,
+	}, NO_ERROR
+}
+
+// GetComicAccess returns whether the user created the comic
+// and which roles they are assigned to it as.
+func (as *authzSvc) GetComicAccess(userID string, comicID string) (ComicAccess, SvcErr) {
+	comic, err := as.comicRepo.GetComicByID(nil, comicID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return ComicAccess{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for authorization", zap.String("comicID", comicID), zap.Error(err))
+		return ComicAccess{}, DB_FAILURE
+	}
+
+	access := ComicAccess{
+		IsCreator: comic.CreatorID == userID,
+		Roles:     map[string]bool{},
+	}
+
+	asgn, err := as.comicAsgnRepo.GetAsgnsByUserAndComicID(nil, userID, comicID)
+	if err == repo.REC_NOT_FOUND {
+		return access, NO_ERROR
+	}
+	if err != nil {
+		zap.L().Error("Failed to get assignment for authorization",
+			zap.String("userID", userID), zap.String("comicID", comicID), zap.Error(err))
+		return ComicAccess{}, DB_FAILURE
+	}
+
+	access.Roles[ROLE_TRANSLATOR] = asgn.AssignedTranslatorAt != nil
+	access.Roles[ROLE_PROOFREADER] = asgn.AssignedProofreaderAt != nil
+	access.Roles[ROLE_TYPESETTER] = asgn.AssignedTypesetterAt != nil
+	access.Roles[ROLE_REDRAWER] = asgn.AssignedRedrawerAt != nil
+	access.Roles[ROLE_REVIEWER] = asgn.AssignedReviewerAt != nil
+
+	if access.PageRanges, err = parsePageRanges(asgn.PageRanges); err != nil {
+		zap.L().Error("Failed to parse page ranges for authorization",
+			zap.String("assignmentID", asgn.ID), zap.Error(err))
+		return ComicAccess{}, DB_FAILURE
+	}
+
+	return access, NO_ERROR
+}
+
+// GetComicIDByPageID returns the comic a page belongs to.
+func (as *authzSvc) GetComicIDByPageID(pageID string) (string, SvcErr) {
+	page, err := as.comicPageRepo.GetPageByID(nil, pageID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return "", NOT_FOUND
+		}
+		zap.L().Error("Failed to get page for authorization", zap.String("pageID", pageID), zap.Error(err))
+		return "", DB_FAILURE
+	}
+
+	return page.ComicID, NO_ERROR
+}
+
+// GetComicIDByAsgnID returns the comic an assignment belongs to.
+func (as *authzSvc) GetComicIDByAsgnID(asgnID string) (string, SvcErr) {
+	asgn, err := as.comicAsgnRepo.GetAsgnByID(nil, asgnID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return "", NOT_FOUND
+		}
+		zap.L().Error("Failed to get assignment for authorization", zap.String("assignmentID", asgnID), zap.Error(err))
+		return "", DB_FAILURE
+	}
+
+	return asgn.ComicID, NO_ERROR
+}
+
+// GetTermbaseCreatorID returns the creator of a termbase.
+func (as *authzSvc) GetTermbaseCreatorID(termbaseID string) (string, SvcErr) {
+	tb, err := as.termbaseRepo.GetTermbaseByID(nil, termbaseID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return "", NOT_FOUND
+		}
+		zap.L().Error("Failed to get termbase for authorization", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return "", DB_FAILURE
+	}
+
+	return tb.CreatorID, NO_ERROR
+}
@@ -0,0 +1,219 @@
+package svc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Types of page events.
+const (
+	PAGE_EVENT_PRESENCE      = "presence"
+	PAGE_EVENT_UNITS_CREATED = "units_created"
+	PAGE_EVENT_UNITS_UPDATED = "units_updated"
+	PAGE_EVENT_UNITS_DELETED = "units_deleted"
+)
+
+// Events buffered for each viewer. Viewers falling further behind are dropped,
+// and are expected to reconnect and reload the units.
+const pageSubBuffer = 64
+
+// CollabSvc broadcasts the changes of units to the viewers of their pages,
+// along with who are viewing them, so that members may work on a page together.
+//
+// Viewers are kept in memory, so only those connected to the same instance are reached.
+type CollabSvc interface {
+	// JoinPage subscribes opID to the events of a page until the subscription is left.
+	JoinPage(opID string, pageID string) (SvcRslt[*PageSub], SvcErr)
+
+	// PublishUnits sends an event of type about units of a page to its viewers.
+	PublishUnits(eventType string, data model.UnitsEventData)
+}
+
+// PageSub is a subscription to the events of a page.
+type PageSub struct {
+	// Closed when left, or when the viewer falls too far behind.
+	Events <-chan model.PageEvent
+
+	leave func()
+}
+
+// Leave ends the subscription. It may be called more than once.
+func (ps *PageSub) Leave() {
+	ps.leave()
+}
+
+type collabSvc struct {
+	pageRepo repo.ComicPageRepo
+	userRepo repo.UserRepo
+
+	mu sync.Mutex
+	// Viewers of each page being viewed.
+	pages map[string]map[*pageViewer]struct{}
+}
+
+type pageViewer struct {
+	userID   string
+	nickname string
+	joinedAt time.Time
+
+	events chan model.PageEvent
+}
+
+// NewCollabSvc creates a new CollabSvc. None of the repos may be nil.
+func NewCollabSvc(pr repo.ComicPageRepo, ur repo.UserRepo) CollabSvc {
+	if pr == nil {
+		panic("ComicPageRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+
+	return &collabSvc{pageRepo: pr, userRepo: ur, pages: map[string]map[*pageViewer]struct{}{}}
+}
+
+// JoinPage adds opID to the viewers of the page, telling everyone including opID who are viewing it.
+func (cs *collabSvc) JoinPage(opID string, pageID string) (SvcRslt[*PageSub], SvcErr) {
+	if _, err := cs.pageRepo.GetPageByID(nil, pageID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[*PageSub]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get page to join", zap.String("pageID", pageID), zap.Error(err))
+		return SvcRslt[*PageSub]{}, DB_FAILURE
+	}
+
+	user, err := cs.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[*PageSub]{}, USER_NOT_FOUND
+		}
+		zap.L().Error("Failed to get user to join page", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[*PageSub]{}, DB_FAILURE
+	}
+
+	viewer := &pageViewer{
+		userID:   opID,
+		nickname: user.Nickname,
+		joinedAt: time.Now(),
+		events:   make(chan model.PageEvent, pageSubBuffer),
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	viewers, ok := cs.pages[pageID]
+	if !ok {
+		viewers = map[*pageViewer]struct{}{}
+		cs.pages[pageID] = viewers
+	}
+	viewers[viewer] = struct{}{}
+
+	cs.broadcastPresence(pageID)
+
+	var once sync.Once
+
+	return accept(200, &PageSub{
+		Events: viewer.events,
+		leave:  func() { once.Do(func() { cs.leave(pageID, viewer) }) },
+	}), NO_ERROR
+}
+
+// leave removes viewer from the page, unless dropped already.
+func (cs *collabSvc) leave(pageID string, viewer *pageViewer) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if _, ok := cs.pages[pageID][viewer]; !ok {
+		return
+	}
+
+	cs.drop(pageID, viewer)
+	cs.broadcastPresence(pageID)
+}
+
+func (cs *collabSvc) PublishUnits(eventType string, data model.UnitsEventData) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.broadcast(data.PageID, model.PageEvent{Type: eventType, Data: data})
+}
+
+// broadcast sends event to the viewers of the page, dropping those whose buffers are full
+// and telling the rest. cs.mu must be held.
+func (cs *collabSvc) broadcast(pageID string, event model.PageEvent) {
+	var dropped []*pageViewer
+
+	for viewer := range cs.pages[pageID] {
+		select {
+		case viewer.events <- event:
+		default:
+			dropped = append(dropped, viewer)
+		}
+	}
+
+	if len(dropped) == 0 {
+		return
+	}
+
+	for _, viewer := range dropped {
+		zap.L().Warn("Dropped page viewer falling behind",
+			zap.String("pageID", pageID), zap.String("userID", viewer.userID))
+		cs.drop(pageID, viewer)
+	}
+
+	cs.broadcastPresence(pageID)
+}
+
+// broadcastPresence tells the viewers of the page who are viewing it. cs.mu must be held.
+func (cs *collabSvc) broadcastPresence(pageID string) {
+	viewers := cs.pages[pageID]
+	if len(viewers) == 0 {
+		return
+	}
+
+	// A user may view the page on more than one connection.
+	byUser := map[string]*model.PageViewerInfo{}
+	for viewer := range viewers {
+		if info, ok := byUser[viewer.userID]; ok {
+			info.JoinedAt = min(info.JoinedAt, viewer.joinedAt.Unix())
+			continue
+		}
+		byUser[viewer.userID] = &model.PageViewerInfo{
+			UserID:   viewer.userID,
+			Nickname: viewer.nickname,
+			JoinedAt: viewer.joinedAt.Unix(),
+		}
+	}
+
+	infos := make([]model.PageViewerInfo, 0, len(byUser))
+	for _, info := range byUser {
+		infos = append(infos, *info)
+	}
+	slices.SortFunc(infos, func(a, b model.PageViewerInfo) int {
+		return cmp.Or(cmp.Compare(a.JoinedAt, b.JoinedAt), strings.Compare(a.UserID, b.UserID))
+	})
+
+	cs.broadcast(pageID, model.PageEvent{
+		Type: PAGE_EVENT_PRESENCE,
+		Data: model.PresenceEventData{PageID: pageID, Viewers: infos},
+	})
+}
+
+// drop removes viewer from the page and closes its events. cs.mu must be held.
+func (cs *collabSvc) drop(pageID string, viewer *pageViewer) {
+	viewers := cs.pages[pageID]
+
+	delete(viewers, viewer)
+	close(viewer.events)
+
+	if len(viewers) == 0 {
+		delete(cs.pages, pageID)
+	}
+}
@@ -0,0 +1,165 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeCollabPageRepo struct{ repo.ComicPageRepo }
+
+func (fakeCollabPageRepo) GetPageByID(_ repo.Exct, pageID string) (*po.BasicComicPage, error) {
+	if pageID != "p-1" {
+		return nil, repo.REC_NOT_FOUND
+	}
+	return &po.BasicComicPage{ID: pageID, ComicID: "c-1"}, nil
+}
+
+type fakeCollabUserRepo struct{ repo.UserRepo }
+
+func (fakeCollabUserRepo) GetUserByID(_ repo.Exct, userID string) (*po.BasicUser, error) {
+	return &po.BasicUser{ID: userID, Nickname: "nick-" + userID}, nil
+}
+
+func newTestCollabSvc() CollabSvc {
+	return NewCollabSvc(fakeCollabPageRepo{}, fakeCollabUserRepo{})
+}
+
+func joinTestPage(t *testing.T, cs CollabSvc, userID string) *PageSub {
+	t.Helper()
+
+	res, svcErr := cs.JoinPage(userID, "p-1")
+	if svcErr != NO_ERROR {
+		t.Fatalf("%s joining: %v", userID, svcErr)
+	}
+	return *res.Data
+}
+
+// nextEvent receives the next event of sub, which must be buffered already.
+func nextEvent(t *testing.T, sub *PageSub) model.PageEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-sub.Events:
+		if !ok {
+			t.Fatal("events closed")
+		}
+		return event
+	default:
+		t.Fatal("no event")
+		return model.PageEvent{}
+	}
+}
+
+// viewerIDs returns the users in a presence event.
+func viewerIDs(t *testing.T, event model.PageEvent) []string {
+	t.Helper()
+
+	if event.Type != PAGE_EVENT_PRESENCE {
+		t.Fatalf("got %s event, want presence", event.Type)
+	}
+
+	var ids []string
+	for _, v := range event.Data.(model.PresenceEventData).Viewers {
+		ids = append(ids, v.UserID)
+	}
+	return ids
+}
+
+func TestCollabPresenceAndUnits(t *testing.T) {
+	cs := newTestCollabSvc()
+
+	if _, svcErr := cs.JoinPage("u-1", "p-2"); svcErr != NOT_FOUND {
+		t.Fatalf("joining missing page: %v", svcErr)
+	}
+
+	alice := joinTestPage(t, cs, "u-1")
+	if ids := viewerIDs(t, nextEvent(t, alice)); len(ids) != 1 || ids[0] != "u-1" {
+		t.Fatalf("alice sees %v", ids)
+	}
+
+	bob := joinTestPage(t, cs, "u-2")
+	// A second connection of bob is not listed twice.
+	bob2 := joinTestPage(t, cs, "u-2")
+	for _, sub := range []*PageSub{alice, bob} {
+		nextEvent(t, sub)
+	}
+	for _, sub := range []*PageSub{alice, bob, bob2} {
+		if ids := viewerIDs(t, nextEvent(t, sub)); len(ids) != 2 {
+			t.Fatalf("viewers %v, want u-1 and u-2", ids)
+		}
+	}
+
+	cs.PublishUnits(PAGE_EVENT_UNITS_UPDATED, model.UnitsEventData{PageID: "p-1", ActorID: "u-1", Units: []model.ComicUnitInfo{{ID: "unit-1"}}})
+	// Units of other pages are not sent.
+	cs.PublishUnits(PAGE_EVENT_UNITS_DELETED, model.UnitsEventData{PageID: "p-2", UnitIDs: []string{"unit-2"}})
+
+	for _, sub := range []*PageSub{alice, bob, bob2} {
+		event := nextEvent(t, sub)
+		if event.Type != PAGE_EVENT_UNITS_UPDATED || event.Data.(model.UnitsEventData).Units[0].ID != "unit-1" {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	}
+
+	bob.Leave()
+	bob.Leave()
+	if _, ok := <-bob.Events; ok {
+		t.Fatal("events of bob not closed after leaving")
+	}
+	bob2.Leave()
+
+	// Each connection closing is announced, and bob is listed until both are closed.
+	if ids := viewerIDs(t, nextEvent(t, alice)); len(ids) != 2 {
+		t.Fatalf("viewers %v after bob left once", ids)
+	}
+	if ids := viewerIDs(t, nextEvent(t, alice)); len(ids) != 1 || ids[0] != "u-1" {
+		t.Fatalf("viewers %v after bob left", ids)
+	}
+
+	alice.Leave()
+	if n := len(cs.(*collabSvc).pages); n != 0 {
+		t.Fatalf("%d pages still viewed", n)
+	}
+}
+
+func TestCollabDropsSlowViewers(t *testing.T) {
+	cs := newTestCollabSvc()
+
+	slow := joinTestPage(t, cs, "u-1")
+	fast := joinTestPage(t, cs, "u-2")
+
+	// Skip the presence of joining.
+	for _, sub := range []*PageSub{slow, slow, fast} {
+		nextEvent(t, sub)
+	}
+
+	for i := 0; i < pageSubBuffer; i++ {
+		cs.PublishUnits(PAGE_EVENT_UNITS_UPDATED, model.UnitsEventData{PageID: "p-1"})
+		// Fast keeps up.
+		for len(fast.Events) > 0 {
+			<-fast.Events
+		}
+	}
+
+	// The buffer of slow is full by now.
+	cs.PublishUnits(PAGE_EVENT_UNITS_UPDATED, model.UnitsEventData{PageID: "p-1"})
+
+	n := 0
+	for range slow.Events {
+		n++
+	}
+	if n != pageSubBuffer {
+		t.Fatalf("slow received %d events before being dropped, want %d", n, pageSubBuffer)
+	}
+
+	nextEvent(t, fast)
+	if ids := viewerIDs(t, nextEvent(t, fast)); len(ids) != 1 || ids[0] != "u-2" {
+		t.Fatalf("viewers %v after slow was dropped", ids)
+	}
+
+	// Leaving after being dropped is harmless.
+	slow.Leave()
+	fast.Leave()
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,16 +18,20 @@ import (
 	"poprako-main-server/internal/oss"
 	"poprako-main-server/internal/repo"
 	comicPkg "poprako-main-server/internal/svc/comic"
+	galleryPkg "poprako-main-server/internal/svc/gallery"
 
 	"go.uber.org/zap"
 )
 
 type ComicSvc interface {
 	GetComicInfoByID(comicID string) (SvcRslt[model.ComicInfo], SvcErr)
-	GetComicBriefsByWorksetID(worksetID string, offset, limit int) (SvcRslt[[]model.ComicBrief], SvcErr)
-	RetrieveComics(opt model.RetrieveComicOpt) (SvcRslt[[]model.ComicBrief], SvcErr)
+	GetComicBriefsByWorksetID(opID string, worksetID string, offset, limit int) (SvcRslt[[]model.ComicBrief], SvcErr)
+	RetrieveComics(opID string, opt model.RetrieveComicOpt) (SvcRslt[[]model.ComicBrief], SvcErr)
 
-	ExportComic(comicID string, exportFormat string) (SvcRslt[model.ExportComicReply], SvcErr)
+	LikeComic(opID string, comicID string) SvcErr
+	UnlikeComic(opID string, comicID string) SvcErr
+
+	ExportComic(opID string, comicID string, exportFormat string) (SvcRslt[model.ExportComicReply], SvcErr)
 	ExportBaseURI() string
 
 	ImportComic(opID string, comicID string, fileName string, reader io.Reader) SvcErr
@@ -35,7 +40,7 @@ type ComicSvc interface {
 
 	UpdateComicByID(args model.UpdateComicArgs) SvcErr
 
-	DeleteComicByID(comicID string) SvcErr
+	DeleteComicByID(opID string, meta model.ReqMeta, comicID string) SvcErr
 }
 
 type comicSvc struct {
@@ -44,6 +49,13 @@ type comicSvc struct {
 	comicAsgnRepo repo.ComicAsgnRepo
 	comicPageRepo repo.ComicPageRepo
 	comicUnitRepo repo.ComicUnitRepo
+	tagRepo       repo.TagRepo
+	comicLikeRepo repo.ComicLikeRepo
+	teamRepo      repo.TeamRepo
+	audit         *auditRecorder
+	events        *comicEventRecorder
+	notifier      *notifier
+	webhooks      *webhookQueue
 	exportDir     string
 	ossClient     oss.OSSClient
 }
@@ -54,6 +66,13 @@ func NewComicSvc(
 	car repo.ComicAsgnRepo,
 	cpr repo.ComicPageRepo,
 	cur repo.ComicUnitRepo,
+	tr repo.TagRepo,
+	clr repo.ComicLikeRepo,
+	tmr repo.TeamRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	nr repo.NotificationRepo,
+	whr repo.WebhookRepo,
 	exportDir string,
 	ossClient oss.OSSClient,
 ) ComicSvc {
@@ -72,6 +91,15 @@ func NewComicSvc(
 	if cur == nil {
 		panic("ComicUnitRepo cannot be nil")
 	}
+	if tr == nil {
+		panic("TagRepo cannot be nil")
+	}
+	if clr == nil {
+		panic("ComicLikeRepo cannot be nil")
+	}
+	if tmr == nil {
+		panic("TeamRepo cannot be nil")
+	}
 	if exportDir == "" {
 		panic("exportDir cannot be empty")
 	}
@@ -85,6 +113,13 @@ func NewComicSvc(
 		comicAsgnRepo: car,
 		comicPageRepo: cpr,
 		comicUnitRepo: cur,
+		tagRepo:       tr,
+		comicLikeRepo: clr,
+		teamRepo:      tmr,
+		audit:         newAuditRecorder(alr),
+		events:        newComicEventRecorder(cer),
+		notifier:      newNotifier(nr),
+		webhooks:      newWebhookQueue(whr),
 		exportDir:     exportDir,
 		ossClient:     ossClient,
 	}
@@ -110,6 +145,8 @@ func (cs *comicSvc) GetComicInfoByID(comicID string) (SvcRslt[model.ComicInfo],
 		Description:     basic.Description,
 		Comment:         basic.Comment,
 		PageCount:       basic.PageCount,
+		LikesCount:      basic.LikesCount,
+		Stage:           basic.Stage,
 		CreatedAt:       basic.CreatedAt.Unix(),
 		UpdatedAt:       basic.UpdatedAt.Unix(),
 	}
@@ -124,11 +161,18 @@ func (cs *comicSvc) GetComicInfoByID(comicID string) (SvcRslt[model.ComicInfo],
 	info.ReviewingCompletedAt = timePtrToInt64Ptr(basic.ReviewingCompletedAt)
 	info.UploadingCompletedAt = timePtrToInt64Ptr(basic.UploadingCompletedAt)
 
+	tags, err := cs.tagRepo.GetTagsByComicID(nil, comicID)
+	if err != nil {
+		zap.L().Error("Failed to get tags by comic ID", zap.String("comicID", comicID), zap.Error(err))
+		return SvcRslt[model.ComicInfo]{}, DB_FAILURE
+	}
+	info.Tags = poTagsToModelTags(tags)
+
 	return accept(200, info), NO_ERROR
 }
 
 // GetComicBriefsByWorksetID retrieves brief comic info by workset ID with pagination.
-func (cs *comicSvc) GetComicBriefsByWorksetID(worksetID string, offset, limit int) (SvcRslt[[]model.ComicBrief], SvcErr) {
+func (cs *comicSvc) GetComicBriefsByWorksetID(opID string, worksetID string, offset, limit int) (SvcRslt[[]model.ComicBrief], SvcErr) {
 	briefs, err := cs.repo.GetComicsByWorksetID(nil, worksetID, offset, limit)
 	if err != nil {
 		zap.L().Error("Failed to get comics by workset ID", zap.String("worksetID", worksetID), zap.Error(err))
@@ -146,6 +190,8 @@ func (cs *comicSvc) GetComicBriefsByWorksetID(worksetID string, offset, limit in
 			Author:       cb.Author,
 			Title:        cb.Title,
 			PageCount:    cb.PageCount,
+			LikesCount:   cb.LikesCount,
+			Stage:        cb.Stage,
 		}
 
 		// Handle optional timestamp fields
@@ -160,11 +206,15 @@ func (cs *comicSvc) GetComicBriefsByWorksetID(worksetID string, offset, limit in
 		lst = append(lst, brief)
 	}
 
+	if svcErr := cs.fillLikedByMe(opID, lst); svcErr != NO_ERROR {
+		return SvcRslt[[]model.ComicBrief]{}, svcErr
+	}
+
 	return accept(200, lst), NO_ERROR
 }
 
 // RetrieveComics retrieves comics with filtering and pagination.
-func (cs *comicSvc) RetrieveComics(opt model.RetrieveComicOpt) (SvcRslt[[]model.ComicBrief], SvcErr) {
+func (cs *comicSvc) RetrieveComics(opID string, opt model.RetrieveComicOpt) (SvcRslt[[]model.ComicBrief], SvcErr) {
 	briefs, err := cs.repo.RetrieveComics(nil, opt)
 	if err != nil {
 		zap.L().Error("Failed to retrieve comics", zap.Error(err))
@@ -181,6 +231,8 @@ func (cs *comicSvc) RetrieveComics(opt model.RetrieveComicOpt) (SvcRslt[[]model.
 			Author:       cb.Author,
 			Title:        cb.Title,
 			PageCount:    cb.PageCount,
+			LikesCount:   cb.LikesCount,
+			Stage:        cb.Stage,
 		}
 
 		// Handle optional timestamp fields
@@ -196,11 +248,66 @@ func (cs *comicSvc) RetrieveComics(opt model.RetrieveComicOpt) (SvcRslt[[]model.
 		lst = append(lst, brief)
 	}
 
+	if svcErr := cs.fillLikedByMe(opID, lst); svcErr != NO_ERROR {
+		return SvcRslt[[]model.ComicBrief]{}, svcErr
+	}
+
 	return accept(200, lst), NO_ERROR
 }
 
+// LikeComic makes opID like the comic. Liking a comic twice is a no-op.
+func (cs *comicSvc) LikeComic(opID string, comicID string) SvcErr {
+	if _, err := cs.repo.GetComicByID(nil, comicID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for like", zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := cs.comicLikeRepo.LikeComic(nil, opID, comicID); err != nil {
+		zap.L().Error("Failed to like comic", zap.String("userID", opID), zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// UnlikeComic removes the like of opID from the comic.
+func (cs *comicSvc) UnlikeComic(opID string, comicID string) SvcErr {
+	if err := cs.comicLikeRepo.UnlikeComic(nil, opID, comicID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to unlike comic", zap.String("userID", opID), zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// fillLikedByMe sets LikedByMe of the briefs for opID in one query.
+func (cs *comicSvc) fillLikedByMe(opID string, briefs []model.ComicBrief) SvcErr {
+	comicIDs := make([]string, 0, len(briefs))
+	for _, b := range briefs {
+		comicIDs = append(comicIDs, b.ID)
+	}
+
+	liked, err := cs.comicLikeRepo.GetLikedComicIDs(nil, opID, comicIDs)
+	if err != nil {
+		zap.L().Error("Failed to get liked comics", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	for i := range briefs {
+		briefs[i].LikedByMe = liked[briefs[i].ID]
+	}
+
+	return NO_ERROR
+}
+
 // ExportComic exports a comic to LabelPlus format.
-func (cs *comicSvc) ExportComic(comicID string, exportFormat string) (SvcRslt[model.ExportComicReply], SvcErr) {
+func (cs *comicSvc) ExportComic(opID string, comicID string, exportFormat string) (SvcRslt[model.ExportComicReply], SvcErr) {
 	// Validate export format
 	if exportFormat != "prk" && exportFormat != "lp" {
 		zap.L().Warn("Invalid export format", zap.String("comicID", comicID), zap.String("format", exportFormat))
@@ -253,6 +360,11 @@ func (cs *comicSvc) ExportComic(comicID string, exportFormat string) (SvcRslt[mo
 		return SvcRslt[model.ExportComicReply]{}, DB_FAILURE
 	}
 
+	// The export has succeeded regardless of whether it can be recorded.
+	if err := cs.events.record(nil, comicID, opID, COMIC_EVENT_EXPORT, map[string]string{"format": exportFormat}); err != nil {
+		zap.L().Error("Failed to record comic export", zap.String("comicID", comicID), zap.Error(err))
+	}
+
 	// Return relative URI
 	fileName := filepath.Base(filePath)
 	exportURI := cs.ExportBaseURI() + url.PathEscape(fileName)
@@ -295,8 +407,23 @@ func (cs *comicSvc) ImportComic(
 		UserID:        opID,
 	}
 
+	// Translators split the comic by page ranges write only their pages.
+	if !isProofreader && asgn.AssignedReviewerAt == nil {
+		ranges, err := parsePageRanges(asgn.PageRanges)
+		if err != nil {
+			zap.L().Error("Failed to parse page ranges for import", zap.String("assignmentID", asgn.ID), zap.Error(err))
+			return DB_FAILURE
+		}
+
+		if ranges != nil {
+			importOpts.PageFilter = func(index int64) bool { return pageRangesCover(ranges, index) }
+		}
+	}
+
 	lowerFileName := strings.ToLower(fileName)
 
+	var format string
+
 	switch {
 	case strings.HasSuffix(lowerFileName, ".poprako.json"):
 		if err := comicPkg.ImportPoprakoComic(reader, comicID, cs.comicPageRepo, cs.comicUnitRepo, importOpts); err != nil {
@@ -306,7 +433,7 @@ func (cs *comicSvc) ImportComic(
 				zap.Error(err))
 			return INVALID_PROJ_DATA
 		}
-		return NO_ERROR
+		format = "prk"
 
 	case strings.HasSuffix(lowerFileName, ".txt"):
 		// Import LabelPlus format
@@ -317,7 +444,7 @@ func (cs *comicSvc) ImportComic(
 				zap.Error(err))
 			return INVALID_PROJ_DATA
 		}
-		return NO_ERROR
+		format = "lp"
 
 	default:
 		ext := strings.ToLower(filepath.Ext(fileName))
@@ -327,6 +454,16 @@ func (cs *comicSvc) ImportComic(
 			zap.String("extension", ext))
 		return INVALID_PROJ_EXT
 	}
+
+	// The import has succeeded regardless of whether it can be recorded.
+	if err := cs.events.record(nil, comicID, opID, COMIC_EVENT_IMPORT, map[string]string{
+		"file_name": fileName,
+		"format":    format,
+	}); err != nil {
+		zap.L().Error("Failed to record comic import", zap.String("comicID", comicID), zap.Error(err))
+	}
+
+	return NO_ERROR
 }
 
 // cleanOldExports removes oldest export files if count exceeds 30.
@@ -400,9 +537,28 @@ func (cs *comicSvc) CreateComic(opID string, args model.CreateComicArgs) (SvcRsl
 		return SvcRslt[model.CreateComicReply]{}, PERMISSION_DENIED
 	}
 
+	// Merge members of the team into pre-assignments
+	preAsgns := args.PreAsgns
+	if args.TeamID != nil {
+		teamPreAsgns, svcErr := getTeamPreAsgns(cs.teamRepo, *args.TeamID)
+		if svcErr != NO_ERROR {
+			return SvcRslt[model.CreateComicReply]{}, svcErr
+		}
+		preAsgns = mergePreAsgns(preAsgns, teamPreAsgns)
+	}
+
 	// Validate pre-assignments
-	if len(args.PreAsgns) > 0 {
-		if svcErr := cs.validatePreAssignments(args.PreAsgns); svcErr != NO_ERROR {
+	if len(preAsgns) > 0 {
+		if svcErr := validatePreAssignments(cs.userRepo, preAsgns); svcErr != NO_ERROR {
+			return SvcRslt[model.CreateComicReply]{}, svcErr
+		}
+	}
+
+	// Resolve tags from gallery metadata
+	var galleryTags []po.BriefTag
+	if len(args.GalleryMeta) > 0 {
+		var svcErr SvcErr
+		if galleryTags, svcErr = cs.resolveGalleryTags(args.GalleryMeta); svcErr != NO_ERROR {
 			return SvcRslt[model.CreateComicReply]{}, svcErr
 		}
 	}
@@ -427,27 +583,94 @@ func (cs *comicSvc) CreateComic(opID string, args model.CreateComicArgs) (SvcRsl
 			Comment:     args.Comment,
 		}
 
-		if err := cs.repo.CreateComic(newComic); err != nil {
+		if err := cs.repo.CreateComic(tx, newComic); err != nil {
 			return fmt.Errorf("failed to create comic: %w", err)
 		}
 
+		if err := cs.webhooks.enqueue(tx, WEBHOOK_EVENT_COMIC_CREATE, newID, opID, comicWebhookData{
+			ID:        newID,
+			WorksetID: args.WorksetID,
+			Title:     args.Title,
+			Author:    args.Author,
+		}); err != nil {
+			return err
+		}
+
 		// Create pre-assignments
-		if err := cs.createPreAssignments(tx, newID, args.PreAsgns); err != nil {
+		if err := cs.createPreAssignments(tx, opID, newID, preAsgns); err != nil {
 			return err
 		}
 
+		// Attach resolved tags
+		if len(galleryTags) > 0 {
+			tagIDs := make([]string, 0, len(galleryTags))
+			for _, t := range galleryTags {
+				tagIDs = append(tagIDs, t.ID)
+			}
+
+			if err := cs.tagRepo.AttachTagsToComic(tx, newID, tagIDs); err != nil {
+				return fmt.Errorf("failed to attach gallery tags: %w", err)
+			}
+		}
+
 		return nil
 	}); err != nil {
 		zap.L().Error("Failed to create comic with assignments", zap.String("worksetID", args.WorksetID), zap.Error(err))
 		return SvcRslt[model.CreateComicReply]{}, DB_FAILURE
 	}
 
-	return accept(201, model.CreateComicReply{ID: newID}), NO_ERROR
+	reply := model.CreateComicReply{ID: newID}
+	if len(galleryTags) > 0 {
+		reply.Tags = poTagsToModelTags(galleryTags)
+	}
+
+	return accept(201, reply), NO_ERROR
+}
+
+// resolveGalleryTags parses gallery metadata and maps its site tags
+// to our tags through the candidate columns of the source site.
+func (cs *comicSvc) resolveGalleryTags(raw []byte) ([]po.BriefTag, SvcErr) {
+	meta, err := galleryPkg.ParseMeta(raw)
+	if err != nil {
+		zap.L().Warn("Failed to parse gallery metadata", zap.Error(err))
+		return nil, INVALID_GALLERY_META
+	}
+
+	var tags []po.BriefTag
+	switch meta.Source {
+	case galleryPkg.SOURCE_EHENTAI:
+		tags, err = cs.tagRepo.GetTagsByEhentaiCandidates(nil, meta.Candidates())
+	case galleryPkg.SOURCE_PICA:
+		tags, err = cs.tagRepo.GetTagsByPicaCandidates(nil, meta.Candidates())
+	}
+	if err != nil {
+		zap.L().Error("Failed to resolve gallery tags", zap.String("source", meta.Source), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	zap.L().Debug("Resolved gallery tags",
+		zap.String("source", meta.Source), zap.Strings("siteTags", meta.Tags), zap.Int("resolved", len(tags)))
+
+	return tags, NO_ERROR
 }
 
 // UpdateComicByID updates comic info by ID.
+// The workflow stage is changed through WorkflowSvc instead,
+// so the stage toggles of the old API are rejected rather than dropped.
 func (cs *comicSvc) UpdateComicByID(args model.UpdateComicArgs) SvcErr {
-	now := time.Now()
+	if slices.ContainsFunc([]*bool{
+		args.TranslatingStarted,
+		args.TranslatingCompleted,
+		args.ProofreadingStarted,
+		args.ProofreadingCompleted,
+		args.TypesettingStarted,
+		args.TypesettingCompleted,
+		args.ReviewingCompleted,
+		args.UploadingCompleted,
+	}, func(toggle *bool) bool { return toggle != nil }) {
+		zap.L().Warn("Comic update carrying stage toggles", zap.String("comicID", args.ID))
+		return STAGE_TOGGLES_REMOVED
+	}
 
 	patch := &po.PatchComic{
 		ID:          args.ID,
@@ -457,32 +680,6 @@ func (cs *comicSvc) UpdateComicByID(args model.UpdateComicArgs) SvcErr {
 		Comment:     args.Comment,
 	}
 
-	// Handle workflow timestamp toggles - only set when true
-	if args.TranslatingStarted != nil && *args.TranslatingStarted {
-		patch.TranslatingStartedAt = &now
-	}
-	if args.TranslatingCompleted != nil && *args.TranslatingCompleted {
-		patch.TranslatingCompletedAt = &now
-	}
-	if args.ProofreadingStarted != nil && *args.ProofreadingStarted {
-		patch.ProofreadingStartedAt = &now
-	}
-	if args.ProofreadingCompleted != nil && *args.ProofreadingCompleted {
-		patch.ProofreadingCompletedAt = &now
-	}
-	if args.TypesettingStarted != nil && *args.TypesettingStarted {
-		patch.TypesettingStartedAt = &now
-	}
-	if args.TypesettingCompleted != nil && *args.TypesettingCompleted {
-		patch.TypesettingCompletedAt = &now
-	}
-	if args.ReviewingCompleted != nil && *args.ReviewingCompleted {
-		patch.ReviewingCompletedAt = &now
-	}
-	if args.UploadingCompleted != nil && *args.UploadingCompleted {
-		patch.UploadingCompletedAt = &now
-	}
-
 	if err := cs.repo.UpdateComicByID(nil, patch); err != nil {
 		zap.L().Error("Failed to update comic", zap.String("comicID", args.ID), zap.Error(err))
 		return DB_FAILURE
@@ -492,9 +689,9 @@ func (cs *comicSvc) UpdateComicByID(args model.UpdateComicArgs) SvcErr {
 }
 
 // validatePreAssignments validates that all pre-assigned users have the required qualifications.
-func (cs *comicSvc) validatePreAssignments(preAsgns []model.PreAsgnArgs) SvcErr {
+func validatePreAssignments(userRepo repo.UserRepo, preAsgns []model.PreAsgnArgs) SvcErr {
 	for _, preAsgn := range preAsgns {
-		user, err := cs.userRepo.GetUserByID(nil, preAsgn.AssigneeID)
+		user, err := userRepo.GetUserByID(nil, preAsgn.AssigneeID)
 		if err != nil {
 			zap.L().Error("Failed to get user info for pre-assignment validation",
 				zap.String("userID", preAsgn.AssigneeID), zap.Error(err))
@@ -548,7 +745,7 @@ func (cs *comicSvc) validatePreAssignments(preAsgns []model.PreAsgnArgs) SvcErr
 
 // createPreAssignments creates comic assignments for pre-assigned users.
 // For each pre-assignment, it creates a new assignment record and sets the role timestamps.
-func (cs *comicSvc) createPreAssignments(tx repo.Exct, comicID string, preAsgns []model.PreAsgnArgs) error {
+func (cs *comicSvc) createPreAssignments(tx repo.Exct, opID string, comicID string, preAsgns []model.PreAsgnArgs) error {
 	now := time.Now()
 
 	for _, preAsgn := range preAsgns {
@@ -594,12 +791,55 @@ func (cs *comicSvc) createPreAssignments(tx repo.Exct, comicID string, preAsgns
 		if err := cs.comicAsgnRepo.UpdateAsgnByID(tx, patchAsgn); err != nil {
 			return fmt.Errorf("failed to update assignment roles: %w", err)
 		}
+
+		roles := asgnRoleNames(
+			patchAsgn.AssignedTranslatorAt,
+			patchAsgn.AssignedProofreaderAt,
+			patchAsgn.AssignedTypesetterAt,
+			patchAsgn.AssignedRedrawerAt,
+			patchAsgn.AssignedReviewerAt,
+		)
+
+		if err := cs.events.record(tx, comicID, opID, COMIC_EVENT_ASGN_CREATE, asgnEventDetail{
+			AsgnID: asgnID,
+			UserID: preAsgn.AssigneeID,
+			Roles:  roles,
+		}); err != nil {
+			return err
+		}
+
+		if err := cs.notifier.notify(tx, []string{preAsgn.AssigneeID}, notification{
+			Kind:    NOTIFICATION_ASSIGNED,
+			ComicID: comicID,
+			ActorID: opID,
+			Detail:  assignedNotification{AsgnID: asgnID, Roles: roles},
+		}); err != nil {
+			return err
+		}
+
+		if err := cs.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_CREATE, comicID, opID, asgnEventDetail{
+			AsgnID: asgnID,
+			UserID: preAsgn.AssigneeID,
+			Roles:  roles,
+		}); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (cs *comicSvc) DeleteComicByID(comicID string) SvcErr {
+func (cs *comicSvc) DeleteComicByID(opID string, meta model.ReqMeta, comicID string) SvcErr {
+	comic, err := cs.repo.GetComicByID(nil, comicID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			zap.L().Warn("Comic not found for deletion", zap.String("comicID", comicID))
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for deletion", zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
 	// First, get all pages for this comic
 	pages, err := cs.comicPageRepo.GetPagesByComicID(nil, comicID)
 	if err != nil {
@@ -665,7 +905,27 @@ func (cs *comicSvc) DeleteComicByID(comicID string) SvcErr {
 	}
 
 	// All pages deleted successfully, now delete the comic
-	if err := cs.repo.DeleteComicByID(nil, comicID); err != nil {
+	if err := cs.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cs.repo.DeleteComicByID(tx, comicID); err != nil {
+			return err
+		}
+
+		if err := cs.webhooks.enqueue(tx, WEBHOOK_EVENT_COMIC_DELETE, comicID, opID, comicWebhookData{
+			ID:        comic.ID,
+			WorksetID: comic.WorksetID,
+			Title:     comic.Title,
+			Author:    comic.Author,
+		}); err != nil {
+			return err
+		}
+
+		return cs.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_COMIC,
+			EntityID:   comicID,
+			Before:     comic,
+		})
+	}); err != nil {
 		if err == repo.REC_NOT_FOUND {
 			zap.L().Warn("Comic not found for deletion", zap.String("comicID", comicID))
 			return NOT_FOUND
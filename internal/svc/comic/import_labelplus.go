@@ -35,6 +35,10 @@ type parsedUnit struct {
 type ImportOptions struct {
 	IsProofreader bool
 	UserID        string
+
+	// Reports whether the page of the index may be written, all may if nil.
+	// Pages it rejects are skipped.
+	PageFilter func(index int64) bool
 }
 
 var (
@@ -94,6 +98,11 @@ func ImportLabelplusComic(
 	for i, parsedPage := range parsedPages {
 		dbPage := dbPages[i]
 
+		// Skip pages the importer is not assigned to
+		if opts.PageFilter != nil && !opts.PageFilter(dbPage.Index) {
+			continue
+		}
+
 		// Get existing units to delete
 		existingUnits, err := unitRepo.GetUnitsByPageID(tx, dbPage.ID)
 		if err != nil {
@@ -103,6 +103,10 @@ func ImportPoprakoComic(
 	for i, parsedPage := range parsedPages {
 		dbPage := dbPages[i]
 
+		if opts.PageFilter != nil && !opts.PageFilter(dbPage.Index) {
+			continue
+		}
+
 		existingUnits, err := unitRepo.GetUnitsByPageID(tx, dbPage.ID)
 		if err != nil {
 			tx.Rollback()
@@ -1,6 +1,7 @@
 package svc
 
 import (
+	"encoding/json"
 	"time"
 
 	"poprako-main-server/internal/model"
@@ -11,39 +12,81 @@ import (
 )
 
 // ComicAsgnSvc defines service operations for comic assignments.
+//
+// Each role of an assignment may have a due date. The work of a role counts as done
+// once the comic completes the stage of the role, so that a role past its due date
+// is overdue until then.
+//
+// Translators may be given page ranges to split a comic among them, in which case
+// they may only edit the units of those pages.
 type ComicAsgnSvc interface {
 	GetAsgnByID(assignmentID string) (SvcRslt[model.ComicAsgnInfo], SvcErr)
 	GetAsgnsByComicID(comicID string, offset, limit int) (SvcRslt[[]model.ComicAsgnInfo], SvcErr)
 	GetAsgnsByUserID(userID string, offset, limit int) (SvcRslt[[]model.ComicAsgnInfo], SvcErr)
+	RetrieveAsgns(opt model.RetrieveAsgnOpt) (SvcRslt[[]model.ComicAsgnInfo], SvcErr)
 
-	CreateAsgn(args model.CreateComicAsgnArgs) (SvcRslt[string], SvcErr)
+	GetUpcomingDeadlines(opID string, days int) (SvcRslt[[]model.AsgnDeadline], SvcErr)
 
-	UpdateAsgnByID(args model.UpdateComicAsgnArgs) SvcErr
+	// Run periodically by the stall checker.
+	FlagStalledAsgns(idleFor time.Duration) SvcErr
 
-	DeleteAsgnByID(assignmentID string) SvcErr
+	// GetPageCoverage reports how the pages of a comic are split among its translators.
+	GetPageCoverage(comicID string) (SvcRslt[model.PageCoverage], SvcErr)
+
+	CreateAsgn(opID string, meta model.ReqMeta, args model.CreateComicAsgnArgs) (SvcRslt[string], SvcErr)
+	UpdateAsgnByID(opID string, meta model.ReqMeta, args model.UpdateComicAsgnArgs) SvcErr
+
+	DeleteAsgnByID(opID string, meta model.ReqMeta, assignmentID string) SvcErr
 }
 
 type comicAsgnSvc struct {
 	repo     repo.ComicAsgnRepo
 	userRepo repo.UserRepo
+	pageRepo repo.ComicPageRepo
+	audit    *auditRecorder
+	events   *comicEventRecorder
+	notifier *notifier
+	webhooks *webhookQueue
 }
 
-// NewComicAsgnSvc creates a new ComicAsgnSvc. r and userRepo must not be nil.
-func NewComicAsgnSvc(r repo.ComicAsgnRepo, userRepo repo.UserRepo) ComicAsgnSvc {
+// NewComicAsgnSvc creates a new ComicAsgnSvc. None of the repos may be nil.
+func NewComicAsgnSvc(
+	r repo.ComicAsgnRepo,
+	userRepo repo.UserRepo,
+	pageRepo repo.ComicPageRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	nr repo.NotificationRepo,
+	whr repo.WebhookRepo,
+) ComicAsgnSvc {
 	if r == nil {
 		panic("ComicAsgnRepo cannot be nil")
 	}
 	if userRepo == nil {
 		panic("UserRepo cannot be nil")
 	}
+	if pageRepo == nil {
+		panic("ComicPageRepo cannot be nil")
+	}
 
-	return &comicAsgnSvc{repo: r, userRepo: userRepo}
+	return &comicAsgnSvc{
+		repo:     r,
+		userRepo: userRepo,
+		pageRepo: pageRepo,
+		audit:    newAuditRecorder(alr),
+		events:   newComicEventRecorder(cer),
+		notifier: newNotifier(nr),
+		webhooks: newWebhookQueue(whr),
+	}
 }
 
 // GetAsgnByID retrieves a comic assignment by ID.
 func (cas *comicAsgnSvc) GetAsgnByID(assignmentID string) (SvcRslt[model.ComicAsgnInfo], SvcErr) {
 	asgn, err := cas.repo.GetAsgnByID(nil, assignmentID)
 	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.ComicAsgnInfo]{}, NOT_FOUND
+		}
 		zap.L().Error("Failed to get assignment by ID", zap.String("assignmentID", assignmentID), zap.Error(err))
 		return SvcRslt[model.ComicAsgnInfo]{}, DB_FAILURE
 	}
@@ -84,8 +127,39 @@ func (cas *comicAsgnSvc) GetAsgnsByUserID(userID string, offset, limit int) (Svc
 	return accept(200, asgnInfos), NO_ERROR
 }
 
+// GetPageCoverage reports how the pages of a comic are split among its translators.
+func (cas *comicAsgnSvc) GetPageCoverage(comicID string) (SvcRslt[model.PageCoverage], SvcErr) {
+	coverage, err := cas.getPageCoverage(nil, comicID)
+	if err != nil {
+		zap.L().Error("Failed to get page coverage", zap.String("comicID", comicID), zap.Error(err))
+		return SvcRslt[model.PageCoverage]{}, DB_FAILURE
+	}
+
+	return accept(200, *coverage), NO_ERROR
+}
+
+// RetrieveAsgns retrieves comic assignments matching opt, most recently updated first.
+func (cas *comicAsgnSvc) RetrieveAsgns(opt model.RetrieveAsgnOpt) (SvcRslt[[]model.ComicAsgnInfo], SvcErr) {
+	asgnList, err := cas.repo.RetrieveAsgns(nil, opt, asgnOpenStages, time.Now())
+	if err != nil {
+		zap.L().Error("Failed to retrieve assignments", zap.Error(err))
+		return SvcRslt[[]model.ComicAsgnInfo]{}, DB_FAILURE
+	}
+
+	asgnInfos := make([]model.ComicAsgnInfo, 0, len(asgnList))
+	for _, asgn := range asgnList {
+		asgnInfos = append(asgnInfos, poAsgnToModelAsgn(&asgn))
+	}
+
+	return accept(200, asgnInfos), NO_ERROR
+}
+
 // CreateAsgn creates a new comic assignment.
-func (cas *comicAsgnSvc) CreateAsgn(args model.CreateComicAsgnArgs) (SvcRslt[string], SvcErr) {
+func (cas *comicAsgnSvc) CreateAsgn(
+	opID string,
+	meta model.ReqMeta,
+	args model.CreateComicAsgnArgs,
+) (SvcRslt[string], SvcErr) {
 	// Validate user qualifications for requested roles
 	user, err := cas.userRepo.GetUserByID(nil, args.AssigneeID)
 	if err != nil {
@@ -138,11 +212,6 @@ func (cas *comicAsgnSvc) CreateAsgn(args model.CreateComicAsgnArgs) (SvcRslt[str
 		UserID:  args.AssigneeID,
 	}
 
-	if err := cas.repo.CreateAsgn(nil, newAssign); err != nil {
-		zap.L().Error("Failed to create assignment", zap.Error(err))
-		return SvcRslt[string]{}, DB_FAILURE
-	}
-
 	// Set role timestamps based on requested roles
 	now := time.Now()
 	patchAssign := po.PatchComicAsgn{
@@ -165,9 +234,92 @@ func (cas *comicAsgnSvc) CreateAsgn(args model.CreateComicAsgnArgs) (SvcRslt[str
 		patchAssign.AssignedReviewerAt = &now
 	}
 
-	// Update assignment with role timestamps
-	if err := cas.repo.UpdateAsgnByID(nil, &patchAssign); err != nil {
-		zap.L().Error("Failed to set assignment roles", zap.Error(err))
+	roles := map[string]*bool{
+		ROLE_TRANSLATOR:  args.IsTranslator,
+		ROLE_PROOFREADER: args.IsProofreader,
+		ROLE_TYPESETTER:  args.IsTypesetter,
+		ROLE_REDRAWER:    args.IsRedrawer,
+		ROLE_REVIEWER:    args.IsReviewer,
+	}
+
+	if svcErr := asgnDuesToPatch(&patchAssign, map[string]*int64{
+		ROLE_TRANSLATOR:  args.TranslatorDueAt,
+		ROLE_PROOFREADER: args.ProofreaderDueAt,
+		ROLE_TYPESETTER:  args.TypesetterDueAt,
+		ROLE_REDRAWER:    args.RedrawerDueAt,
+		ROLE_REVIEWER:    args.ReviewerDueAt,
+	}, func(role string) bool {
+		return roles[role] != nil && *roles[role]
+	}); svcErr != NO_ERROR {
+		return SvcRslt[string]{}, svcErr
+	}
+
+	isTranslator := args.IsTranslator != nil && *args.IsTranslator
+
+	if len(args.PageRanges) > 0 {
+		if !isTranslator {
+			return SvcRslt[string]{}, INVALID_PAGE_RANGE
+		}
+
+		ranges, svcErr := normalizePageRanges(args.PageRanges)
+		if svcErr != NO_ERROR {
+			return SvcRslt[string]{}, svcErr
+		}
+
+		raw, err := json.Marshal(ranges)
+		if err != nil {
+			zap.L().Error("Failed to marshal page ranges", zap.Error(err))
+			return SvcRslt[string]{}, DB_FAILURE
+		}
+		patchAssign.PageRanges = raw
+	}
+
+	if err := cas.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cas.repo.CreateAsgn(tx, newAssign); err != nil {
+			return err
+		}
+
+		// Update assignment with role timestamps
+		if err := cas.repo.UpdateAsgnByID(tx, &patchAssign); err != nil {
+			return err
+		}
+
+		after, err := cas.repo.GetAsgnByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		detail := asgnEventDetail{
+			AsgnID: id,
+			UserID: after.UserID,
+			Roles:  basicAsgnRoleNames(after),
+		}
+
+		if err := cas.events.record(tx, after.ComicID, opID, COMIC_EVENT_ASGN_CREATE, detail); err != nil {
+			return err
+		}
+
+		if err := cas.notifier.notify(tx, []string{after.UserID}, notification{
+			Kind:    NOTIFICATION_ASSIGNED,
+			ComicID: after.ComicID,
+			ActorID: opID,
+			Detail:  assignedNotification{AsgnID: id, Roles: detail.Roles},
+		}); err != nil {
+			return err
+		}
+
+		if err := cas.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_CREATE, after.ComicID, opID, detail); err != nil {
+			return err
+		}
+
+		return cas.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_CREATE,
+			EntityType: AUDIT_ENTITY_ASGN,
+			EntityID:   id,
+			After:      after,
+		})
+	}); err != nil {
+		zap.L().Error("Failed to create assignment", zap.Error(err))
 		return SvcRslt[string]{}, DB_FAILURE
 	}
 
@@ -175,10 +327,109 @@ func (cas *comicAsgnSvc) CreateAsgn(args model.CreateComicAsgnArgs) (SvcRslt[str
 }
 
 // UpdateAsgnByID updates a comic assignment by ID.
-func (cas *comicAsgnSvc) UpdateAsgnByID(args model.UpdateComicAsgnArgs) SvcErr {
+func (cas *comicAsgnSvc) UpdateAsgnByID(
+	opID string,
+	meta model.ReqMeta,
+	args model.UpdateComicAsgnArgs,
+) SvcErr {
 	patchAssign := modelAsgnArgsToPoPatch(args)
 
-	if err := cas.repo.UpdateAsgnByID(nil, &patchAssign); err != nil {
+	before, err := cas.repo.GetAsgnByID(nil, args.ID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get assignment for update", zap.String("assignmentID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	roles := map[string]*bool{
+		ROLE_TRANSLATOR:  args.IsTranslator,
+		ROLE_PROOFREADER: args.IsProofreader,
+		ROLE_TYPESETTER:  args.IsTypesetter,
+		ROLE_REDRAWER:    args.IsRedrawer,
+		ROLE_REVIEWER:    args.IsReviewer,
+	}
+
+	if svcErr := asgnDuesToPatch(&patchAssign, map[string]*int64{
+		ROLE_TRANSLATOR:  args.TranslatorDueAt,
+		ROLE_PROOFREADER: args.ProofreaderDueAt,
+		ROLE_TYPESETTER:  args.TypesetterDueAt,
+		ROLE_REDRAWER:    args.RedrawerDueAt,
+		ROLE_REVIEWER:    args.ReviewerDueAt,
+	}, func(role string) bool {
+		if roles[role] != nil {
+			return *roles[role]
+		}
+		for _, f := range asgnRoleFields {
+			if f.role == role {
+				return f.assigned(before) != nil
+			}
+		}
+		return false
+	}); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	wasTranslator := before.AssignedTranslatorAt != nil
+	isTranslator := wasTranslator
+	if args.IsTranslator != nil {
+		isTranslator = *args.IsTranslator
+	}
+
+	switch {
+	case !isTranslator && wasTranslator:
+		// Erased along with the role.
+		patchAssign.PageRanges = json.RawMessage{}
+
+	case args.PageRanges != nil && len(*args.PageRanges) == 0:
+		patchAssign.PageRanges = json.RawMessage{}
+
+	case args.PageRanges != nil:
+		if !isTranslator {
+			return INVALID_PAGE_RANGE
+		}
+
+		ranges, svcErr := normalizePageRanges(*args.PageRanges)
+		if svcErr != NO_ERROR {
+			return svcErr
+		}
+
+		raw, err := json.Marshal(ranges)
+		if err != nil {
+			zap.L().Error("Failed to marshal page ranges", zap.Error(err))
+			return DB_FAILURE
+		}
+		patchAssign.PageRanges = raw
+	}
+
+	if err := cas.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cas.repo.UpdateAsgnByID(tx, &patchAssign); err != nil {
+			return err
+		}
+
+		after, err := cas.repo.GetAsgnByID(tx, args.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := cas.events.record(tx, after.ComicID, opID, COMIC_EVENT_ASGN_UPDATE, asgnEventDetail{
+			AsgnID:    args.ID,
+			UserID:    after.UserID,
+			Roles:     basicAsgnRoleNames(after),
+			PrevRoles: basicAsgnRoleNames(before),
+		}); err != nil {
+			return err
+		}
+
+		return cas.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_UPDATE,
+			EntityType: AUDIT_ENTITY_ASGN,
+			EntityID:   args.ID,
+			Before:     before,
+			After:      after,
+		})
+	}); err != nil {
 		zap.L().Error("Failed to update assignment", zap.Error(err))
 		return DB_FAILURE
 	}
@@ -186,8 +437,43 @@ func (cas *comicAsgnSvc) UpdateAsgnByID(args model.UpdateComicAsgnArgs) SvcErr {
 	return NO_ERROR
 }
 
-func (cas *comicAsgnSvc) DeleteAsgnByID(assignmentID string) SvcErr {
-	if err := cas.repo.DeleteAsgnByID(nil, assignmentID); err != nil {
+func (cas *comicAsgnSvc) DeleteAsgnByID(opID string, meta model.ReqMeta, assignmentID string) SvcErr {
+	before, err := cas.repo.GetAsgnByID(nil, assignmentID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			zap.L().Warn("Assignment not found for deletion", zap.String("assignmentID", assignmentID))
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get assignment for deletion", zap.String("assignmentID", assignmentID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := cas.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cas.repo.DeleteAsgnByID(tx, assignmentID); err != nil {
+			return err
+		}
+
+		detail := asgnEventDetail{
+			AsgnID: assignmentID,
+			UserID: before.UserID,
+			Roles:  basicAsgnRoleNames(before),
+		}
+
+		if err := cas.events.record(tx, before.ComicID, opID, COMIC_EVENT_ASGN_DELETE, detail); err != nil {
+			return err
+		}
+
+		if err := cas.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_DELETE, before.ComicID, opID, detail); err != nil {
+			return err
+		}
+
+		return cas.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_ASGN,
+			EntityID:   assignmentID,
+			Before:     before,
+		})
+	}); err != nil {
 		if err == repo.REC_NOT_FOUND {
 			zap.L().Warn("Assignment not found for deletion", zap.String("assignmentID", assignmentID))
 			return NOT_FOUND
@@ -231,6 +517,19 @@ func poAsgnToModelAsgn(asgn *po.BasicComicAsgn) model.ComicAsgnInfo {
 		info.AssignedReviewerAt = &ts
 	}
 
+	info.TranslatorDueAt = timePtrToInt64Ptr(asgn.TranslatorDueAt)
+	info.ProofreaderDueAt = timePtrToInt64Ptr(asgn.ProofreaderDueAt)
+	info.TypesetterDueAt = timePtrToInt64Ptr(asgn.TypesetterDueAt)
+	info.RedrawerDueAt = timePtrToInt64Ptr(asgn.RedrawerDueAt)
+	info.ReviewerDueAt = timePtrToInt64Ptr(asgn.ReviewerDueAt)
+	info.StalledAt = timePtrToInt64Ptr(asgn.StalledAt)
+
+	ranges, err := parsePageRanges(asgn.PageRanges)
+	if err != nil {
+		zap.L().Error("Failed to parse page ranges", zap.String("assignmentID", asgn.ID), zap.Error(err))
+	}
+	info.PageRanges = ranges
+
 	return info
 }
 
@@ -245,12 +544,13 @@ func modelAsgnArgsToPoPatch(args model.UpdateComicAsgnArgs) po.PatchComicAsgn {
 
 	// Convert role flags to timestamp assignments/removals
 	// true = assign role with current timestamp
-	// false = remove role (set to zero time, which repo converts to NULL)
+	// false = remove role (set to zero time, which repo converts to NULL), along with its due date
 	if args.IsTranslator != nil {
 		if *args.IsTranslator {
 			patch.AssignedTranslatorAt = &now
 		} else {
 			patch.AssignedTranslatorAt = &zeroTime
+			patch.TranslatorDueAt = &zeroTime
 		}
 	}
 
@@ -259,6 +559,7 @@ func modelAsgnArgsToPoPatch(args model.UpdateComicAsgnArgs) po.PatchComicAsgn {
 			patch.AssignedProofreaderAt = &now
 		} else {
 			patch.AssignedProofreaderAt = &zeroTime
+			patch.ProofreaderDueAt = &zeroTime
 		}
 	}
 
@@ -267,6 +568,7 @@ func modelAsgnArgsToPoPatch(args model.UpdateComicAsgnArgs) po.PatchComicAsgn {
 			patch.AssignedTypesetterAt = &now
 		} else {
 			patch.AssignedTypesetterAt = &zeroTime
+			patch.TypesetterDueAt = &zeroTime
 		}
 	}
 
@@ -275,6 +577,7 @@ func modelAsgnArgsToPoPatch(args model.UpdateComicAsgnArgs) po.PatchComicAsgn {
 			patch.AssignedRedrawerAt = &now
 		} else {
 			patch.AssignedRedrawerAt = &zeroTime
+			patch.RedrawerDueAt = &zeroTime
 		}
 	}
 
@@ -283,6 +586,7 @@ func modelAsgnArgsToPoPatch(args model.UpdateComicAsgnArgs) po.PatchComicAsgn {
 			patch.AssignedReviewerAt = &now
 		} else {
 			patch.AssignedReviewerAt = &zeroTime
+			patch.ReviewerDueAt = &zeroTime
 		}
 	}
 
@@ -0,0 +1,183 @@
+package svc
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDeadlineDays = 7
+	maxDeadlineDays     = 90
+)
+
+// asgnActiveStages maps each assignment role to the stages in which its work is under way.
+// Translators start the work, so they are active before it starts.
+var asgnActiveStages = map[string][]string{
+	ROLE_TRANSLATOR:  {model.COMIC_STAGE_PENDING, model.COMIC_STAGE_TRANSLATING},
+	ROLE_PROOFREADER: {model.COMIC_STAGE_PROOFREADING},
+	ROLE_TYPESETTER:  {model.COMIC_STAGE_TYPESETTING},
+	ROLE_REDRAWER:    {model.COMIC_STAGE_TYPESETTING},
+	ROLE_REVIEWER:    {model.COMIC_STAGE_REVIEWING},
+}
+
+// asgnOpenStages maps each assignment role to the stages in which its work is not done,
+// namely those up to the last in which it is active.
+var asgnOpenStages = func() map[string][]string {
+	open := make(map[string][]string, len(asgnActiveStages))
+
+	for role, stages := range asgnActiveStages {
+		open[role] = workflowStages[:stageIndex(stages[len(stages)-1])+1]
+	}
+
+	return open
+}()
+
+// asgnRoleField pairs an assignment role with its columns and pre-assignment flag.
+type asgnRoleField struct {
+	role          string
+	assigned      func(*po.BasicComicAsgn) *time.Time
+	due           func(*po.BasicComicAsgn) *time.Time
+	patchAssigned func(*po.PatchComicAsgn) **time.Time
+	patchDue      func(*po.PatchComicAsgn) **time.Time
+	preAsgn       func(*model.PreAsgnArgs) **bool
+}
+
+var asgnRoleFields = []asgnRoleField{
+	{
+		ROLE_TRANSLATOR,
+		func(a *po.BasicComicAsgn) *time.Time { return a.AssignedTranslatorAt },
+		func(a *po.BasicComicAsgn) *time.Time { return a.TranslatorDueAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.AssignedTranslatorAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.TranslatorDueAt },
+		func(a *model.PreAsgnArgs) **bool { return &a.IsTranslator },
+	},
+	{
+		ROLE_PROOFREADER,
+		func(a *po.BasicComicAsgn) *time.Time { return a.AssignedProofreaderAt },
+		func(a *po.BasicComicAsgn) *time.Time { return a.ProofreaderDueAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.AssignedProofreaderAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.ProofreaderDueAt },
+		func(a *model.PreAsgnArgs) **bool { return &a.IsProofreader },
+	},
+	{
+		ROLE_TYPESETTER,
+		func(a *po.BasicComicAsgn) *time.Time { return a.AssignedTypesetterAt },
+		func(a *po.BasicComicAsgn) *time.Time { return a.TypesetterDueAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.AssignedTypesetterAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.TypesetterDueAt },
+		func(a *model.PreAsgnArgs) **bool { return &a.IsTypesetter },
+	},
+	{
+		ROLE_REDRAWER,
+		func(a *po.BasicComicAsgn) *time.Time { return a.AssignedRedrawerAt },
+		func(a *po.BasicComicAsgn) *time.Time { return a.RedrawerDueAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.AssignedRedrawerAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.RedrawerDueAt },
+		func(a *model.PreAsgnArgs) **bool { return &a.IsRedrawer },
+	},
+	{
+		ROLE_REVIEWER,
+		func(a *po.BasicComicAsgn) *time.Time { return a.AssignedReviewerAt },
+		func(a *po.BasicComicAsgn) *time.Time { return a.ReviewerDueAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.AssignedReviewerAt },
+		func(p *po.PatchComicAsgn) **time.Time { return &p.ReviewerDueAt },
+		func(a *model.PreAsgnArgs) **bool { return &a.IsReviewer },
+	},
+}
+
+// GetUpcomingDeadlines lists the due dates of opID falling within the coming days,
+// along with those already passed, soonest first.
+// Roles whose stage the comic has completed are left out.
+func (cas *comicAsgnSvc) GetUpcomingDeadlines(opID string, days int) (SvcRslt[[]model.AsgnDeadline], SvcErr) {
+	if days <= 0 {
+		days = defaultDeadlineDays
+	}
+	days = min(days, maxDeadlineDays)
+
+	now := time.Now()
+	until := now.AddDate(0, 0, days)
+
+	asgns, err := cas.repo.GetDueAsgnsByUserID(nil, opID, asgnOpenStages, until)
+	if err != nil {
+		zap.L().Error("Failed to get due assignments", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[[]model.AsgnDeadline]{}, DB_FAILURE
+	}
+
+	deadlines := []model.AsgnDeadline{}
+
+	for i := range asgns {
+		asgn := &asgns[i]
+
+		for _, f := range asgnRoleFields {
+			dueAt := f.due(&asgn.BasicComicAsgn)
+
+			if f.assigned(&asgn.BasicComicAsgn) == nil || dueAt == nil || dueAt.After(until) ||
+				!slices.Contains(asgnOpenStages[f.role], asgn.ComicStage) {
+				continue
+			}
+
+			deadlines = append(deadlines, model.AsgnDeadline{
+				AsgnID:     asgn.ID,
+				ComicID:    asgn.ComicID,
+				ComicTitle: asgn.ComicTitle,
+				ComicStage: asgn.ComicStage,
+				Role:       f.role,
+				DueAt:      dueAt.Unix(),
+				Overdue:    dueAt.Before(now),
+			})
+		}
+	}
+
+	slices.SortFunc(deadlines, func(a, b model.AsgnDeadline) int { return cmp.Compare(a.DueAt, b.DueAt) })
+
+	return accept(200, deadlines), NO_ERROR
+}
+
+// FlagStalledAsgns flags the assignments whose comic has been idle for idleFor
+// in a stage one of their roles is active in, and clears the flags no longer holding.
+func (cas *comicAsgnSvc) FlagStalledAsgns(idleFor time.Duration) SvcErr {
+	now := time.Now()
+
+	flagged, cleared, err := cas.repo.MarkStalledAsgns(nil, asgnActiveStages, now.Add(-idleFor), now)
+	if err != nil {
+		zap.L().Error("Failed to flag stalled assignments", zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if flagged > 0 || cleared > 0 {
+		zap.L().Info("Checked stalled assignments", zap.Int64("flagged", flagged), zap.Int64("cleared", cleared))
+	}
+
+	return NO_ERROR
+}
+
+// asgnDuesToPatch sets the due dates given by role in Unix seconds on patch, 0 erasing one.
+// held tells whether a role is held once the change is made; due dates are only allowed for those.
+func asgnDuesToPatch(patch *po.PatchComicAsgn, dues map[string]*int64, held func(role string) bool) SvcErr {
+	for _, f := range asgnRoleFields {
+		due := dues[f.role]
+		if due == nil {
+			continue
+		}
+
+		switch {
+		case *due < 0:
+			return INVALID_DUE_DATE
+		case *due == 0:
+			*f.patchDue(patch) = &time.Time{}
+		case !held(f.role):
+			return INVALID_DUE_DATE
+		default:
+			at := time.Unix(*due, 0)
+			*f.patchDue(patch) = &at
+		}
+	}
+
+	return NO_ERROR
+}
@@ -0,0 +1,152 @@
+package svc
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/repo"
+)
+
+// Most page ranges an assignment may have.
+const maxPageRanges = 100
+
+// normalizePageRanges sorts ranges and merges those overlapping or adjacent.
+func normalizePageRanges(ranges []model.PageRange) ([]model.PageRange, SvcErr) {
+	if len(ranges) > maxPageRanges {
+		return nil, INVALID_PAGE_RANGE
+	}
+
+	for _, r := range ranges {
+		if r.From < 0 || r.From > r.To {
+			return nil, INVALID_PAGE_RANGE
+		}
+	}
+
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b model.PageRange) int { return cmp.Compare(a.From, b.From) })
+
+	merged := []model.PageRange{}
+	for _, r := range sorted {
+		if n := len(merged); n > 0 && r.From <= merged[n-1].To+1 {
+			merged[n-1].To = max(merged[n-1].To, r.To)
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged, NO_ERROR
+}
+
+// parsePageRanges decodes the page_ranges column, nil meaning the whole comic.
+func parsePageRanges(raw json.RawMessage) ([]model.PageRange, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	var ranges []model.PageRange
+	if err := json.Unmarshal(raw, &ranges); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page ranges: %w", err)
+	}
+
+	return ranges, nil
+}
+
+// pageRangesCover reports whether ranges, nil meaning the whole comic, cover the page of index.
+func pageRangesCover(ranges []model.PageRange, index int64) bool {
+	if ranges == nil {
+		return true
+	}
+
+	return slices.ContainsFunc(ranges, func(r model.PageRange) bool {
+		return r.From <= index && index <= r.To
+	})
+}
+
+// getPageCoverage reports how the pages of a comic are split among its translators.
+// Gaps and overlaps are left empty if none of them has page ranges, in which case
+// they all work on the whole comic.
+func (cas *comicAsgnSvc) getPageCoverage(ex repo.Exct, comicID string) (*model.PageCoverage, error) {
+	asgns, err := cas.repo.GetAsgnsByComicID(ex, comicID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := &model.PageCoverage{
+		Gaps:     []model.PageRange{},
+		Overlaps: []model.PageOverlap{},
+	}
+
+	type translator struct {
+		userID string
+		ranges []model.PageRange
+	}
+
+	var (
+		translators []translator
+		split       bool
+	)
+
+	for i := range asgns {
+		if asgns[i].AssignedTranslatorAt == nil {
+			continue
+		}
+
+		ranges, err := parsePageRanges(asgns[i].PageRanges)
+		if err != nil {
+			return nil, err
+		}
+
+		translators = append(translators, translator{asgns[i].UserID, ranges})
+		split = split || ranges != nil
+	}
+
+	if !split {
+		return coverage, nil
+	}
+	coverage.Split = true
+
+	pages, err := cas.pageRepo.GetPagesByComicID(ex, comicID)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int64, 0, len(pages))
+	for _, p := range pages {
+		indexes = append(indexes, p.Index)
+	}
+	slices.Sort(indexes)
+
+	// Consecutive pages with the same owners are reported as one range.
+	var prevOwners []string
+
+	for i, index := range indexes {
+		owners := []string{}
+		for _, t := range translators {
+			if pageRangesCover(t.ranges, index) {
+				owners = append(owners, t.userID)
+			}
+		}
+
+		extend := i > 0 && slices.Equal(owners, prevOwners)
+		prevOwners = owners
+
+		switch {
+		case len(owners) == 0 && extend:
+			coverage.Gaps[len(coverage.Gaps)-1].To = index
+		case len(owners) == 0:
+			coverage.Gaps = append(coverage.Gaps, model.PageRange{From: index, To: index})
+		case len(owners) > 1 && extend:
+			coverage.Overlaps[len(coverage.Overlaps)-1].To = index
+		case len(owners) > 1:
+			coverage.Overlaps = append(coverage.Overlaps, model.PageOverlap{
+				PageRange: model.PageRange{From: index, To: index},
+				UserIDs:   owners,
+			})
+		}
+	}
+
+	return coverage, nil
+}
@@ -0,0 +1,173 @@
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Kinds of comic events.
+const (
+	COMIC_EVENT_STAGE_TRANSITION = "stage_transition"
+	COMIC_EVENT_ASGN_CREATE      = "assignment_create"
+	COMIC_EVENT_ASGN_UPDATE      = "assignment_update"
+	COMIC_EVENT_ASGN_DELETE      = "assignment_delete"
+	COMIC_EVENT_PAGES_CREATE     = "pages_create"
+	COMIC_EVENT_PAGE_UPLOAD      = "page_upload"
+	COMIC_EVENT_IMPORT           = "import"
+	COMIC_EVENT_EXPORT           = "export"
+	COMIC_EVENT_TASK_CLAIM       = "task_claim"
+	COMIC_EVENT_TASK_RELEASE     = "task_release"
+)
+
+const (
+	defaultComicEventLimit = 100
+	maxComicEventLimit     = 500
+)
+
+// ComicEventSvc defines service operations for the timeline of a comic.
+// Events are written by the services making the changes.
+type ComicEventSvc interface {
+	GetComicTimeline(comicID string, opt model.RetrieveComicEventOpt) (SvcRslt[[]model.ComicEventInfo], SvcErr)
+}
+
+type comicEventSvc struct {
+	repo      repo.ComicEventRepo
+	comicRepo repo.ComicRepo
+}
+
+// NewComicEventSvc creates a new ComicEventSvc. r and cr must not be nil.
+func NewComicEventSvc(r repo.ComicEventRepo, cr repo.ComicRepo) ComicEventSvc {
+	if r == nil {
+		panic("ComicEventRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+
+	return &comicEventSvc{repo: r, comicRepo: cr}
+}
+
+// GetComicTimeline lists the events of a comic matching opt, oldest first.
+func (ces *comicEventSvc) GetComicTimeline(
+	comicID string,
+	opt model.RetrieveComicEventOpt,
+) (SvcRslt[[]model.ComicEventInfo], SvcErr) {
+	if _, err := ces.comicRepo.GetComicByID(nil, comicID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[[]model.ComicEventInfo]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for timeline", zap.String("comicID", comicID), zap.Error(err))
+		return SvcRslt[[]model.ComicEventInfo]{}, DB_FAILURE
+	}
+
+	if opt.Limit <= 0 {
+		opt.Limit = defaultComicEventLimit
+	}
+	opt.Limit = min(opt.Limit, maxComicEventLimit)
+
+	events, err := ces.repo.GetEventsByComicID(nil, comicID, opt)
+	if err != nil {
+		zap.L().Error("Failed to get comic events", zap.String("comicID", comicID), zap.Error(err))
+		return SvcRslt[[]model.ComicEventInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.ComicEventInfo, 0, len(events))
+	for _, e := range events {
+		infos = append(infos, model.ComicEventInfo{
+			ID:            e.ID,
+			ComicID:       e.ComicID,
+			ActorID:       e.ActorID,
+			ActorNickname: e.ActorNickname,
+			Kind:          e.Kind,
+			Detail:        e.Detail,
+			CreatedAt:     e.CreatedAt.Unix(),
+		})
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// comicEventRecorder writes comic events on behalf of the services making the changes.
+type comicEventRecorder struct {
+	repo repo.ComicEventRepo
+}
+
+func newComicEventRecorder(r repo.ComicEventRepo) *comicEventRecorder {
+	if r == nil {
+		panic("ComicEventRepo cannot be nil")
+	}
+
+	return &comicEventRecorder{repo: r}
+}
+
+// record writes an event with detail marshalled as JSON.
+// Pass the transaction of the change as ex, so that the change is rolled back if it cannot be recorded.
+func (cer *comicEventRecorder) record(ex repo.Exct, comicID string, actorID string, kind string, detail any) error {
+	id, err := genUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate comic event ID: %w", err)
+	}
+
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal comic event detail: %w", err)
+	}
+
+	return cer.repo.CreateComicEvent(ex, &po.NewComicEvent{
+		ID:      id,
+		ComicID: comicID,
+		ActorID: actorID,
+		Kind:    kind,
+		Detail:  raw,
+	})
+}
+
+// asgnEventDetail describes an assignment in comic events.
+type asgnEventDetail struct {
+	AsgnID string   `json:"assignment_id"`
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+
+	// Set on updates only.
+	PrevRoles []string `json:"prev_roles,omitempty"`
+}
+
+// asgnRoleNames lists the ROLE_* strings an assignment holds.
+// Nil and zero timestamps, the latter meaning removal in patches, are not held.
+func asgnRoleNames(translator, proofreader, typesetter, redrawer, reviewer *time.Time) []string {
+	roles := []string{}
+
+	for _, r := range []struct {
+		at   *time.Time
+		role string
+	}{
+		{translator, ROLE_TRANSLATOR},
+		{proofreader, ROLE_PROOFREADER},
+		{typesetter, ROLE_TYPESETTER},
+		{redrawer, ROLE_REDRAWER},
+		{reviewer, ROLE_REVIEWER},
+	} {
+		if r.at != nil && !r.at.IsZero() {
+			roles = append(roles, r.role)
+		}
+	}
+
+	return roles
+}
+
+func basicAsgnRoleNames(asgn *po.BasicComicAsgn) []string {
+	return asgnRoleNames(
+		asgn.AssignedTranslatorAt,
+		asgn.AssignedProofreaderAt,
+		asgn.AssignedTypesetterAt,
+		asgn.AssignedRedrawerAt,
+		asgn.AssignedReviewerAt,
+	)
+}
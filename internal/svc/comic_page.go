@@ -37,7 +37,7 @@ type ComicPageSvc interface {
 
 	UpdatePageByID(opID string, args *model.PatchComicPageArgs) SvcErr
 
-	DeletePageByID(pageID string) SvcErr
+	DeletePageByID(opID string, meta model.ReqMeta, pageID string) SvcErr
 }
 
 type comicPageSvc struct {
@@ -45,6 +45,9 @@ type comicPageSvc struct {
 	comicRepo     repo.ComicRepo
 	comicAsgnRepo repo.ComicAsgnRepo
 	unitRepo      repo.ComicUnitRepo
+	audit         *auditRecorder
+	events        *comicEventRecorder
+	webhooks      *webhookQueue
 	ossClient     oss.OSSClient
 }
 
@@ -53,6 +56,9 @@ func NewComicPageSvc(
 	comicRepo repo.ComicRepo,
 	comicAsgnRepo repo.ComicAsgnRepo,
 	unitRepo repo.ComicUnitRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	whr repo.WebhookRepo,
 	ossClient oss.OSSClient,
 ) ComicPageSvc {
 	return &comicPageSvc{
@@ -60,6 +66,9 @@ func NewComicPageSvc(
 		comicRepo:     comicRepo,
 		unitRepo:      unitRepo,
 		comicAsgnRepo: comicAsgnRepo,
+		audit:         newAuditRecorder(alr),
+		events:        newComicEventRecorder(cer),
+		webhooks:      newWebhookQueue(whr),
 		ossClient:     ossClient,
 	}
 }
@@ -283,8 +292,23 @@ func (cps *comicPageSvc) CreatePages(
 		}
 	}
 
+	detail := pagesEventDetail{
+		PageIDs: make([]string, len(newPages)),
+		Indexes: make([]int64, len(newPages)),
+	}
+	for i, p := range newPages {
+		detail.PageIDs[i] = p.ID
+		detail.Indexes[i] = p.Index
+	}
+
 	// Save to database
-	if err := cps.pageRepo.CreatePages(newPages); err != nil {
+	if err := cps.pageRepo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cps.pageRepo.CreatePages(tx, newPages); err != nil {
+			return err
+		}
+
+		return cps.events.record(tx, comicID, opID, COMIC_EVENT_PAGES_CREATE, detail)
+	}); err != nil {
 		zap.L().Error("Failed to create pages", zap.String("comicID", comicID), zap.Error(err))
 		return SvcRslt[[]model.CreateComicPageReply]{}, DB_FAILURE
 	}
@@ -365,7 +389,7 @@ func (cps *comicPageSvc) UpdatePageByID(opID string, args *model.PatchComicPageA
 	}
 
 	// Verify page exists
-	_, err := cps.pageRepo.GetPageByID(nil, args.ID)
+	page, err := cps.pageRepo.GetPageByID(nil, args.ID)
 	if err != nil {
 		zap.L().Error("Failed to get page for update", zap.String("pageID", args.ID), zap.Error(err))
 		return DB_FAILURE
@@ -378,17 +402,6 @@ func (cps *comicPageSvc) UpdatePageByID(opID string, args *model.PatchComicPageA
 
 	var ossKey *string
 	if args.ImageExt != nil {
-		// Get the comic ID and index of the page
-		page, err := cps.pageRepo.GetPageByID(nil, args.ID)
-		if err != nil {
-			zap.L().Error("Failed to get page for OSS key generation", zap.String("pageID", args.ID), zap.Error(err))
-			return DB_FAILURE
-		}
-		if page == nil {
-			zap.L().Warn("Page not found for OSS key generation", zap.String("pageID", args.ID))
-			return NOT_FOUND
-		}
-
 		// Dynamically generate OSS key: comic/{comic_id}/page_{index}.{ext}
 		key := fmt.Sprintf("comic/%s/page_%d.%s", page.ComicID, page.Index, *args.ImageExt)
 		ossKey = &key
@@ -401,7 +414,26 @@ func (cps *comicPageSvc) UpdatePageByID(opID string, args *model.PatchComicPageA
 		Uploaded: args.Uploaded,
 	}
 
-	if err := cps.pageRepo.UpdatePageByID(nil, patchPage); err != nil {
+	if err := cps.pageRepo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cps.pageRepo.UpdatePageByID(tx, patchPage); err != nil {
+			return err
+		}
+
+		if args.Uploaded == nil || !*args.Uploaded {
+			return nil
+		}
+
+		detail := pageEventDetail{
+			PageID: page.ID,
+			Index:  page.Index,
+		}
+
+		if err := cps.events.record(tx, page.ComicID, opID, COMIC_EVENT_PAGE_UPLOAD, detail); err != nil {
+			return err
+		}
+
+		return cps.webhooks.enqueue(tx, WEBHOOK_EVENT_PAGE_UPLOAD, page.ComicID, opID, detail)
+	}); err != nil {
 		zap.L().Error("Failed to update page", zap.String("pageID", args.ID), zap.Error(err))
 		return DB_FAILURE
 	}
@@ -409,7 +441,19 @@ func (cps *comicPageSvc) UpdatePageByID(opID string, args *model.PatchComicPageA
 	return NO_ERROR
 }
 
-func (cps *comicPageSvc) DeletePageByID(pageID string) SvcErr {
+// pagesEventDetail describes pages created in comic events.
+type pagesEventDetail struct {
+	PageIDs []string `json:"page_ids"`
+	Indexes []int64  `json:"indexes"`
+}
+
+// pageEventDetail describes an uploaded page in comic events.
+type pageEventDetail struct {
+	PageID string `json:"page_id"`
+	Index  int64  `json:"index"`
+}
+
+func (cps *comicPageSvc) DeletePageByID(opID string, meta model.ReqMeta, pageID string) SvcErr {
 	// First, get page info to construct OSS key
 	page, err := cps.pageRepo.GetPageByID(nil, pageID)
 	if err != nil {
@@ -437,7 +481,18 @@ func (cps *comicPageSvc) DeletePageByID(pageID string) SvcErr {
 	}
 
 	// OSS deletion succeeded, now delete from DB
-	if err := cps.pageRepo.DeletePageByID(nil, pageID); err != nil {
+	if err := cps.pageRepo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cps.pageRepo.DeletePageByID(tx, pageID); err != nil {
+			return err
+		}
+
+		return cps.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_PAGE,
+			EntityID:   pageID,
+			Before:     page,
+		})
+	}); err != nil {
 		// DB deletion failed after OSS deletion - this is acceptable but should be logged
 		zap.L().Error("Failed to delete page from DB after OSS deletion",
 			zap.String("pageID", pageID),
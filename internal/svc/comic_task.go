@@ -0,0 +1,546 @@
+package svc
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// ComicTaskSvc defines service operations for the task board.
+//
+// Admins open the role slots of comics as tasks, which qualified users may claim
+// on their own. Claiming a task assigns its role to the claimer, and releasing
+// the task takes the role back and reopens the task.
+type ComicTaskSvc interface {
+	RetrieveTasks(opt model.RetrieveComicTaskOpt) (SvcRslt[[]model.ComicTaskInfo], SvcErr)
+
+	CreateTask(opID string, meta model.ReqMeta, args model.CreateComicTaskArgs) (SvcRslt[string], SvcErr)
+
+	// ClaimTask returns the ID of the assignment holding the claimed role.
+	ClaimTask(opID string, meta model.ReqMeta, taskID string) (SvcRslt[string], SvcErr)
+
+	// ReleaseTask may be done by the claimer or an admin.
+	ReleaseTask(opID string, meta model.ReqMeta, taskID string) SvcErr
+
+	DeleteTask(opID string, meta model.ReqMeta, taskID string) SvcErr
+}
+
+type comicTaskSvc struct {
+	repo      repo.ComicTaskRepo
+	comicRepo repo.ComicRepo
+	asgnRepo  repo.ComicAsgnRepo
+	userRepo  repo.UserRepo
+	authz     AuthzSvc
+	audit     *auditRecorder
+	events    *comicEventRecorder
+	webhooks  *webhookQueue
+
+	// Open tasks a user may hold at once, unlimited if not positive.
+	claimLimit int
+}
+
+// NewComicTaskSvc creates a new ComicTaskSvc. None of the repos nor authz may be nil.
+func NewComicTaskSvc(
+	r repo.ComicTaskRepo,
+	cr repo.ComicRepo,
+	car repo.ComicAsgnRepo,
+	ur repo.UserRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	whr repo.WebhookRepo,
+	authz AuthzSvc,
+	claimLimit int,
+) ComicTaskSvc {
+	if r == nil {
+		panic("ComicTaskRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+	if car == nil {
+		panic("ComicAsgnRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+	if authz == nil {
+		panic("AuthzSvc cannot be nil")
+	}
+
+	return &comicTaskSvc{
+		repo:       r,
+		comicRepo:  cr,
+		asgnRepo:   car,
+		userRepo:   ur,
+		authz:      authz,
+		audit:      newAuditRecorder(alr),
+		events:     newComicEventRecorder(cer),
+		webhooks:   newWebhookQueue(whr),
+		claimLimit: claimLimit,
+	}
+}
+
+// taskEventDetail describes a task in comic events.
+type taskEventDetail struct {
+	TaskID string `json:"task_id"`
+	Role   string `json:"role"`
+	UserID string `json:"user_id"`
+	AsgnID string `json:"assignment_id"`
+}
+
+// RetrieveTasks retrieves the tasks matching opt, newest first.
+func (cts *comicTaskSvc) RetrieveTasks(opt model.RetrieveComicTaskOpt) (SvcRslt[[]model.ComicTaskInfo], SvcErr) {
+	tasks, err := cts.repo.RetrieveTasks(nil, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve tasks", zap.Error(err))
+		return SvcRslt[[]model.ComicTaskInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.ComicTaskInfo, 0, len(tasks))
+	for i := range tasks {
+		infos = append(infos, poTaskToModelTask(&tasks[i]))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// CreateTask opens a role of a comic for users to claim.
+// A role may only have one open task per comic.
+func (cts *comicTaskSvc) CreateTask(
+	opID string,
+	meta model.ReqMeta,
+	args model.CreateComicTaskArgs,
+) (SvcRslt[string], SvcErr) {
+	if !slices.Contains(po.COMIC_ASGN_ROLES, args.Role) || (args.DueAt != nil && *args.DueAt <= 0) {
+		return SvcRslt[string]{}, INVALID_TASK_DATA
+	}
+
+	comic, err := cts.comicRepo.GetComicByID(nil, args.ComicID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[string]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for task", zap.String("comicID", args.ComicID), zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	// No use in claiming a role whose work is done.
+	if !slices.Contains(asgnOpenStages[args.Role], comic.Stage) {
+		return SvcRslt[string]{}, INVALID_TASK_DATA
+	}
+
+	if _, err := cts.repo.GetOpenTaskByComicAndRole(nil, args.ComicID, args.Role); err == nil {
+		return SvcRslt[string]{}, TASK_EXISTS
+	} else if err != repo.REC_NOT_FOUND {
+		zap.L().Error("Failed to get open task", zap.String("comicID", args.ComicID), zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for task", zap.Error(err))
+		return SvcRslt[string]{}, ID_GEN_FAILURE
+	}
+
+	newTask := &po.NewComicTask{
+		ID:        id,
+		ComicID:   args.ComicID,
+		Role:      args.Role,
+		CreatorID: opID,
+	}
+
+	if !isBlank(args.Note) {
+		note := strings.TrimSpace(*args.Note)
+		newTask.Note = &note
+	}
+
+	if args.DueAt != nil {
+		dueAt := time.Unix(*args.DueAt, 0)
+		newTask.DueAt = &dueAt
+	}
+
+	if err := cts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cts.repo.CreateTask(tx, newTask); err != nil {
+			return err
+		}
+
+		return cts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_CREATE,
+			EntityType: AUDIT_ENTITY_TASK,
+			EntityID:   id,
+			After:      newTask,
+		})
+	}); err != nil {
+		zap.L().Error("Failed to create task", zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	return accept(201, id), NO_ERROR
+}
+
+// errClaimLimit rolls back a claim exceeding the claim limit.
+var errClaimLimit = errors.New("task claim limit reached")
+
+// ClaimTask claims an open task for opID and assigns its role,
+// checking the qualification of opID as pre-assignments do.
+func (cts *comicTaskSvc) ClaimTask(opID string, meta model.ReqMeta, taskID string) (SvcRslt[string], SvcErr) {
+	task, svcErr := cts.getTask(taskID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[string]{}, svcErr
+	}
+
+	f := taskRoleFields(task.Role)
+	if f == nil {
+		zap.L().Error("Task has unknown role", zap.String("taskID", taskID), zap.String("role", task.Role))
+		return SvcRslt[string]{}, INVALID_TASK_DATA
+	}
+
+	if task.ClaimedBy != nil || !slices.Contains(asgnOpenStages[task.Role], task.ComicStage) {
+		return SvcRslt[string]{}, TASK_UNAVAILABLE
+	}
+
+	preAsgn := model.PreAsgnArgs{AssigneeID: opID}
+	isRole := true
+	*f.preAsgn(&preAsgn) = &isRole
+
+	if svcErr := validatePreAssignments(cts.userRepo, []model.PreAsgnArgs{preAsgn}); svcErr != NO_ERROR {
+		return SvcRslt[string]{}, svcErr
+	}
+
+	before, err := cts.asgnRepo.GetAsgnsByUserAndComicID(nil, opID, task.ComicID)
+	if err != nil && err != repo.REC_NOT_FOUND {
+		zap.L().Error("Failed to get assignment for claim", zap.String("taskID", taskID), zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	if before != nil && f.assigned(before) != nil {
+		return SvcRslt[string]{}, TASK_ROLE_HELD
+	}
+
+	asgnID := ""
+	if before != nil {
+		asgnID = before.ID
+	} else if asgnID, err = genUUID(); err != nil {
+		zap.L().Error("Failed to generate UUID for assignment", zap.Error(err))
+		return SvcRslt[string]{}, ID_GEN_FAILURE
+	}
+
+	now := time.Now()
+
+	patch := po.PatchComicAsgn{ID: asgnID}
+	*f.patchAssigned(&patch) = &now
+	*f.patchDue(&patch) = task.DueAt
+
+	if err := cts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if cts.claimLimit > 0 {
+			// Concurrent claims of opID wait here, so that each counts the others.
+			if err := cts.repo.LockClaimsByUserID(tx, opID); err != nil {
+				return err
+			}
+
+			count, err := cts.repo.CountOpenClaimsByUserID(tx, opID, asgnOpenStages)
+			if err != nil {
+				return err
+			}
+
+			if count >= int64(cts.claimLimit) {
+				return errClaimLimit
+			}
+		}
+
+		if err := cts.repo.ClaimTask(tx, taskID, opID, now); err != nil {
+			return err
+		}
+
+		if before == nil {
+			if err := cts.asgnRepo.CreateAsgn(tx, &po.NewComicAsgn{
+				ID:      asgnID,
+				ComicID: task.ComicID,
+				UserID:  opID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := cts.asgnRepo.UpdateAsgnByID(tx, &patch); err != nil {
+			return err
+		}
+
+		after, err := cts.asgnRepo.GetAsgnByID(tx, asgnID)
+		if err != nil {
+			return err
+		}
+
+		if err := cts.events.record(tx, task.ComicID, opID, COMIC_EVENT_TASK_CLAIM, taskEventDetail{
+			TaskID: taskID,
+			Role:   task.Role,
+			UserID: opID,
+			AsgnID: asgnID,
+		}); err != nil {
+			return err
+		}
+
+		if before == nil {
+			if err := cts.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_CREATE, task.ComicID, opID, asgnEventDetail{
+				AsgnID: asgnID,
+				UserID: opID,
+				Roles:  basicAsgnRoleNames(after),
+			}); err != nil {
+				return err
+			}
+		}
+
+		entry := auditEntry{
+			Action:     AUDIT_ACTION_CREATE,
+			EntityType: AUDIT_ENTITY_ASGN,
+			EntityID:   asgnID,
+			After:      after,
+		}
+		if before != nil {
+			entry.Action = AUDIT_ACTION_UPDATE
+			entry.Before = before
+		}
+
+		return cts.audit.record(tx, opID, meta, entry)
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			// Claimed by someone else meanwhile.
+			return SvcRslt[string]{}, TASK_UNAVAILABLE
+		}
+		if err == errClaimLimit {
+			return SvcRslt[string]{}, TASK_CLAIM_LIMIT
+		}
+		zap.L().Error("Failed to claim task", zap.String("taskID", taskID), zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	return accept(200, asgnID), NO_ERROR
+}
+
+// ReleaseTask takes back the role of a claimed task along with its due date,
+// deleting the assignment if no role is left, and reopens the task.
+// If the role has been opened again meanwhile, the task is deleted instead.
+func (cts *comicTaskSvc) ReleaseTask(opID string, meta model.ReqMeta, taskID string) SvcErr {
+	task, svcErr := cts.getTask(taskID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	f := taskRoleFields(task.Role)
+	if f == nil {
+		zap.L().Error("Task has unknown role", zap.String("taskID", taskID), zap.String("role", task.Role))
+		return INVALID_TASK_DATA
+	}
+
+	if task.ClaimedBy == nil {
+		return TASK_NOT_CLAIMED
+	}
+	claimerID := *task.ClaimedBy
+
+	if claimerID != opID {
+		isAdmin, svcErr := cts.authz.IsAdmin(opID)
+		if svcErr != NO_ERROR {
+			return svcErr
+		}
+		if !isAdmin {
+			return PERMISSION_DENIED
+		}
+	}
+
+	reopened := true
+	if _, err := cts.repo.GetOpenTaskByComicAndRole(nil, task.ComicID, task.Role); err == nil {
+		reopened = false
+	} else if err != repo.REC_NOT_FOUND {
+		zap.L().Error("Failed to get open task", zap.String("comicID", task.ComicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := cts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if reopened {
+			if err := cts.repo.ReleaseTask(tx, taskID, claimerID); err != nil {
+				return err
+			}
+		} else if err := cts.repo.DeleteTaskByID(tx, taskID); err != nil {
+			return err
+		}
+
+		asgnID := ""
+
+		// The assignment may have been changed by admins since the claim.
+		before, err := cts.asgnRepo.GetAsgnsByUserAndComicID(tx, claimerID, task.ComicID)
+		if err != nil && err != repo.REC_NOT_FOUND {
+			return err
+		}
+
+		if before != nil && f.assigned(before) != nil {
+			asgnID = before.ID
+
+			if err := cts.takeBackRole(tx, opID, meta, before, f); err != nil {
+				return err
+			}
+		}
+
+		if err := cts.events.record(tx, task.ComicID, opID, COMIC_EVENT_TASK_RELEASE, taskEventDetail{
+			TaskID: taskID,
+			Role:   task.Role,
+			UserID: claimerID,
+			AsgnID: asgnID,
+		}); err != nil {
+			return err
+		}
+
+		if claimerID == opID {
+			return nil
+		}
+
+		return cts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_REVOKE,
+			EntityType: AUDIT_ENTITY_TASK,
+			EntityID:   taskID,
+			Before:     task,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			// Released by someone else meanwhile.
+			return TASK_NOT_CLAIMED
+		}
+		zap.L().Error("Failed to release task", zap.String("taskID", taskID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DeleteTask removes a task from the board.
+// The role of a claimed task stays assigned.
+func (cts *comicTaskSvc) DeleteTask(opID string, meta model.ReqMeta, taskID string) SvcErr {
+	task, svcErr := cts.getTask(taskID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := cts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cts.repo.DeleteTaskByID(tx, taskID); err != nil {
+			return err
+		}
+
+		return cts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_TASK,
+			EntityID:   taskID,
+			Before:     task,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete task", zap.String("taskID", taskID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// takeBackRole removes the role of f and its due date from an assignment,
+// deleting the assignment if no role is left.
+func (cts *comicTaskSvc) takeBackRole(
+	tx repo.Exct,
+	opID string,
+	meta model.ReqMeta,
+	before *po.BasicComicAsgn,
+	f *asgnRoleField,
+) error {
+	patch := po.PatchComicAsgn{ID: before.ID}
+	*f.patchAssigned(&patch) = &time.Time{}
+	*f.patchDue(&patch) = &time.Time{}
+	if f.role == ROLE_TRANSLATOR {
+		patch.PageRanges = json.RawMessage{}
+	}
+
+	if err := cts.asgnRepo.UpdateAsgnByID(tx, &patch); err != nil {
+		return err
+	}
+
+	after, err := cts.asgnRepo.GetAsgnByID(tx, before.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(basicAsgnRoleNames(after)) > 0 {
+		return cts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_UPDATE,
+			EntityType: AUDIT_ENTITY_ASGN,
+			EntityID:   before.ID,
+			Before:     before,
+			After:      after,
+		})
+	}
+
+	if err := cts.asgnRepo.DeleteAsgnByID(tx, before.ID); err != nil {
+		return err
+	}
+
+	if err := cts.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_DELETE, before.ComicID, opID, asgnEventDetail{
+		AsgnID: before.ID,
+		UserID: before.UserID,
+		Roles:  basicAsgnRoleNames(before),
+	}); err != nil {
+		return err
+	}
+
+	return cts.audit.record(tx, opID, meta, auditEntry{
+		Action:     AUDIT_ACTION_DELETE,
+		EntityType: AUDIT_ENTITY_ASGN,
+		EntityID:   before.ID,
+		Before:     before,
+	})
+}
+
+func (cts *comicTaskSvc) getTask(taskID string) (*po.BasicComicTask, SvcErr) {
+	task, err := cts.repo.GetTaskByID(nil, taskID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return nil, NOT_FOUND
+		}
+		zap.L().Error("Failed to get task", zap.String("taskID", taskID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	return task, NO_ERROR
+}
+
+// taskRoleFields returns the fields of role, or nil if role is unknown.
+func taskRoleFields(role string) *asgnRoleField {
+	i := slices.IndexFunc(asgnRoleFields, func(f asgnRoleField) bool { return f.role == role })
+	if i < 0 {
+		return nil
+	}
+
+	return &asgnRoleFields[i]
+}
+
+func poTaskToModelTask(task *po.BasicComicTask) model.ComicTaskInfo {
+	return model.ComicTaskInfo{
+		ID:              task.ID,
+		ComicID:         task.ComicID,
+		ComicTitle:      task.ComicTitle,
+		ComicStage:      task.ComicStage,
+		Role:            task.Role,
+		Note:            task.Note,
+		DueAt:           timePtrToInt64Ptr(task.DueAt),
+		CreatorID:       task.CreatorID,
+		ClaimedBy:       task.ClaimedBy,
+		ClaimerNickname: task.ClaimerNickname,
+		ClaimedAt:       timePtrToInt64Ptr(task.ClaimedAt),
+		CreatedAt:       task.CreatedAt.Unix(),
+		UpdatedAt:       task.UpdatedAt.Unix(),
+	}
+}
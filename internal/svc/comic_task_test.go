@@ -0,0 +1,58 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeRoleTaskRepo struct {
+	repo.ComicTaskRepo
+
+	task po.BasicComicTask
+}
+
+func (r *fakeRoleTaskRepo) GetTaskByID(repo.Exct, string) (*po.BasicComicTask, error) {
+	task := r.task
+	return &task, nil
+}
+
+func TestTaskRoleFields(t *testing.T) {
+	for _, role := range po.COMIC_ASGN_ROLES {
+		if f := taskRoleFields(role); f == nil || f.role != role {
+			t.Fatalf("role %s: got %+v", role, f)
+		}
+	}
+
+	if f := taskRoleFields("colorist"); f != nil {
+		t.Fatalf("unknown role: got %+v", f)
+	}
+}
+
+func TestTasksOfUnknownRoleRejected(t *testing.T) {
+	claimer := "u-1"
+	r := &fakeRoleTaskRepo{task: po.BasicComicTask{ID: "t-1", ComicID: "c-1", Role: "colorist"}}
+
+	cts := NewComicTaskSvc(
+		r,
+		struct{ repo.ComicRepo }{},
+		struct{ repo.ComicAsgnRepo }{},
+		struct{ repo.UserRepo }{},
+		struct{ repo.AuditLogRepo }{},
+		struct{ repo.ComicEventRepo }{},
+		struct{ repo.WebhookRepo }{},
+		fakeAdminAuthzSvc{},
+		0,
+	)
+
+	if _, svcErr := cts.ClaimTask(claimer, model.ReqMeta{}, "t-1"); svcErr != INVALID_TASK_DATA {
+		t.Fatalf("claim: got %v", svcErr)
+	}
+
+	r.task.ClaimedBy = &claimer
+	if svcErr := cts.ReleaseTask(claimer, model.ReqMeta{}, "t-1"); svcErr != INVALID_TASK_DATA {
+		t.Fatalf("release: got %v", svcErr)
+	}
+}
@@ -0,0 +1,61 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/oss"
+	"poprako-main-server/internal/repo"
+)
+
+type fakePatchComicRepo struct {
+	repo.ComicRepo
+
+	patched int
+}
+
+func (r *fakePatchComicRepo) UpdateComicByID(repo.Exct, *po.PatchComic) error {
+	r.patched++
+	return nil
+}
+
+func TestUpdateComicRejectsStageToggles(t *testing.T) {
+	r := &fakePatchComicRepo{}
+
+	cs := NewComicSvc(
+		r,
+		struct{ repo.UserRepo }{},
+		struct{ repo.ComicAsgnRepo }{},
+		struct{ repo.ComicPageRepo }{},
+		struct{ repo.ComicUnitRepo }{},
+		struct{ repo.TagRepo }{},
+		struct{ repo.ComicLikeRepo }{},
+		struct{ repo.TeamRepo }{},
+		struct{ repo.AuditLogRepo }{},
+		struct{ repo.ComicEventRepo }{},
+		struct{ repo.NotificationRepo }{},
+		struct{ repo.WebhookRepo }{},
+		t.TempDir(),
+		struct{ oss.OSSClient }{},
+	)
+
+	title := "title"
+	done, notDone := true, false
+
+	for _, args := range []model.UpdateComicArgs{
+		{ID: "c-1", Title: &title, TranslatingCompleted: &done},
+		{ID: "c-1", UploadingCompleted: &notDone},
+	} {
+		if svcErr := cs.UpdateComicByID(args); svcErr != STAGE_TOGGLES_REMOVED || svcErr.Code() != 400 {
+			t.Fatalf("%+v: got %v", args, svcErr)
+		}
+	}
+	if r.patched != 0 {
+		t.Fatalf("comic patched %d times despite stage toggles", r.patched)
+	}
+
+	if svcErr := cs.UpdateComicByID(model.UpdateComicArgs{ID: "c-1", Title: &title}); svcErr != NO_ERROR || r.patched != 1 {
+		t.Fatalf("plain update: got %v, %d patches", svcErr, r.patched)
+	}
+}
@@ -1,6 +1,9 @@
 package svc
 
 import (
+	"strings"
+	"time"
+
 	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/model/po"
 	"poprako-main-server/internal/repo"
@@ -10,30 +13,57 @@ import (
 
 // ComicUnitSvc defines service operations for comic units.
 type ComicUnitSvc interface {
-	GetUnitsByPageID(pageID string) (SvcRslt[[]model.ComicUnitInfo], SvcErr)
+	GetUnitsByPageID(pageID string, withTerms bool) (SvcRslt[[]model.ComicUnitInfo], SvcErr)
 
 	CreateUnits(opID string, newUnits []model.NewComicUnitArgs) SvcErr
 
-	UpdateUnitsByIDs(opID string, patchUnits []model.PatchComicUnitArgs) SvcErr
+	// On UNIT_CONFLICT the result holds the units changed by others.
+	UpdateUnitsByIDs(opID string, patchUnits []model.PatchComicUnitArgs) (SvcRslt[model.UnitConflictReport], SvcErr)
 
-	DeleteUnitByIDs(unitIDs []string) SvcErr
+	DeleteUnitByIDs(opID string, unitIDs []string) SvcErr
 }
 
 type comicUnitSvc struct {
-	repo repo.ComicUnitRepo
+	repo     repo.ComicUnitRepo
+	pageRepo repo.ComicPageRepo
+	termRepo repo.TermRepo
+	authz    AuthzSvc
+	notifier *notifier
+	collab   CollabSvc
 }
 
-// NewComicUnitSvc creates a new ComicUnitSvc. r must not be nil.
-func NewComicUnitSvc(r repo.ComicUnitRepo) ComicUnitSvc {
+// NewComicUnitSvc creates a new ComicUnitSvc. None of the repos, az nor collab may be nil.
+func NewComicUnitSvc(
+	r repo.ComicUnitRepo,
+	pr repo.ComicPageRepo,
+	tr repo.TermRepo,
+	nr repo.NotificationRepo,
+	az AuthzSvc,
+	collab CollabSvc,
+) ComicUnitSvc {
 	if r == nil {
 		panic("ComicUnitRepo cannot be nil")
 	}
+	if pr == nil {
+		panic("ComicPageRepo cannot be nil")
+	}
+	if tr == nil {
+		panic("TermRepo cannot be nil")
+	}
+	if az == nil {
+		panic("AuthzSvc cannot be nil")
+	}
+	if collab == nil {
+		panic("CollabSvc cannot be nil")
+	}
 
-	return &comicUnitSvc{repo: r}
+	return &comicUnitSvc{repo: r, pageRepo: pr, termRepo: tr, authz: az, notifier: newNotifier(nr), collab: collab}
 }
 
 // GetUnitsByPageID retrieves comic units by page ID.
-func (cus *comicUnitSvc) GetUnitsByPageID(pageID string) (SvcRslt[[]model.ComicUnitInfo], SvcErr) {
+// If withTerms is true, units are annotated with the terms of the
+// termbases linked to the comic.
+func (cus *comicUnitSvc) GetUnitsByPageID(pageID string, withTerms bool) (SvcRslt[[]model.ComicUnitInfo], SvcErr) {
 	units, err := cus.repo.GetUnitsByPageID(nil, pageID)
 	if err != nil {
 		zap.L().Error("Failed to get units by page ID", zap.String("pageID", pageID), zap.Error(err))
@@ -42,38 +72,93 @@ func (cus *comicUnitSvc) GetUnitsByPageID(pageID string) (SvcRslt[[]model.ComicU
 
 	// Convert po.BasicComicUnit to model.ComicUnitInfo
 	var infos []model.ComicUnitInfo
-	for _, u := range units {
-		infos = append(infos, model.ComicUnitInfo{
-			ID:                 u.ID,
-			PageID:             u.PageID,
-			Index:              u.Index,
-			XCoordinate:        u.XCoordinate,
-			YCoordinate:        u.YCoordinate,
-			IsInBox:            u.IsInBox,
-			TranslatedText:     u.TranslatedText,
-			TranslatorID:       u.TranslatorID,
-			TranslatorComment:  u.TranslatorComment,
-			ProvedText:         u.ProvedText,
-			Proved:             u.Proved,
-			ProofreaderID:      u.ProofreaderID,
-			ProofreaderComment: u.ProofreaderComment,
-			CreatorID:          u.CreatorID,
-			CreatedAt:          u.CreatedAt.Unix(),
-			UpdatedAt:          u.UpdatedAt.Unix(),
-		})
+	for i := range units {
+		infos = append(infos, unitInfo(&units[i]))
+	}
+
+	if withTerms && len(infos) > 0 {
+		if svcErr := cus.annotateTerms(pageID, infos); svcErr != NO_ERROR {
+			return SvcRslt[[]model.ComicUnitInfo]{}, svcErr
+		}
 	}
 
 	return accept(200, infos), NO_ERROR
 }
 
+func unitInfo(u *po.BasicComicUnit) model.ComicUnitInfo {
+	return model.ComicUnitInfo{
+		ID:                 u.ID,
+		PageID:             u.PageID,
+		Index:              u.Index,
+		XCoordinate:        u.XCoordinate,
+		YCoordinate:        u.YCoordinate,
+		IsInBox:            u.IsInBox,
+		TranslatedText:     u.TranslatedText,
+		TranslatorID:       u.TranslatorID,
+		TranslatorComment:  u.TranslatorComment,
+		ProvedText:         u.ProvedText,
+		Proved:             u.Proved,
+		ProofreaderID:      u.ProofreaderID,
+		ProofreaderComment: u.ProofreaderComment,
+		CreatorID:          u.CreatorID,
+		Version:            u.Version,
+		CreatedAt:          u.CreatedAt.Unix(),
+		UpdatedAt:          u.UpdatedAt.Unix(),
+	}
+}
+
+// annotateTerms annotates units of a page with glossary hits and
+// inconsistent renderings across the whole comic.
+func (cus *comicUnitSvc) annotateTerms(pageID string, infos []model.ComicUnitInfo) SvcErr {
+	page, err := cus.pageRepo.GetPageByID(nil, pageID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get page for term annotation", zap.String("pageID", pageID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	terms, err := cus.termRepo.GetTermsByComicID(nil, page.ComicID)
+	if err != nil {
+		zap.L().Error("Failed to get terms by comic ID", zap.String("comicID", page.ComicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if len(terms) == 0 {
+		return NO_ERROR
+	}
+
+	comicUnits, err := cus.repo.GetUnitsByComicID(nil, page.ComicID)
+	if err != nil {
+		zap.L().Error("Failed to get units by comic ID", zap.String("comicID", page.ComicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	annotateUnitTerms(infos, terms, comicUnits)
+
+	return NO_ERROR
+}
+
 // CreateUnits creates a batch of comic units.
+// See checkEditAccess for who may create them.
 func (cus *comicUnitSvc) CreateUnits(opID string, newUnits []model.NewComicUnitArgs) SvcErr {
 	if len(newUnits) == 0 {
 		return NO_ERROR
 	}
 
+	pages := map[string]bool{}
+	for _, u := range newUnits {
+		pages[u.PageID] = pages[u.PageID] || u.ProvedText != nil || u.Proved
+	}
+
+	if svcErr := cus.checkEditAccess(opID, pages); svcErr != NO_ERROR {
+		return svcErr
+	}
+
 	// Convert model.NewComicUnitArgs to po.NewComicUnit
 	var poUnits []po.NewComicUnit
+	var unitIDs []string
 	for _, u := range newUnits {
 		// Generate ID for each unit
 		id, err := genUUID()
@@ -81,6 +166,7 @@ func (cus *comicUnitSvc) CreateUnits(opID string, newUnits []model.NewComicUnitA
 			zap.L().Error("Failed to generate UUID for comic unit", zap.Error(err))
 			return ID_GEN_FAILURE
 		}
+		unitIDs = append(unitIDs, id)
 
 		poUnits = append(poUnits, po.NewComicUnit{
 			ID:                 id,
@@ -105,13 +191,61 @@ func (cus *comicUnitSvc) CreateUnits(opID string, newUnits []model.NewComicUnitA
 		return DB_FAILURE
 	}
 
+	cus.publishUnits(opID, PAGE_EVENT_UNITS_CREATED, unitIDs)
+
 	return NO_ERROR
 }
 
 // UpdateUnitsByIDs updates a batch of comic units by their IDs.
-func (cus *comicUnitSvc) UpdateUnitsByIDs(opID string, patchUnits []model.PatchComicUnitArgs) SvcErr {
+// See checkEditAccess for who may update them.
+// Translators are notified of new proofreader comments on their units.
+//
+// Units patched with a version are only updated if still of that version.
+// Otherwise nothing is updated, and the units changed by others are reported
+// as they are now, for the client to merge.
+func (cus *comicUnitSvc) UpdateUnitsByIDs(opID string, patchUnits []model.PatchComicUnitArgs) (SvcRslt[model.UnitConflictReport], SvcErr) {
 	if len(patchUnits) == 0 {
-		return NO_ERROR
+		return SvcRslt[model.UnitConflictReport]{}, NO_ERROR
+	}
+
+	patched := map[string]int{}
+	versioned := map[string]bool{}
+	for _, pu := range patchUnits {
+		patched[pu.ID]++
+		versioned[pu.ID] = versioned[pu.ID] || pu.Version != nil
+	}
+
+	unitIDs := make([]string, 0, len(patchUnits))
+	proofUnits := map[string]bool{}
+	for _, pu := range patchUnits {
+		// Any patch after the first would find the version bumped by the earlier one,
+		// whichever of them carries the version.
+		if versioned[pu.ID] && patched[pu.ID] > 1 {
+			zap.L().Warn("PatchComicUnitArgs repeating versioned unit", zap.String("unitID", pu.ID))
+			return SvcRslt[model.UnitConflictReport]{}, INVALID_UNIT_DATA
+		}
+
+		unitIDs = append(unitIDs, pu.ID)
+		proofUnits[pu.ID] = proofUnits[pu.ID] || pu.ProvedText != nil || pu.Proved != nil
+	}
+
+	units, svcErr := cus.getUnits(unitIDs)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.UnitConflictReport]{}, svcErr
+	}
+
+	pages := map[string]bool{}
+	for unitID, u := range units {
+		pages[u.PageID] = pages[u.PageID] || proofUnits[unitID]
+	}
+
+	if svcErr := cus.checkEditAccess(opID, pages); svcErr != NO_ERROR {
+		return SvcRslt[model.UnitConflictReport]{}, svcErr
+	}
+
+	if report := unitConflicts(patchUnits, units); report != nil {
+		svcErr = UNIT_CONFLICT
+		return SvcRslt[model.UnitConflictReport]{Code: svcErr.Code(), Data: report}, svcErr
 	}
 
 	// Convert model.PatchComicUnitArgs to po.PatchComicUnit
@@ -119,7 +253,7 @@ func (cus *comicUnitSvc) UpdateUnitsByIDs(opID string, patchUnits []model.PatchC
 	for _, pu := range patchUnits {
 		if pu.ID == "" {
 			zap.L().Error("PatchComicUnitArgs missing ID", zap.Any("patchUnit", pu))
-			return INVALID_UNIT_DATA
+			return SvcRslt[model.UnitConflictReport]{}, INVALID_UNIT_DATA
 		}
 
 		poPatch := po.PatchComicUnit{
@@ -133,6 +267,7 @@ func (cus *comicUnitSvc) UpdateUnitsByIDs(opID string, patchUnits []model.PatchC
 			ProvedText:         pu.ProvedText,
 			Proved:             pu.Proved,
 			ProofreaderComment: pu.ProofreaderComment,
+			Version:            pu.Version,
 		}
 
 		// Set translator/proofreader ID based on what's being modified
@@ -146,24 +281,276 @@ func (cus *comicUnitSvc) UpdateUnitsByIDs(opID string, patchUnits []model.PatchC
 		poPatches = append(poPatches, poPatch)
 	}
 
-	if err := cus.repo.UpdateUnitsByIDs(nil, poPatches); err != nil {
+	if err := cus.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := cus.repo.UpdateUnitsByIDs(tx, poPatches); err != nil {
+			return err
+		}
+
+		return cus.notifyComments(tx, opID, units, patchUnits)
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			// Changed by someone else since the check above.
+			return cus.reportUnitConflicts(patchUnits, unitIDs)
+		}
 		zap.L().Error("Failed to update units", zap.Error(err))
-		return DB_FAILURE
+		return SvcRslt[model.UnitConflictReport]{}, DB_FAILURE
 	}
 
-	return NO_ERROR
+	cus.publishUnits(opID, PAGE_EVENT_UNITS_UPDATED, unitIDs)
+
+	return SvcRslt[model.UnitConflictReport]{}, NO_ERROR
+}
+
+// unitConflicts reports the units patched with a version other than theirs in units,
+// or missing from units. It returns nil if there is none.
+func unitConflicts(patchUnits []model.PatchComicUnitArgs, units map[string]*po.BasicComicUnit) *model.UnitConflictReport {
+	var conflicts []model.UnitConflict
+
+	for _, pu := range patchUnits {
+		if pu.Version == nil {
+			continue
+		}
+
+		u, ok := units[pu.ID]
+		if ok && u.Version == *pu.Version {
+			continue
+		}
+
+		conflict := model.UnitConflict{UnitID: pu.ID, ExpectedVersion: *pu.Version}
+		if ok {
+			info := unitInfo(u)
+			conflict.Current = &info
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return &model.UnitConflictReport{Units: conflicts}
+}
+
+// reportUnitConflicts reads the units again to report those changed during an update.
+func (cus *comicUnitSvc) reportUnitConflicts(patchUnits []model.PatchComicUnitArgs, unitIDs []string) (SvcRslt[model.UnitConflictReport], SvcErr) {
+	lst, err := cus.repo.GetUnitsByIDs(nil, unitIDs)
+	if err != nil {
+		zap.L().Error("Failed to get units for conflict report", zap.Error(err))
+		return SvcRslt[model.UnitConflictReport]{}, DB_FAILURE
+	}
+
+	units := make(map[string]*po.BasicComicUnit, len(lst))
+	for i := range lst {
+		units[lst[i].ID] = &lst[i]
+	}
+
+	report := unitConflicts(patchUnits, units)
+	if report == nil {
+		// Not a conflict, but the page of a unit deleted in the meantime.
+		return SvcRslt[model.UnitConflictReport]{}, NOT_FOUND
+	}
+
+	svcErr := UNIT_CONFLICT
+	return SvcRslt[model.UnitConflictReport]{Code: svcErr.Code(), Data: report}, svcErr
+}
+
+// notifyComments notifies the translators of units whose proofreader comment
+// patchUnits change to something not blank.
+func (cus *comicUnitSvc) notifyComments(
+	tx repo.Exct,
+	opID string,
+	units map[string]*po.BasicComicUnit,
+	patchUnits []model.PatchComicUnitArgs,
+) error {
+	pageComics := map[string]string{}
+
+	for _, pu := range patchUnits {
+		u := units[pu.ID]
+
+		if pu.ProofreaderComment == nil || u.TranslatorID == nil {
+			continue
+		}
+
+		comment := strings.TrimSpace(*pu.ProofreaderComment)
+		if comment == "" || (u.ProofreaderComment != nil && strings.TrimSpace(*u.ProofreaderComment) == comment) {
+			continue
+		}
+
+		comicID, ok := pageComics[u.PageID]
+		if !ok {
+			page, err := cus.pageRepo.GetPageByID(tx, u.PageID)
+			if err != nil {
+				return err
+			}
+			comicID = page.ComicID
+			pageComics[u.PageID] = comicID
+		}
+
+		if err := cus.notifier.notify(tx, []string{*u.TranslatorID}, notification{
+			Kind:    NOTIFICATION_UNIT_COMMENT,
+			ComicID: comicID,
+			ActorID: opID,
+			Detail: unitCommentNotification{
+				UnitID:  u.ID,
+				PageID:  u.PageID,
+				Comment: comment,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // DeleteUnitByIDs deletes a batch of comic units by their IDs.
-func (cus *comicUnitSvc) DeleteUnitByIDs(unitIDs []string) SvcErr {
+// See checkEditAccess for who may delete them.
+func (cus *comicUnitSvc) DeleteUnitByIDs(opID string, unitIDs []string) SvcErr {
 	if len(unitIDs) == 0 {
 		return NO_ERROR
 	}
 
+	units, svcErr := cus.getUnits(unitIDs)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	pages := map[string]bool{}
+	for _, u := range units {
+		pages[u.PageID] = false
+	}
+
+	if svcErr := cus.checkEditAccess(opID, pages); svcErr != NO_ERROR {
+		return svcErr
+	}
+
 	if err := cus.repo.DeleteUnitByIDs(nil, unitIDs); err != nil {
 		zap.L().Error("Failed to delete units", zap.Error(err))
 		return DB_FAILURE
 	}
 
+	deleted := map[string][]string{}
+	for _, id := range unitIDs {
+		pageID := units[id].PageID
+		deleted[pageID] = append(deleted[pageID], id)
+	}
+
+	now := time.Now().Unix()
+	for pageID, ids := range deleted {
+		cus.collab.PublishUnits(PAGE_EVENT_UNITS_DELETED, model.UnitsEventData{
+			PageID:     pageID,
+			ActorID:    opID,
+			UnitIDs:    ids,
+			OccurredAt: now,
+		})
+	}
+
+	return NO_ERROR
+}
+
+// publishUnits tells the viewers of their pages about the units just created or updated,
+// as they are now. The change is done already, so failures are only logged.
+func (cus *comicUnitSvc) publishUnits(opID string, eventType string, unitIDs []string) {
+	units, err := cus.repo.GetUnitsByIDs(nil, unitIDs)
+	if err != nil {
+		zap.L().Error("Failed to get units to publish", zap.Error(err))
+		return
+	}
+
+	changed := map[string][]model.ComicUnitInfo{}
+	for i := range units {
+		changed[units[i].PageID] = append(changed[units[i].PageID], unitInfo(&units[i]))
+	}
+
+	now := time.Now().Unix()
+	for pageID, infos := range changed {
+		cus.collab.PublishUnits(eventType, model.UnitsEventData{
+			PageID:     pageID,
+			ActorID:    opID,
+			Units:      infos,
+			OccurredAt: now,
+		})
+	}
+}
+
+// getUnits maps each of unitIDs to the unit.
+// NOT_FOUND is returned if any unit does not exist.
+func (cus *comicUnitSvc) getUnits(unitIDs []string) (map[string]*po.BasicComicUnit, SvcErr) {
+	lst, err := cus.repo.GetUnitsByIDs(nil, unitIDs)
+	if err != nil {
+		zap.L().Error("Failed to get units by IDs", zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	units := make(map[string]*po.BasicComicUnit, len(lst))
+	for i := range lst {
+		units[lst[i].ID] = &lst[i]
+	}
+
+	for _, id := range unitIDs {
+		if _, ok := units[id]; !ok {
+			return nil, NOT_FOUND
+		}
+	}
+
+	return units, NO_ERROR
+}
+
+// checkEditAccess checks that opID may edit units on the given pages,
+// each mapped to whether proved_text or proved is edited on it.
+// Admins may edit anything. Otherwise opID must be assigned to the comic of
+// every page as translator, proofreader or reviewer, and only proofreaders
+// and reviewers may edit proved_text or proved. Translators given page ranges
+// may only edit the pages in them.
+func (cus *comicUnitSvc) checkEditAccess(opID string, pages map[string]bool) SvcErr {
+	isAdmin, svcErr := cus.authz.IsAdmin(opID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+	if isAdmin {
+		return NO_ERROR
+	}
+
+	accesses := map[string]ComicAccess{}
+
+	for pageID, proof := range pages {
+		page, err := cus.pageRepo.GetPageByID(nil, pageID)
+		if err != nil {
+			if err == repo.REC_NOT_FOUND {
+				return NOT_FOUND
+			}
+			zap.L().Error("Failed to get page for unit edit", zap.String("pageID", pageID), zap.Error(err))
+			return DB_FAILURE
+		}
+
+		access, ok := accesses[page.ComicID]
+		if !ok {
+			if access, svcErr = cus.authz.GetComicAccess(opID, page.ComicID); svcErr != NO_ERROR {
+				return svcErr
+			}
+			accesses[page.ComicID] = access
+		}
+
+		canProve := access.Roles[ROLE_PROOFREADER] || access.Roles[ROLE_REVIEWER]
+
+		if !canProve && !access.Roles[ROLE_TRANSLATOR] {
+			zap.L().Warn("Unassigned user attempted to edit units",
+				zap.String("userID", opID), zap.String("comicID", page.ComicID))
+			return PERMISSION_DENIED
+		}
+
+		if proof && !canProve {
+			zap.L().Warn("Translator attempted to edit proofreading of units",
+				zap.String("userID", opID), zap.String("comicID", page.ComicID))
+			return PERMISSION_DENIED
+		}
+
+		if !canProve && !pageRangesCover(access.PageRanges, page.Index) {
+			zap.L().Warn("Translator attempted to edit units outside their pages",
+				zap.String("userID", opID), zap.String("pageID", pageID))
+			return PAGE_NOT_ASSIGNED
+		}
+	}
+
 	return NO_ERROR
 }
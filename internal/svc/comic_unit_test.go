@@ -0,0 +1,207 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeVersionedUnitRepo struct {
+	repo.ComicUnitRepo
+
+	units []po.BasicComicUnit
+}
+
+func (r *fakeVersionedUnitRepo) GetUnitsByIDs(_ repo.Exct, unitIDs []string) ([]po.BasicComicUnit, error) {
+	var lst []po.BasicComicUnit
+	for _, u := range r.units {
+		for _, id := range unitIDs {
+			if u.ID == id {
+				lst = append(lst, u)
+				break
+			}
+		}
+	}
+	return lst, nil
+}
+
+// fakeAdminAuthzSvc lets everyone edit anything.
+type fakeAdminAuthzSvc struct{ AuthzSvc }
+
+func (fakeAdminAuthzSvc) IsAdmin(string) (bool, SvcErr) { return true, NO_ERROR }
+
+func (r *fakeVersionedUnitRepo) CreateUnits(repo.Exct, []po.NewComicUnit) error { return nil }
+
+func (r *fakeVersionedUnitRepo) DeleteUnitByIDs(repo.Exct, []string) error { return nil }
+
+// fakeComicAccessAuthzSvc gives everyone the same access to every comic.
+type fakeComicAccessAuthzSvc struct {
+	AuthzSvc
+
+	access ComicAccess
+}
+
+func (fakeComicAccessAuthzSvc) IsAdmin(string) (bool, SvcErr) { return false, NO_ERROR }
+
+func (az fakeComicAccessAuthzSvc) GetComicAccess(string, string) (ComicAccess, SvcErr) {
+	return az.access, NO_ERROR
+}
+
+type fakeUnitPageRepo struct {
+	repo.ComicPageRepo
+
+	page po.BasicComicPage
+}
+
+func (r fakeUnitPageRepo) GetPageByID(_ repo.Exct, pageID string) (*po.BasicComicPage, error) {
+	if pageID != r.page.ID {
+		return nil, repo.REC_NOT_FOUND
+	}
+	page := r.page
+	return &page, nil
+}
+
+func TestUnitEditAccess(t *testing.T) {
+	roles := func(rs ...string) map[string]bool {
+		m := map[string]bool{}
+		for _, r := range rs {
+			m[r] = true
+		}
+		return m
+	}
+
+	cases := []struct {
+		name   string
+		access ComicAccess
+		proof  bool
+		want   SvcErr
+	}{
+		{"unassigned", ComicAccess{Roles: roles()}, false, PERMISSION_DENIED},
+		{"unassigned proving", ComicAccess{Roles: roles()}, true, PERMISSION_DENIED},
+		{"typesetter", ComicAccess{Roles: roles(ROLE_TYPESETTER)}, false, PERMISSION_DENIED},
+		{"translator", ComicAccess{Roles: roles(ROLE_TRANSLATOR)}, false, NO_ERROR},
+		{"translator proving", ComicAccess{Roles: roles(ROLE_TRANSLATOR)}, true, PERMISSION_DENIED},
+		{"proofreader proving", ComicAccess{Roles: roles(ROLE_PROOFREADER)}, true, NO_ERROR},
+		{"reviewer proving", ComicAccess{Roles: roles(ROLE_REVIEWER)}, true, NO_ERROR},
+		{
+			"translator of the page",
+			ComicAccess{Roles: roles(ROLE_TRANSLATOR), PageRanges: []model.PageRange{{From: 1, To: 2}, {From: 5, To: 8}}},
+			false, NO_ERROR,
+		},
+		{
+			"translator of other pages",
+			ComicAccess{Roles: roles(ROLE_TRANSLATOR), PageRanges: []model.PageRange{{From: 1, To: 4}}},
+			false, PAGE_NOT_ASSIGNED,
+		},
+		{
+			// Page ranges only limit translating.
+			"proofreader with page ranges",
+			ComicAccess{Roles: roles(ROLE_TRANSLATOR, ROLE_PROOFREADER), PageRanges: []model.PageRange{{From: 1, To: 4}}},
+			true, NO_ERROR,
+		},
+	}
+
+	text := "text"
+	proved := true
+	stale := int64(1)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cus := NewComicUnitSvc(
+				&fakeVersionedUnitRepo{units: []po.BasicComicUnit{{ID: "unit-1", PageID: "p-1", Version: 2}}},
+				fakeUnitPageRepo{page: po.BasicComicPage{ID: "p-1", ComicID: "c-1", Index: 5}},
+				struct{ repo.TermRepo }{},
+				struct{ repo.NotificationRepo }{},
+				fakeComicAccessAuthzSvc{access: c.access},
+				newTestCollabSvc(),
+			)
+
+			newUnit := model.NewComicUnitArgs{PageID: "p-1", TranslatedText: &text}
+			patch := model.PatchComicUnitArgs{ID: "unit-1", TranslatedText: &text, Version: &stale}
+			if c.proof {
+				newUnit.ProvedText, newUnit.Proved = &text, true
+				patch.ProvedText, patch.Proved = &text, &proved
+			}
+
+			if svcErr := cus.CreateUnits("u-1", []model.NewComicUnitArgs{newUnit}); svcErr != c.want {
+				t.Errorf("create: got %v, want %v", svcErr, c.want)
+			}
+
+			// The stale version stops updates let through before they are written.
+			wantUpdate := c.want
+			if wantUpdate == NO_ERROR {
+				wantUpdate = UNIT_CONFLICT
+			}
+			if _, svcErr := cus.UpdateUnitsByIDs("u-1", []model.PatchComicUnitArgs{patch}); svcErr != wantUpdate {
+				t.Errorf("update: got %v, want %v", svcErr, wantUpdate)
+			}
+
+			// Deleting does not touch the proofreading.
+			if !c.proof {
+				if svcErr := cus.DeleteUnitByIDs("u-1", []string{"unit-1"}); svcErr != c.want {
+					t.Errorf("delete: got %v, want %v", svcErr, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUnitsReportsConflicts(t *testing.T) {
+	translated := "theirs"
+	r := &fakeVersionedUnitRepo{units: []po.BasicComicUnit{
+		{ID: "unit-1", PageID: "p-1", Version: 3},
+		{ID: "unit-2", PageID: "p-1", Version: 5, TranslatedText: &translated},
+	}}
+
+	cus := NewComicUnitSvc(r, struct{ repo.ComicPageRepo }{}, struct{ repo.TermRepo }{}, struct{ repo.NotificationRepo }{}, fakeAdminAuthzSvc{}, newTestCollabSvc())
+
+	mine := "mine"
+	v3, v4 := int64(3), int64(4)
+
+	res, svcErr := cus.UpdateUnitsByIDs("u-1", []model.PatchComicUnitArgs{
+		{ID: "unit-1", TranslatedText: &mine, Version: &v3},
+		{ID: "unit-2", TranslatedText: &mine, Version: &v4},
+	})
+	if svcErr != UNIT_CONFLICT || svcErr.Code() != 409 {
+		t.Fatalf("got %v, want UNIT_CONFLICT", svcErr)
+	}
+
+	conflicts := res.Data.Units
+	if len(conflicts) != 1 || conflicts[0].UnitID != "unit-2" || conflicts[0].ExpectedVersion != 4 {
+		t.Fatalf("unexpected conflicts %+v", conflicts)
+	}
+	if cur := conflicts[0].Current; cur == nil || cur.Version != 5 || *cur.TranslatedText != "theirs" {
+		t.Fatalf("unexpected server state %+v", cur)
+	}
+
+	// A unit patched twice would conflict with itself if any of the patches is versioned.
+	for _, patches := range [][2]*int64{{&v3, &v3}, {nil, &v3}, {&v3, nil}} {
+		if _, svcErr := cus.UpdateUnitsByIDs("u-1", []model.PatchComicUnitArgs{
+			{ID: "unit-1", TranslatedText: &mine, Version: patches[0]},
+			{ID: "unit-2", TranslatedText: &mine},
+			{ID: "unit-1", TranslatedText: &mine, Version: patches[1]},
+		}); svcErr != INVALID_UNIT_DATA {
+			t.Fatalf("repeated versioned unit %v: got %v", patches, svcErr)
+		}
+	}
+}
+
+func TestUnitConflicts(t *testing.T) {
+	v1, v2 := int64(1), int64(2)
+	units := map[string]*po.BasicComicUnit{"unit-1": {ID: "unit-1", Version: 2}}
+
+	if report := unitConflicts([]model.PatchComicUnitArgs{{ID: "unit-1", Version: &v2}, {ID: "unit-9"}}, units); report != nil {
+		t.Fatalf("conflicts reported for matching or unversioned patches: %+v", report)
+	}
+
+	report := unitConflicts([]model.PatchComicUnitArgs{{ID: "unit-1", Version: &v1}, {ID: "unit-9", Version: &v1}}, units)
+	if report == nil || len(report.Units) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	// Deleted units have no server state.
+	if report.Units[0].Current == nil || report.Units[1].Current != nil {
+		t.Fatalf("unexpected server states %+v", report.Units)
+	}
+}
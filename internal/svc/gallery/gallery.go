@@ -0,0 +1,123 @@
+package gallery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Supported source sites.
+const (
+	SOURCE_EHENTAI = "ehentai"
+	SOURCE_PICA    = "pica"
+)
+
+// Meta holds the parts of a gallery metadata document used for tag mapping.
+type Meta struct {
+	Source string
+
+	// Raw site tags, trimmed and deduplicated.
+	Tags []string
+}
+
+// ehentaiDoc is the reply of the e-hentai gallery API (method gdata).
+type ehentaiDoc struct {
+	GMetadata []struct {
+		Error string   `json:"error"`
+		Tags  []string `json:"tags"`
+	} `json:"gmetadata"`
+}
+
+// picaDoc is the reply of the pica comic detail API.
+type picaDoc struct {
+	Data *struct {
+		Comic *struct {
+			Categories []string `json:"categories"`
+			Tags       []string `json:"tags"`
+		} `json:"comic"`
+	} `json:"data"`
+}
+
+// ParseMeta detects the source site of a gallery metadata document and extracts its tags.
+// An e-hentai document must describe exactly one gallery.
+func ParseMeta(raw []byte) (Meta, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return Meta{}, fmt.Errorf("failed to decode gallery metadata: %w", err)
+	}
+
+	if _, ok := probe["gmetadata"]; ok {
+		var doc ehentaiDoc
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return Meta{}, fmt.Errorf("failed to decode e-hentai metadata: %w", err)
+		}
+
+		if len(doc.GMetadata) != 1 {
+			return Meta{}, fmt.Errorf("expected 1 gallery in e-hentai metadata, got %d", len(doc.GMetadata))
+		}
+
+		g := doc.GMetadata[0]
+		if g.Error != "" {
+			return Meta{}, fmt.Errorf("e-hentai metadata carries an error: %s", g.Error)
+		}
+
+		return Meta{Source: SOURCE_EHENTAI, Tags: normalizeTags(g.Tags)}, nil
+	}
+
+	if _, ok := probe["data"]; ok {
+		var doc picaDoc
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return Meta{}, fmt.Errorf("failed to decode pica metadata: %w", err)
+		}
+
+		if doc.Data == nil || doc.Data.Comic == nil {
+			return Meta{}, errors.New("pica metadata has no comic")
+		}
+
+		// Pica categories act as coarse tags, so both are mapped.
+		tags := append(doc.Data.Comic.Categories, doc.Data.Comic.Tags...)
+
+		return Meta{Source: SOURCE_PICA, Tags: normalizeTags(tags)}, nil
+	}
+
+	return Meta{}, errors.New("unknown gallery metadata format")
+}
+
+// Candidates returns the strings to look up in the candidate column of the source.
+// E-hentai tags are namespaced ("female:full color"), and both the namespaced
+// and the bare form are returned so candidates may be written either way.
+func (m Meta) Candidates() []string {
+	if m.Source != SOURCE_EHENTAI {
+		return m.Tags
+	}
+
+	res := make([]string, 0, len(m.Tags)*2)
+	for _, t := range m.Tags {
+		res = append(res, t)
+		if _, bare, ok := strings.Cut(t, ":"); ok && bare != "" {
+			res = append(res, bare)
+		}
+	}
+
+	return normalizeTags(res)
+}
+
+func normalizeTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+
+	return res
+}
@@ -0,0 +1,94 @@
+package gallery
+
+import (
+	"slices"
+	"testing"
+)
+
+// Trimmed replies of the e-hentai gdata API and the pica comic detail API.
+const (
+	ehentaiStub = `{"gmetadata": [{
+		"gid": 618395, "token": "0439fa3666", "title": "(Kouroumu 8) [Handful☆Happiness!] Touhou Tiny Games",
+		"category": "Non-H", "posted": "1291155314", "filecount": "20",
+		"tags": ["parody:touhou project", "character:hong meiling", " female:full color ", "translated", "parody:touhou project", ""]
+	}]}`
+
+	picaStub = `{"code": 200, "message": "success", "data": {"comic": {
+		"_id": "5822a6e3ad7ede654696e482", "title": "Tiny Games", "author": "Handful Happiness",
+		"categories": ["東方", "短篇"],
+		"tags": ["全彩", "東方", " 紅美鈴 "],
+		"pagesCount": 20, "epsCount": 1, "finished": true
+	}}}`
+)
+
+func TestParseMeta(t *testing.T) {
+	cases := []struct {
+		name       string
+		raw        string
+		want       Meta
+		candidates []string
+	}{
+		{
+			name: "e-hentai",
+			raw:  ehentaiStub,
+			want: Meta{
+				Source: SOURCE_EHENTAI,
+				Tags:   []string{"parody:touhou project", "character:hong meiling", "female:full color", "translated"},
+			},
+			// Namespaced tags are looked up bare as well.
+			candidates: []string{
+				"parody:touhou project", "touhou project",
+				"character:hong meiling", "hong meiling",
+				"female:full color", "full color",
+				"translated",
+			},
+		},
+		{
+			// Categories act as coarse tags, merged with the tags.
+			name:       "pica",
+			raw:        picaStub,
+			want:       Meta{Source: SOURCE_PICA, Tags: []string{"東方", "短篇", "全彩", "紅美鈴"}},
+			candidates: []string{"東方", "短篇", "全彩", "紅美鈴"},
+		},
+		{
+			name:       "pica without categories",
+			raw:        `{"data": {"comic": {"tags": ["全彩"]}}}`,
+			want:       Meta{Source: SOURCE_PICA, Tags: []string{"全彩"}},
+			candidates: []string{"全彩"},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := ParseMeta([]byte(c.raw))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got.Source != c.want.Source || !slices.Equal(got.Tags, c.want.Tags) {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+		if candidates := got.Candidates(); !slices.Equal(candidates, c.candidates) {
+			t.Errorf("%s: got candidates %q, want %q", c.name, candidates, c.candidates)
+		}
+	}
+}
+
+func TestParseMetaRejects(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+	}{
+		{"no galleries", `{"gmetadata": []}`},
+		{"two galleries", `{"gmetadata": [{"gid": 1, "tags": ["translated"]}, {"gid": 2, "tags": ["translated"]}]}`},
+		{"gallery error", `{"gmetadata": [{"gid": 618395, "error": "Key missing, or incorrect key provided."}]}`},
+		{"pica without comic", `{"code": 400, "message": "not found", "data": {}}`},
+		{"unknown format", `{"id": 618395, "tags": ["translated"]}`},
+		{"not an object", `["translated"]`},
+		{"not json", `gid=618395`},
+	}
+
+	for _, c := range cases {
+		if meta, err := ParseMeta([]byte(c.raw)); err == nil {
+			t.Errorf("%s: got %+v, want an error", c.name, meta)
+		}
+	}
+}
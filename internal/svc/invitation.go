@@ -1,28 +1,93 @@
 package svc
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
 	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/model/po"
 	"poprako-main-server/internal/repo"
+	invitationPkg "poprako-main-server/internal/svc/invitation"
 
 	"go.uber.org/zap"
 )
 
+// Invitation statuses.
+const (
+	INV_STATUS_PENDING  = "pending"
+	INV_STATUS_EXPIRED  = "expired"
+	INV_STATUS_REVOKED  = "revoked"
+	INV_STATUS_CONSUMED = "consumed"
+)
+
+// Statuses of rows of bulk invitation files.
+const (
+	INV_ROW_CREATED  = "created"
+	INV_ROW_VALID    = "valid"
+	INV_ROW_REJECTED = "rejected"
+)
+
+// Rows accepted per bulk invitation file.
+const maxBulkInvitations = 200
+
+const (
+	// Crockford's base32 alphabet, free of letters mistaken for digits.
+	invCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// 60 bits of entropy.
+	invCodeLen = 12
+)
+
 type InvitationSvc interface {
 	GetInvitationInfos(opID string) (SvcRslt[[]model.InvitationInfo], SvcErr)
 
-	CreateInvitation(opID string, args model.CreateInvitationArgs) (SvcRslt[model.CreateInvitationReply], SvcErr)
+	CreateInvitation(opID string, meta model.ReqMeta, args model.CreateInvitationArgs) (SvcRslt[model.CreateInvitationReply], SvcErr)
+
+	RegenerateInvitationCode(opID string, meta model.ReqMeta, invitationID string) (SvcRslt[model.CreateInvitationReply], SvcErr)
+
+	RevokeInvitationByID(opID string, meta model.ReqMeta, invitationID string) SvcErr
+
+	BulkCreateInvitations(
+		opID string,
+		meta model.ReqMeta,
+		reader io.Reader,
+		dryRun bool,
+	) (SvcRslt[model.BulkCreateInvitationsReply], SvcErr)
 }
 
 type invitationSvc struct {
 	userRepo repo.UserRepo
 	invRepo  repo.InvitationRepo
+	authz    AuthzSvc
+	audit    *auditRecorder
+	invExp   time.Duration
 }
 
-func NewInvitationSvc(invRepo repo.InvitationRepo, userRepo repo.UserRepo) InvitationSvc {
+func NewInvitationSvc(
+	invRepo repo.InvitationRepo,
+	userRepo repo.UserRepo,
+	alr repo.AuditLogRepo,
+	az AuthzSvc,
+	invExpSecs int64,
+) InvitationSvc {
+	if az == nil {
+		panic("AuthzSvc cannot be nil")
+	}
+	if invExpSecs <= 0 {
+		panic("invExpSecs must be positive")
+	}
+
 	return &invitationSvc{
 		invRepo:  invRepo,
 		userRepo: userRepo,
+		authz:    az,
+		audit:    newAuditRecorder(alr),
+		invExp:   time.Duration(invExpSecs) * time.Second,
 	}
 }
 
@@ -32,16 +97,8 @@ func (is *invitationSvc) GetInvitationInfos(
 	SvcRslt[[]model.InvitationInfo],
 	SvcErr,
 ) {
-	opBasic, err := is.userRepo.GetUserByID(nil, opID)
-	if err != nil {
-		zap.L().Error("Failed to get user basics by ID during invitation", zap.String("userID", opID), zap.Error(err))
-		return SvcRslt[[]model.InvitationInfo]{}, DB_FAILURE
-	}
-
-	// Check whether operator is admin.
-	if !opBasic.IsAdmin {
-		zap.L().Warn("Non-admin user attempted to invite user", zap.String("userID", opID))
-		return SvcRslt[[]model.InvitationInfo]{}, PERMISSION_DENIED
+	if svcErr := requireAdmin(is.authz, opID); svcErr != NO_ERROR {
+		return SvcRslt[[]model.InvitationInfo]{}, svcErr
 	}
 
 	invitations, err := is.invRepo.RetrieveInvitations(nil)
@@ -49,6 +106,8 @@ func (is *invitationSvc) GetInvitationInfos(
 		return SvcRslt[[]model.InvitationInfo]{}, DB_FAILURE
 	}
 
+	now := time.Now()
+
 	var infos []model.InvitationInfo
 
 	for _, inv := range invitations {
@@ -56,14 +115,16 @@ func (is *invitationSvc) GetInvitationInfos(
 			ID:                inv.ID,
 			InvitorID:         inv.InvitorID,
 			InviteeQQ:         inv.InviteeQQ,
-			InvCode:           inv.InvCode,
 			AssignTranslator:  inv.AssignTranslator,
 			AssignProofreader: inv.AssignProofreader,
 			AssignTypesetter:  inv.AssignTypesetter,
 			AssignRedrawer:    inv.AssignRedrawer,
 			AssignReviewer:    inv.AssignReviewer,
 			AssignUploader:    inv.AssignUploader,
+			Note:              inv.Note,
 			Pending:           inv.Pending,
+			Status:            invitationStatus(&inv, now),
+			ExpiresAt:         inv.ExpiresAt.Unix(),
 			CreatedAt:         inv.CreatedAt.Unix(),
 		})
 	}
@@ -71,47 +132,384 @@ func (is *invitationSvc) GetInvitationInfos(
 	return accept(200, infos), NO_ERROR
 }
 
+// CreateInvitation invites a QQ that is neither a member
+// nor holding a usable invitation yet.
+// The code is only returned here and by RegenerateInvitationCode, as only its hash is kept.
 func (is *invitationSvc) CreateInvitation(
 	opID string,
+	meta model.ReqMeta,
 	args model.CreateInvitationArgs,
 ) (
 	SvcRslt[model.CreateInvitationReply],
 	SvcErr,
 ) {
-	opBasic, err := is.userRepo.GetUserByID(nil, opID)
-	if err != nil {
-		zap.L().Error("Failed to get user basics by ID during invitation", zap.String("userID", opID), zap.Error(err))
+	if svcErr := requireAdmin(is.authz, opID); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateInvitationReply]{}, svcErr
+	}
+
+	// Verification passed.
+
+	if !isValidQQ(args.InviteeQQ) {
+		zap.L().Warn("Invalid QQ to invite", zap.String("qq", args.InviteeQQ))
+		return SvcRslt[model.CreateInvitationReply]{}, INVALID_QQ_DATA
+	}
+
+	if svcErr := is.checkInvitee(args.InviteeQQ); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateInvitationReply]{}, svcErr
+	}
+
+	newInvitation, invCode, svcErr := is.newInvitation(opID, args, time.Now())
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.CreateInvitationReply]{}, svcErr
+	}
+
+	if err := is.invRepo.Exct().Transaction(func(tx repo.Exct) error {
+		return is.createInvitation(tx, opID, meta, newInvitation)
+	}); err != nil {
+		if err == repo.DUPICATE_RECORD {
+			// Invited meanwhile.
+			return SvcRslt[model.CreateInvitationReply]{}, INV_PENDING_EXISTING
+		}
+		zap.L().Error("Failed to create invitation", zap.String("qq", args.InviteeQQ), zap.Error(err))
 		return SvcRslt[model.CreateInvitationReply]{}, DB_FAILURE
 	}
 
-	// Check whether operator is admin.
-	if !opBasic.IsAdmin {
-		zap.L().Warn("Non-admin user attempted to invite user", zap.String("userID", opID))
-		return SvcRslt[model.CreateInvitationReply]{}, PERMISSION_DENIED
+	return accept(200, model.CreateInvitationReply{
+		ID:        newInvitation.ID,
+		InvCode:   invCode,
+		ExpiresAt: newInvitation.ExpiresAt.Unix(),
+	}), NO_ERROR
+}
+
+// BulkCreateInvitations invites every QQ of a CSV file with columns qq,roles,note,
+// checking each row as CreateInvitation does.
+// Rejected rows are reported and skipped; the others are created in one transaction.
+// In dry-run mode nothing is written.
+func (is *invitationSvc) BulkCreateInvitations(
+	opID string,
+	meta model.ReqMeta,
+	reader io.Reader,
+	dryRun bool,
+) (
+	SvcRslt[model.BulkCreateInvitationsReply],
+	SvcErr,
+) {
+	if svcErr := requireAdmin(is.authz, opID); svcErr != NO_ERROR {
+		return SvcRslt[model.BulkCreateInvitationsReply]{}, svcErr
 	}
 
-	// Verification passed.
+	parsed, err := invitationPkg.ParseCSV(reader)
+	if err != nil {
+		zap.L().Warn("Failed to parse bulk invitation file", zap.Error(err))
+		return SvcRslt[model.BulkCreateInvitationsReply]{}, INVALID_INV_FILE_DATA
+	}
+
+	if len(parsed) > maxBulkInvitations {
+		zap.L().Warn("Bulk invitation file too large", zap.Int("rows", len(parsed)))
+		return SvcRslt[model.BulkCreateInvitationsReply]{}, INV_BATCH_TOO_LARGE
+	}
+
+	reply := model.BulkCreateInvitationsReply{
+		DryRun: dryRun,
+		Total:  len(parsed),
+		Rows:   make([]model.BulkInvitationRow, 0, len(parsed)),
+	}
+
+	now := time.Now()
+
+	// Indexes into reply.Rows, parallel to the invitations to create.
+	var (
+		accepted       []int
+		newInvitations []*po.NewInvitation
+		issued         []invitationPkg.IssuedInvitation
+	)
+	seen := make(map[string]struct{}, len(parsed))
+
+	for _, p := range parsed {
+		row := model.BulkInvitationRow{
+			Line:      p.Line,
+			InviteeQQ: p.InviteeQQ,
+			Roles:     p.Roles,
+			Note:      p.Note,
+			Status:    INV_ROW_VALID,
+		}
+
+		args, svcErr := bulkRowToArgs(p)
+		if svcErr == NO_ERROR {
+			if _, ok := seen[p.InviteeQQ]; ok {
+				svcErr = INV_DUPLICATE_QQ
+			} else {
+				seen[p.InviteeQQ] = struct{}{}
+				svcErr = is.checkInvitee(p.InviteeQQ)
+			}
+		}
+
+		var newInvitation *po.NewInvitation
+		if svcErr == NO_ERROR && !dryRun {
+			var invCode string
+			newInvitation, invCode, svcErr = is.newInvitation(opID, args, now)
+			if svcErr == NO_ERROR {
+				row.ID = newInvitation.ID
+				row.InvCode = invCode
+				row.ExpiresAt = newInvitation.ExpiresAt.Unix()
+				issued = append(issued, invitationPkg.IssuedInvitation{
+					InviteeQQ: p.InviteeQQ,
+					InvCode:   invCode,
+					ExpiresAt: newInvitation.ExpiresAt,
+					Roles:     p.Roles,
+					Note:      p.Note,
+				})
+			}
+		}
+
+		if svcErr == DB_FAILURE || svcErr == ID_GEN_FAILURE {
+			// Not a problem of the row, so the whole batch fails.
+			return SvcRslt[model.BulkCreateInvitationsReply]{}, svcErr
+		}
+		if svcErr != NO_ERROR {
+			row.Status = INV_ROW_REJECTED
+			row.Error = svcErr.Msg()
+		} else {
+			reply.Created++
+			if newInvitation != nil {
+				accepted = append(accepted, len(reply.Rows))
+				newInvitations = append(newInvitations, newInvitation)
+			}
+		}
+
+		reply.Rows = append(reply.Rows, row)
+	}
+
+	if dryRun || len(newInvitations) == 0 {
+		return accept(200, reply), NO_ERROR
+	}
+
+	// Invitations of rows invited meanwhile are skipped, each row being created in a savepoint.
+	var created []invitationPkg.IssuedInvitation
+
+	if err := is.invRepo.Exct().Transaction(func(tx repo.Exct) error {
+		for i, newInvitation := range newInvitations {
+			err := tx.Transaction(func(rowTx repo.Exct) error {
+				return is.createInvitation(rowTx, opID, meta, newInvitation)
+			})
+			if err == repo.DUPICATE_RECORD {
+				pending := INV_PENDING_EXISTING
+				reply.Rows[accepted[i]] = model.BulkInvitationRow{
+					Line:      reply.Rows[accepted[i]].Line,
+					InviteeQQ: newInvitation.InviteeQQ,
+					Roles:     reply.Rows[accepted[i]].Roles,
+					Note:      reply.Rows[accepted[i]].Note,
+					Status:    INV_ROW_REJECTED,
+					Error:     pending.Msg(),
+				}
+				reply.Created--
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Rows[accepted[i]].Status = INV_ROW_CREATED
+			created = append(created, issued[i])
+		}
+
+		return nil
+	}); err != nil {
+		zap.L().Error("Failed to create bulk invitations", zap.Int("count", len(newInvitations)), zap.Error(err))
+		return SvcRslt[model.BulkCreateInvitationsReply]{}, DB_FAILURE
+	}
+
+	if len(created) == 0 {
+		return accept(200, reply), NO_ERROR
+	}
+
+	var sheet bytes.Buffer
+	if err := invitationPkg.WriteCodeSheet(&sheet, created); err != nil {
+		// The invitations exist and the codes are in the rows, so do not fail.
+		zap.L().Error("Failed to write invitation code sheet", zap.Error(err))
+	} else {
+		reply.CodeSheet = sheet.String()
+		reply.CodeSheetName = fmt.Sprintf("invitations-%s.csv", now.Format("20060102-150405"))
+	}
+
+	return accept(200, reply), NO_ERROR
+}
+
+// RegenerateInvitationCode issues a new code for an invitation that is
+// neither consumed nor revoked, and restarts its expiry.
+// The previous code stops working. This is how invitations are resent.
+func (is *invitationSvc) RegenerateInvitationCode(
+	opID string,
+	meta model.ReqMeta,
+	invitationID string,
+) (
+	SvcRslt[model.CreateInvitationReply],
+	SvcErr,
+) {
+	if svcErr := requireAdmin(is.authz, opID); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateInvitationReply]{}, svcErr
+	}
+
+	before, svcErr := is.getInvitation(invitationID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.CreateInvitationReply]{}, svcErr
+	}
+
+	invCode, err := genInvCode()
+	if err != nil {
+		zap.L().Error("Failed to generate invitation code", zap.Error(err))
+		return SvcRslt[model.CreateInvitationReply]{}, ID_GEN_FAILURE
+	}
 
-	// Check whether invitee QQ is already registered.
-	_, err = is.userRepo.GetUserByQQ(nil, args.InviteeQQ)
-	if err != nil && err != repo.REC_NOT_FOUND {
-		zap.L().Error("Failed to check existing user by QQ during invitation", zap.String("qq", args.InviteeQQ), zap.Error(err))
+	expiresAt := time.Now().Add(is.invExp)
+
+	if err := is.invRepo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := is.invRepo.RegenerateInvitationCode(tx, invitationID, hashInvCode(invCode), expiresAt); err != nil {
+			return err
+		}
+
+		after, err := is.invRepo.GetInvitationByID(tx, invitationID)
+		if err != nil {
+			return err
+		}
+
+		return is.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_REGENERATE,
+			EntityType: AUDIT_ENTITY_INVITATION,
+			EntityID:   invitationID,
+			Before:     before,
+			After:      after,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.CreateInvitationReply]{}, INV_NOT_PENDING
+		}
+		zap.L().Error("Failed to regenerate invitation code", zap.String("invitationID", invitationID), zap.Error(err))
 		return SvcRslt[model.CreateInvitationReply]{}, DB_FAILURE
 	}
 
+	return accept(200, model.CreateInvitationReply{
+		ID:        invitationID,
+		InvCode:   invCode,
+		ExpiresAt: expiresAt.Unix(),
+	}), NO_ERROR
+}
+
+// RevokeInvitationByID revokes an invitation that is neither consumed nor revoked.
+func (is *invitationSvc) RevokeInvitationByID(opID string, meta model.ReqMeta, invitationID string) SvcErr {
+	if svcErr := requireAdmin(is.authz, opID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	before, svcErr := is.getInvitation(invitationID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := is.invRepo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := is.invRepo.RevokeInvitationByID(tx, invitationID); err != nil {
+			return err
+		}
+
+		after, err := is.invRepo.GetInvitationByID(tx, invitationID)
+		if err != nil {
+			return err
+		}
+
+		return is.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_REVOKE,
+			EntityType: AUDIT_ENTITY_INVITATION,
+			EntityID:   invitationID,
+			Before:     before,
+			After:      after,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return INV_NOT_PENDING
+		}
+		zap.L().Error("Failed to revoke invitation", zap.String("invitationID", invitationID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// checkInvitee checks that qq is neither a member
+// nor holding a usable invitation yet.
+func (is *invitationSvc) checkInvitee(qq string) SvcErr {
+	_, err := is.userRepo.GetUserByQQ(nil, qq)
+	if err == nil {
+		zap.L().Warn("Attempted to invite existing user", zap.String("qq", qq))
+		return USER_EXISTING
+	}
+	if err != repo.REC_NOT_FOUND {
+		zap.L().Error("Failed to check existing user by QQ during invitation", zap.String("qq", qq), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	_, err = is.invRepo.GetInvitationByQQ(nil, qq)
+	if err == nil {
+		zap.L().Warn("Attempted to invite QQ with pending invitation", zap.String("qq", qq))
+		return INV_PENDING_EXISTING
+	}
+	if err != repo.REC_NOT_FOUND {
+		zap.L().Error("Failed to check pending invitation by QQ", zap.String("qq", qq), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// createInvitation creates an invitation within tx, superseding the expired ones of the invitee.
+// repo.DUPICATE_RECORD is returned if the invitee has another invitation in use.
+func (is *invitationSvc) createInvitation(
+	tx repo.Exct,
+	opID string,
+	meta model.ReqMeta,
+	newInvitation *po.NewInvitation,
+) error {
+	if err := is.invRepo.SupersedeExpiredInvitations(tx, newInvitation.InviteeQQ); err != nil {
+		return err
+	}
+
+	if err := is.invRepo.CreateInvitations(tx, newInvitation); err != nil {
+		return err
+	}
+
+	return is.audit.record(tx, opID, meta, auditEntry{
+		Action:     AUDIT_ACTION_CREATE,
+		EntityType: AUDIT_ENTITY_INVITATION,
+		EntityID:   newInvitation.ID,
+		After:      newInvitation,
+	})
+}
+
+// newInvitation builds an invitation expiring invExp after now,
+// returning it along with its plain code.
+func (is *invitationSvc) newInvitation(
+	opID string,
+	args model.CreateInvitationArgs,
+	now time.Time,
+) (*po.NewInvitation, string, SvcErr) {
 	newID, err := genUUID()
 	if err != nil {
 		zap.L().Error("Failed to generate UUID for new invitation", zap.Error(err))
-		return SvcRslt[model.CreateInvitationReply]{}, ID_GEN_FAILURE
+		return nil, "", ID_GEN_FAILURE
 	}
 
-	invCode := genInvCode(newID)
+	invCode, err := genInvCode()
+	if err != nil {
+		zap.L().Error("Failed to generate invitation code", zap.Error(err))
+		return nil, "", ID_GEN_FAILURE
+	}
 
 	newInvitation := &po.NewInvitation{
-		ID:        newID,
-		InvitorID: opID,
-		InviteeQQ: args.InviteeQQ,
-		InvCode:   invCode,
+		ID:          newID,
+		InvitorID:   opID,
+		InviteeQQ:   args.InviteeQQ,
+		InvCodeHash: hashInvCode(invCode),
+		Note:        args.Note,
+		ExpiresAt:   now.Add(is.invExp),
 	}
 
 	if args.AssignTranslator != nil {
@@ -133,16 +531,103 @@ func (is *invitationSvc) CreateInvitation(
 		newInvitation.AssignUploader = *args.AssignUploader
 	}
 
-	if err := is.invRepo.CreateInvitations(nil, newInvitation); err != nil {
-		return SvcRslt[model.CreateInvitationReply]{}, DB_FAILURE
+	return newInvitation, invCode, NO_ERROR
+}
+
+func (is *invitationSvc) getInvitation(invitationID string) (*po.BasicInvitation, SvcErr) {
+	inv, err := is.invRepo.GetInvitationByID(nil, invitationID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return nil, NOT_FOUND
+		}
+		zap.L().Error("Failed to get invitation by ID", zap.String("invitationID", invitationID), zap.Error(err))
+		return nil, DB_FAILURE
 	}
 
-	return accept(200, model.CreateInvitationReply{
-		InvCode: invCode,
-	}), NO_ERROR
+	return inv, NO_ERROR
 }
 
-func genInvCode(newID string) string {
-	// To simplify, just use UUID
-	return newID[len(newID)-6:]
+func invitationStatus(inv *po.BasicInvitation, now time.Time) string {
+	switch {
+	case !inv.Pending:
+		return INV_STATUS_CONSUMED
+	case inv.RevokedAt != nil:
+		return INV_STATUS_REVOKED
+	case !now.Before(inv.ExpiresAt):
+		return INV_STATUS_EXPIRED
+	default:
+		return INV_STATUS_PENDING
+	}
+}
+
+// bulkRowToArgs validates a row of a bulk invitation file
+// and converts it to the arguments of a single invitation.
+func bulkRowToArgs(p invitationPkg.ParsedInvitation) (model.CreateInvitationArgs, SvcErr) {
+	if !isValidQQ(p.InviteeQQ) {
+		return model.CreateInvitationArgs{}, INVALID_QQ_DATA
+	}
+
+	args := model.CreateInvitationArgs{InviteeQQ: p.InviteeQQ, Note: p.Note}
+	assigned := true
+
+	for _, role := range p.Roles {
+		switch role {
+		case ROLE_TRANSLATOR:
+			args.AssignTranslator = &assigned
+		case ROLE_PROOFREADER:
+			args.AssignProofreader = &assigned
+		case ROLE_TYPESETTER:
+			args.AssignTypesetter = &assigned
+		case ROLE_REDRAWER:
+			args.AssignRedrawer = &assigned
+		case ROLE_REVIEWER:
+			args.AssignReviewer = &assigned
+		case ROLE_UPLOADER:
+			args.AssignUploader = &assigned
+		default:
+			return model.CreateInvitationArgs{}, INVALID_ROLE_DATA
+		}
+	}
+
+	return args, NO_ERROR
+}
+
+// isValidQQ reports whether qq looks like a QQ number.
+func isValidQQ(qq string) bool {
+	if len(qq) < 5 || len(qq) > 12 || qq[0] == '0' {
+		return false
+	}
+
+	for _, c := range qq {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// genInvCode returns a random invitation code.
+func genInvCode() (string, error) {
+	buf := make([]byte, invCodeLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invitation code: %w", err)
+	}
+
+	// 256 is a multiple of the alphabet size, so the modulo is unbiased.
+	for i, b := range buf {
+		buf[i] = invCodeAlphabet[int(b)%len(invCodeAlphabet)]
+	}
+
+	return string(buf), nil
+}
+
+// hashInvCode hashes an invitation code for storage.
+// Codes are case-insensitive and may be typed with separators.
+func hashInvCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
 }
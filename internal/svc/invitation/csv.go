@@ -0,0 +1,155 @@
+package invitation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// csvHeader is skipped on import if present.
+var csvHeader = []string{"qq", "roles", "note"}
+
+// codeSheetHeader is written at the top of code sheets.
+var codeSheetHeader = []string{"qq", "invitation_code", "expires_at", "roles", "note"}
+
+// ParsedInvitation represents a row parsed from a bulk invitation file.
+// The fields are not validated beyond trimming.
+type ParsedInvitation struct {
+	// 1-based line number of the row in the file.
+	Line      int
+	InviteeQQ string
+	Roles     []string
+	Note      *string
+}
+
+// IssuedInvitation is a row of a code sheet.
+type IssuedInvitation struct {
+	InviteeQQ string
+	InvCode   string
+	ExpiresAt time.Time
+	Roles     []string
+	Note      *string
+}
+
+// ParseCSV parses a CSV file with columns qq[,roles[,note]].
+// Roles are separated by semicolons, vertical bars, slashes or spaces,
+// so that they fit in one field without quoting.
+// A leading header row and blank rows are skipped.
+func ParseCSV(file io.Reader) ([]ParsedInvitation, error) {
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+
+	var invitations []ParsedInvitation
+
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := r.FieldPos(0)
+
+		if first {
+			// Excel likes to prepend a BOM.
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+
+			if isCSVHeader(record) {
+				continue
+			}
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		inv := ParsedInvitation{
+			Line:      line,
+			InviteeQQ: strings.TrimSpace(record[0]),
+			Roles:     []string{},
+		}
+
+		if len(record) > 1 {
+			inv.Roles = splitRoles(record[1])
+		}
+
+		if len(record) > 2 {
+			if note := strings.TrimSpace(record[2]); note != "" {
+				inv.Note = &note
+			}
+		}
+
+		invitations = append(invitations, inv)
+	}
+
+	return invitations, nil
+}
+
+// WriteCodeSheet writes issued invitations as CSV,
+// one row per invitee with the plain code to hand out.
+func WriteCodeSheet(w io.Writer, invitations []IssuedInvitation) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(codeSheetHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, inv := range invitations {
+		note := ""
+		if inv.Note != nil {
+			note = *inv.Note
+		}
+
+		if err := cw.Write([]string{
+			inv.InviteeQQ,
+			inv.InvCode,
+			inv.ExpiresAt.Format(time.RFC3339),
+			strings.Join(inv.Roles, ";"),
+			note,
+		}); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func splitRoles(field string) []string {
+	roles := strings.FieldsFunc(field, func(r rune) bool {
+		return r == ';' || r == '|' || r == '/' || unicode.IsSpace(r)
+	})
+
+	for i, role := range roles {
+		roles[i] = strings.ToLower(role)
+	}
+
+	return roles
+}
+
+func isCSVHeader(record []string) bool {
+	for i, field := range record {
+		if i >= len(csvHeader) || !strings.EqualFold(strings.TrimSpace(field), csvHeader[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,118 @@
+package invitation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		want []ParsedInvitation
+	}{
+		{
+			name: "header with BOM",
+			file: "\ufeffQQ, Roles ,note\n10001,translator,\n",
+			want: []ParsedInvitation{{Line: 2, InviteeQQ: "10001", Roles: []string{"translator"}}},
+		},
+		{
+			name: "no header",
+			file: "10001\n10002,Translator;PROOFREADER|typesetter/redrawer reviewer\n",
+			want: []ParsedInvitation{
+				{Line: 1, InviteeQQ: "10001", Roles: []string{}},
+				{Line: 2, InviteeQQ: "10002", Roles: []string{"translator", "proofreader", "typesetter", "redrawer", "reviewer"}},
+			},
+		},
+		{
+			name: "header only counts on the first line",
+			file: "10001,translator\nqq,roles,note\n",
+			want: []ParsedInvitation{
+				{Line: 1, InviteeQQ: "10001", Roles: []string{"translator"}},
+				{Line: 2, InviteeQQ: "qq", Roles: []string{"roles"}, Note: strPtr("note")},
+			},
+		},
+		{
+			name: "quoted fields",
+			file: "qq,roles,note\n\" 10001 \",\"translator proofreader\",\"from \"\"A\"\",\nmultiline\"\n10002,reviewer,\"  \"\n",
+			want: []ParsedInvitation{
+				{Line: 2, InviteeQQ: "10001", Roles: []string{"translator", "proofreader"}, Note: strPtr("from \"A\",\nmultiline")},
+				{Line: 4, InviteeQQ: "10002", Roles: []string{"reviewer"}},
+			},
+		},
+		{
+			name: "blank rows",
+			file: "qq,roles\n\n , \n10001,translator\n,,\n",
+			want: []ParsedInvitation{{Line: 4, InviteeQQ: "10001", Roles: []string{"translator"}}},
+		},
+		{
+			// Rejected by the service, not the parser.
+			name: "duplicate rows",
+			file: "10001,translator\n10001,translator\n",
+			want: []ParsedInvitation{
+				{Line: 1, InviteeQQ: "10001", Roles: []string{"translator"}},
+				{Line: 2, InviteeQQ: "10001", Roles: []string{"translator"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := ParseCSV(strings.NewReader(c.file))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !slices.EqualFunc(got, c.want, equalParsed) {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseCSVMalformed(t *testing.T) {
+	if _, err := ParseCSV(strings.NewReader("10001,\"translator\n")); err == nil {
+		t.Fatal("unterminated quote accepted")
+	}
+}
+
+func TestWriteCodeSheet(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 24, 8, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := WriteCodeSheet(&buf, []IssuedInvitation{
+		{InviteeQQ: "10001", InvCode: "ABCD", ExpiresAt: expiresAt, Roles: []string{"translator", "proofreader"}, Note: strPtr("a, b")},
+		{InviteeQQ: "10002", InvCode: "EFGH", ExpiresAt: expiresAt, Roles: []string{}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		codeSheetHeader,
+		{"10001", "ABCD", "2026-10-24T08:00:00Z", "translator;proofreader", "a, b"},
+		{"10002", "EFGH", "2026-10-24T08:00:00Z", "", ""},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Fatalf("got %q", records)
+	}
+}
+
+func equalParsed(a, b ParsedInvitation) bool {
+	if a.Note == nil || b.Note == nil {
+		if a.Note != b.Note {
+			return false
+		}
+	} else if *a.Note != *b.Note {
+		return false
+	}
+
+	return a.Line == b.Line && a.InviteeQQ == b.InviteeQQ && slices.Equal(a.Roles, b.Roles)
+}
+
+func strPtr(s string) *string { return &s }
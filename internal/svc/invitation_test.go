@@ -0,0 +1,33 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/repo"
+)
+
+func TestCreateInvitationChecks(t *testing.T) {
+	newSvc := func(az AuthzSvc) InvitationSvc {
+		return NewInvitationSvc(
+			struct{ repo.InvitationRepo }{},
+			struct{ repo.UserRepo }{},
+			struct{ repo.AuditLogRepo }{},
+			az,
+			3600,
+		)
+	}
+
+	valid := model.CreateInvitationArgs{InviteeQQ: "123456789"}
+	if _, svcErr := newSvc(fakeComicAccessAuthzSvc{}).CreateInvitation("u-1", model.ReqMeta{}, valid); svcErr != PERMISSION_DENIED {
+		t.Fatalf("non-admin: got %v", svcErr)
+	}
+
+	// Rejected before anything is looked up.
+	for _, qq := range []string{"", "1234", "0123456789", "1234567890123", "12345678a", " 123456789"} {
+		args := model.CreateInvitationArgs{InviteeQQ: qq}
+		if _, svcErr := newSvc(fakeAdminAuthzSvc{}).CreateInvitation("u-1", model.ReqMeta{}, args); svcErr != INVALID_QQ_DATA {
+			t.Errorf("QQ %q: got %v", qq, svcErr)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package svc
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Kinds of notifications.
+const (
+	// Assigned roles of a comic.
+	NOTIFICATION_ASSIGNED = "assigned"
+	// A comic one is assigned to changed stage.
+	NOTIFICATION_STAGE_CHANGE = "stage_change"
+	// A proofreader commented on a unit one translated.
+	NOTIFICATION_UNIT_COMMENT = "unit_comment"
+	// An invitation one sent was used to register.
+	NOTIFICATION_INVITATION_USED = "invitation_used"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+// NotificationSvc defines service operations for the inbox of the operator.
+// Notifications are sent by the services making the changes.
+type NotificationSvc interface {
+	RetrieveNotifications(opID string, opt model.RetrieveNotificationOpt) (SvcRslt[model.RetrieveNotificationReply], SvcErr)
+
+	MarkNotificationRead(opID string, notificationID string) SvcErr
+
+	MarkAllNotificationsRead(opID string) (SvcRslt[model.MarkAllNotificationsReadReply], SvcErr)
+}
+
+type notificationSvc struct {
+	repo repo.NotificationRepo
+}
+
+// NewNotificationSvc creates a new NotificationSvc. r must not be nil.
+func NewNotificationSvc(r repo.NotificationRepo) NotificationSvc {
+	if r == nil {
+		panic("NotificationRepo cannot be nil")
+	}
+
+	return &notificationSvc{repo: r}
+}
+
+// RetrieveNotifications lists the notifications of opID matching opt, newest first.
+func (ns *notificationSvc) RetrieveNotifications(
+	opID string,
+	opt model.RetrieveNotificationOpt,
+) (SvcRslt[model.RetrieveNotificationReply], SvcErr) {
+	if opt.Limit <= 0 {
+		opt.Limit = defaultNotificationLimit
+	}
+	opt.Limit = min(opt.Limit, maxNotificationLimit)
+
+	notifications, err := ns.repo.RetrieveNotifications(nil, opID, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve notifications", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[model.RetrieveNotificationReply]{}, DB_FAILURE
+	}
+
+	unread, err := ns.repo.CountUnreadByUserID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to count unread notifications", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[model.RetrieveNotificationReply]{}, DB_FAILURE
+	}
+
+	infos := make([]model.NotificationInfo, 0, len(notifications))
+	for _, n := range notifications {
+		infos = append(infos, model.NotificationInfo{
+			ID:            n.ID,
+			Kind:          n.Kind,
+			ComicID:       n.ComicID,
+			ComicTitle:    n.ComicTitle,
+			ActorID:       n.ActorID,
+			ActorNickname: n.ActorNickname,
+			Detail:        n.Detail,
+			ReadAt:        timePtrToInt64Ptr(n.ReadAt),
+			CreatedAt:     n.CreatedAt.Unix(),
+		})
+	}
+
+	return accept(200, model.RetrieveNotificationReply{
+		Notifications: infos,
+		UnreadCount:   unread,
+	}), NO_ERROR
+}
+
+// MarkNotificationRead marks a notification of opID as read.
+// Those of other users are reported as not found.
+func (ns *notificationSvc) MarkNotificationRead(opID string, notificationID string) SvcErr {
+	if err := ns.repo.MarkRead(nil, opID, notificationID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to mark notification read",
+			zap.String("userID", opID), zap.String("notificationID", notificationID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+func (ns *notificationSvc) MarkAllNotificationsRead(opID string) (SvcRslt[model.MarkAllNotificationsReadReply], SvcErr) {
+	count, err := ns.repo.MarkAllRead(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to mark all notifications read", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[model.MarkAllNotificationsReadReply]{}, DB_FAILURE
+	}
+
+	return accept(200, model.MarkAllNotificationsReadReply{Count: count}), NO_ERROR
+}
+
+// notifier sends notifications on behalf of the services making the changes.
+type notifier struct {
+	repo repo.NotificationRepo
+}
+
+func newNotifier(r repo.NotificationRepo) *notifier {
+	if r == nil {
+		panic("NotificationRepo cannot be nil")
+	}
+
+	return &notifier{repo: r}
+}
+
+// notification is one notification to be sent to its recipients.
+type notification struct {
+	Kind string
+
+	// Empty if the notification is not about a comic.
+	ComicID string
+	ActorID string
+
+	// Marshalled as JSON.
+	Detail any
+}
+
+// notify sends n to each of userIDs once, leaving out the actor, who knows already.
+// Pass the transaction of the change as ex, so that the change is rolled back if the notifications cannot be sent.
+func (nt *notifier) notify(ex repo.Exct, userIDs []string, n notification) error {
+	raw, err := json.Marshal(n.Detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification detail: %w", err)
+	}
+
+	var comicID, actorID *string
+	if n.ComicID != "" {
+		comicID = &n.ComicID
+	}
+	if n.ActorID != "" {
+		actorID = &n.ActorID
+	}
+
+	newNotifications := make([]po.NewNotification, 0, len(userIDs))
+	seen := make([]string, 0, len(userIDs))
+
+	for _, userID := range userIDs {
+		if userID == n.ActorID || slices.Contains(seen, userID) {
+			continue
+		}
+		seen = append(seen, userID)
+
+		id, err := genUUID()
+		if err != nil {
+			return fmt.Errorf("failed to generate notification ID: %w", err)
+		}
+
+		newNotifications = append(newNotifications, po.NewNotification{
+			ID:      id,
+			UserID:  userID,
+			Kind:    n.Kind,
+			ComicID: comicID,
+			ActorID: actorID,
+			Detail:  raw,
+		})
+	}
+
+	return nt.repo.CreateNotifications(ex, newNotifications)
+}
+
+// assignedNotification describes the roles assigned to a user.
+type assignedNotification struct {
+	AsgnID string   `json:"assignment_id"`
+	Roles  []string `json:"roles"`
+}
+
+// unitCommentNotification describes a proofreader comment on a unit.
+type unitCommentNotification struct {
+	UnitID  string `json:"unit_id"`
+	PageID  string `json:"page_id"`
+	Comment string `json:"comment"`
+}
+
+// invitationUsedNotification describes the registration through an invitation.
+type invitationUsedNotification struct {
+	InvitationID string `json:"invitation_id"`
+	InviteeID    string `json:"invitee_id"`
+	InviteeQQ    string `json:"invitee_qq"`
+}
@@ -0,0 +1,262 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/onebot"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Kinds of notifications pushed to QQ.
+var onebotPushedKinds = []string{
+	NOTIFICATION_ASSIGNED,
+	NOTIFICATION_STAGE_CHANGE,
+}
+
+const (
+	// Notifications pushed at once by one push.
+	onebotPushBatch = 20
+	// Notifications older than this when claimed are dropped rather than pushed,
+	// such as those sent while pushing was off.
+	onebotPushMaxAge = 24 * time.Hour
+)
+
+// Names of roles and stages in messages.
+var (
+	onebotRoleNames = map[string]string{
+		ROLE_TRANSLATOR:  "翻译",
+		ROLE_PROOFREADER: "校对",
+		ROLE_TYPESETTER:  "排版",
+		ROLE_REDRAWER:    "修图",
+		ROLE_REVIEWER:    "审核",
+		ROLE_UPLOADER:    "上传",
+	}
+
+	onebotStageNames = map[string]string{
+		model.COMIC_STAGE_PENDING:      "未开始",
+		model.COMIC_STAGE_TRANSLATING:  "翻译",
+		model.COMIC_STAGE_PROOFREADING: "校对",
+		model.COMIC_STAGE_TYPESETTING:  "排版",
+		model.COMIC_STAGE_REVIEWING:    "审核",
+		model.COMIC_STAGE_UPLOADING:    "上传",
+		model.COMIC_STAGE_COMPLETED:    "已完成",
+	}
+)
+
+// OneBotSvc defines service operations for the QQ bot.
+//
+// Members are told of their assignments and of the stage changes of their comics
+// in private messages, and commands in groups are answered on behalf of the sender,
+// both matched to users by QQ. Everything is off without a client.
+type OneBotSvc interface {
+	// PushNotifications pushes the notifications of the kinds pushed not pushed yet,
+	// returning how many were sent.
+	PushNotifications() int
+
+	// HandleEvent answers a command in a group message posted by the bot.
+	// The reply is empty if there is nothing to answer.
+	HandleEvent(meta model.ReqMeta, body []byte, signature string) (SvcRslt[onebot.QuickReply], SvcErr)
+}
+
+type oneBotSvc struct {
+	notificationRepo repo.NotificationRepo
+	userRepo         repo.UserRepo
+
+	comicSvc    ComicSvc
+	asgnSvc     ComicAsgnSvc
+	workflowSvc WorkflowSvc
+	taskSvc     ComicTaskSvc
+
+	client onebot.Client
+
+	// Groups whose commands are answered, all of them if empty.
+	groupIDs []int64
+}
+
+// NewOneBotSvc creates a new OneBotSvc. None of the repos nor services may be nil.
+// If client is nil, nothing is pushed and events are rejected as not found.
+func NewOneBotSvc(
+	nr repo.NotificationRepo,
+	ur repo.UserRepo,
+	comicSvc ComicSvc,
+	asgnSvc ComicAsgnSvc,
+	workflowSvc WorkflowSvc,
+	taskSvc ComicTaskSvc,
+	client onebot.Client,
+	groupIDs []int64,
+) OneBotSvc {
+	if nr == nil {
+		panic("NotificationRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+	if comicSvc == nil {
+		panic("ComicSvc cannot be nil")
+	}
+	if asgnSvc == nil {
+		panic("ComicAsgnSvc cannot be nil")
+	}
+	if workflowSvc == nil {
+		panic("WorkflowSvc cannot be nil")
+	}
+	if taskSvc == nil {
+		panic("ComicTaskSvc cannot be nil")
+	}
+
+	return &oneBotSvc{
+		notificationRepo: nr,
+		userRepo:         ur,
+		comicSvc:         comicSvc,
+		asgnSvc:          asgnSvc,
+		workflowSvc:      workflowSvc,
+		taskSvc:          taskSvc,
+		client:           client,
+		groupIDs:         groupIDs,
+	}
+}
+
+// PushNotifications claims the notifications to push in batches and sends each to its recipient.
+// Each is claimed once, so failures are logged and not retried.
+func (obs *oneBotSvc) PushNotifications() int {
+	if obs.client == nil {
+		return 0
+	}
+
+	sent := 0
+
+	for {
+		notifications, err := obs.notificationRepo.ClaimUnpushed(nil, onebotPushedKinds, onebotPushBatch)
+		if err != nil {
+			zap.L().Error("Failed to claim notifications to push", zap.Error(err))
+			return sent
+		}
+
+		for i := range notifications {
+			if obs.push(&notifications[i]) {
+				sent++
+			}
+		}
+
+		if len(notifications) < onebotPushBatch {
+			return sent
+		}
+	}
+}
+
+// push sends n to the QQ of its recipient, reporting whether it was sent.
+func (obs *oneBotSvc) push(n *po.PushNotification) bool {
+	if time.Since(n.CreatedAt) > onebotPushMaxAge {
+		return false
+	}
+
+	qq, err := strconv.ParseInt(n.UserQQ, 10, 64)
+	if err != nil {
+		zap.L().Warn("Cannot push notification to malformed QQ", zap.String("userID", n.UserID), zap.String("qq", n.UserQQ))
+		return false
+	}
+
+	text := onebotNotificationText(n)
+	if text == "" {
+		return false
+	}
+
+	if err := obs.client.SendPrivateMsg(context.Background(), qq, text); err != nil {
+		zap.L().Warn("Failed to push notification to QQ",
+			zap.String("notificationID", n.ID), zap.String("qq", n.UserQQ), zap.Error(err))
+		return false
+	}
+
+	return true
+}
+
+// onebotNotificationText renders n as a message, empty if it cannot be.
+func onebotNotificationText(n *po.PushNotification) string {
+	comic := "一部漫画"
+	if n.ComicTitle != nil {
+		comic = "《" + *n.ComicTitle + "》"
+	}
+
+	switch n.Kind {
+	case NOTIFICATION_ASSIGNED:
+		var detail assignedNotification
+		if err := json.Unmarshal(n.Detail, &detail); err != nil {
+			return ""
+		}
+
+		text := fmt.Sprintf("你被分配为%s的%s。", comic, onebotRoleText(detail.Roles))
+		if n.ActorNickname != nil {
+			text = fmt.Sprintf("%s 将你分配为%s的%s。", *n.ActorNickname, comic, onebotRoleText(detail.Roles))
+		}
+		return text
+
+	case NOTIFICATION_STAGE_CHANGE:
+		var detail stageEventDetail
+		if err := json.Unmarshal(n.Detail, &detail); err != nil {
+			return ""
+		}
+
+		text := fmt.Sprintf("%s已由「%s」阶段进入「%s」阶段。",
+			comic, onebotStageNames[detail.From], onebotStageNames[detail.To])
+		if detail.Reason != "" {
+			text += "原因：" + detail.Reason
+		}
+		return text
+
+	default:
+		return ""
+	}
+}
+
+// onebotRoleText joins the names of roles.
+func onebotRoleText(roles []string) string {
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		if name, ok := onebotRoleNames[r]; ok {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, "、")
+}
+
+func (obs *oneBotSvc) HandleEvent(meta model.ReqMeta, body []byte, signature string) (SvcRslt[onebot.QuickReply], SvcErr) {
+	if obs.client == nil {
+		return SvcRslt[onebot.QuickReply]{}, NOT_FOUND
+	}
+
+	if !obs.client.VerifyEvent(body, signature) {
+		zap.L().Warn("OneBot event with invalid signature", zap.String("ip", meta.IP))
+		return SvcRslt[onebot.QuickReply]{}, INVALID_ONEBOT_SIGNATURE
+	}
+
+	var event onebot.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return SvcRslt[onebot.QuickReply]{}, INVALID_ONEBOT_EVENT
+	}
+
+	// Only group messages of others are answered.
+	if event.PostType != onebot.POST_TYPE_MESSAGE ||
+		event.MessageType != onebot.MESSAGE_TYPE_GROUP ||
+		event.UserID == event.SelfID ||
+		(len(obs.groupIDs) > 0 && !slices.Contains(obs.groupIDs, event.GroupID)) {
+		return accept(200, onebot.QuickReply{}), NO_ERROR
+	}
+
+	reply := obs.runCommand(meta, event.UserID, event.RawMessage)
+	if reply == "" {
+		return accept(200, onebot.QuickReply{}), NO_ERROR
+	}
+
+	return accept(200, onebot.QuickReply{Reply: reply, AtSender: true}), NO_ERROR
+}
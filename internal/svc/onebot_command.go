@@ -0,0 +1,305 @@
+package svc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// Comics listed at most in a reply.
+	onebotListLimit = 10
+	// Comics matching a title listed at most when asking for an ID.
+	onebotMatchLimit = 5
+)
+
+// Commands start with a slash, either half or full width.
+var onebotCommandPrefixes = []string{"/", "／"}
+
+// CQ codes, such as those of mentions, are not part of commands.
+var cqCodePattern = regexp.MustCompile(`\[CQ:[^\]]*\]`)
+
+// onebotCommand is a command answered in groups.
+type onebotCommand struct {
+	names []string
+	usage string
+	desc  string
+
+	// Returns the reply to user, who sent arg after the name of the command.
+	run func(obs *oneBotSvc, meta model.ReqMeta, user *po.BasicUser, arg string) string
+}
+
+var onebotCommands = []onebotCommand{
+	{
+		names: []string{"我的任务", "tasks"},
+		usage: "/我的任务",
+		desc:  "列出你参与的未完成的漫画",
+		run:   (*oneBotSvc).myTasks,
+	},
+	{
+		names: []string{"进度", "progress"},
+		usage: "/进度 <漫画ID或标题>",
+		desc:  "查看漫画的进度",
+		run:   (*oneBotSvc).comicProgress,
+	},
+	{
+		names: []string{"领取", "claim"},
+		usage: "/领取 <任务ID>",
+		desc:  "领取任务板上的任务",
+		run:   (*oneBotSvc).claimTask,
+	},
+}
+
+// onebotHelpNames are the names of the command listing the others.
+var onebotHelpNames = []string{"帮助", "help"}
+
+// runCommand answers the command in message from qq, returning an empty reply
+// if message is no command of ours, as other bots may share the group.
+func (obs *oneBotSvc) runCommand(meta model.ReqMeta, qq int64, message string) string {
+	text := strings.TrimSpace(cqCodePattern.ReplaceAllString(message, ""))
+	text = strings.ReplaceAll(text, "　", " ")
+
+	trimmed := false
+	for _, prefix := range onebotCommandPrefixes {
+		if rest, ok := strings.CutPrefix(text, prefix); ok {
+			text, trimmed = rest, true
+			break
+		}
+	}
+	if !trimmed {
+		return ""
+	}
+
+	name, arg, _ := strings.Cut(text, " ")
+	arg = strings.TrimSpace(arg)
+
+	if containsFold(onebotHelpNames, name) {
+		return onebotHelpText()
+	}
+
+	var cmd *onebotCommand
+	for i := range onebotCommands {
+		if containsFold(onebotCommands[i].names, name) {
+			cmd = &onebotCommands[i]
+			break
+		}
+	}
+	if cmd == nil {
+		return ""
+	}
+
+	user, err := obs.userRepo.GetUserByQQ(nil, strconv.FormatInt(qq, 10))
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return "你的 QQ 尚未注册，请先使用邀请码登录。"
+		}
+		zap.L().Error("Failed to get user by QQ for OneBot command", zap.Int64("qq", qq), zap.Error(err))
+		return "服务器内部错误，请稍后再试。"
+	}
+
+	return cmd.run(obs, meta, user, arg)
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func onebotHelpText() string {
+	lines := []string{"可用的命令："}
+	for _, cmd := range onebotCommands {
+		lines = append(lines, fmt.Sprintf("%s：%s", cmd.usage, cmd.desc))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// myTasks lists the comics user is assigned to that are not completed,
+// with the roles held and the nearest due date.
+func (obs *oneBotSvc) myTasks(_ model.ReqMeta, user *po.BasicUser, _ string) string {
+	openStages := make([]string, 0, len(workflowStages))
+	for _, s := range workflowStages {
+		if s != model.COMIC_STAGE_COMPLETED {
+			openStages = append(openStages, s)
+		}
+	}
+
+	comics, svcErr := obs.comicSvc.RetrieveComics(user.ID, model.RetrieveComicOpt{
+		AssignedUserID: &user.ID,
+		Stages:         openStages,
+		Limit:          onebotListLimit,
+	})
+	if svcErr != NO_ERROR {
+		return svcErr.Msg()
+	}
+
+	if len(*comics.Data) == 0 {
+		return "你目前没有未完成的漫画。"
+	}
+
+	asgns, svcErr := obs.asgnSvc.GetAsgnsByUserID(user.ID, 0, 0)
+	if svcErr != NO_ERROR {
+		return svcErr.Msg()
+	}
+
+	asgnByComic := make(map[string]*model.ComicAsgnInfo, len(*asgns.Data))
+	for i := range *asgns.Data {
+		a := &(*asgns.Data)[i]
+		asgnByComic[a.ComicID] = a
+	}
+
+	lines := []string{"你参与的未完成的漫画："}
+	for i, c := range *comics.Data {
+		line := fmt.Sprintf("%d. 《%s》 阶段：%s", i+1, c.Title, onebotStageNames[c.Stage])
+
+		if a, ok := asgnByComic[c.ID]; ok {
+			if roles := onebotRoleText(asgnInfoRoleNames(a)); roles != "" {
+				line += " 担任：" + roles
+			}
+			if due := nearestDue(a); due != nil {
+				line += " 截止：" + time.Unix(*due, 0).Format("01-02 15:04")
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// asgnInfoRoleNames lists the roles held in a.
+func asgnInfoRoleNames(a *model.ComicAsgnInfo) []string {
+	var roles []string
+
+	for _, r := range []struct {
+		name string
+		at   *int64
+	}{
+		{ROLE_TRANSLATOR, a.AssignedTranslatorAt},
+		{ROLE_PROOFREADER, a.AssignedProofreaderAt},
+		{ROLE_TYPESETTER, a.AssignedTypesetterAt},
+		{ROLE_REDRAWER, a.AssignedRedrawerAt},
+		{ROLE_REVIEWER, a.AssignedReviewerAt},
+	} {
+		if r.at != nil {
+			roles = append(roles, r.name)
+		}
+	}
+
+	return roles
+}
+
+// nearestDue returns the earliest due date in a, if any.
+func nearestDue(a *model.ComicAsgnInfo) *int64 {
+	var nearest *int64
+
+	for _, due := range []*int64{a.TranslatorDueAt, a.ProofreaderDueAt, a.TypesetterDueAt, a.RedrawerDueAt, a.ReviewerDueAt} {
+		if due != nil && (nearest == nil || *due < *nearest) {
+			nearest = due
+		}
+	}
+
+	return nearest
+}
+
+// comicProgress shows the stage of the comic whose ID is arg, or whose title contains arg,
+// and what keeps it from completing the stage.
+func (obs *oneBotSvc) comicProgress(_ model.ReqMeta, user *po.BasicUser, arg string) string {
+	if arg == "" {
+		return "用法：/进度 <漫画ID或标题>"
+	}
+
+	comicID := arg
+	if _, err := uuid.Parse(arg); err != nil {
+		matches, svcErr := obs.comicSvc.RetrieveComics(user.ID, model.RetrieveComicOpt{
+			Title: &arg,
+			Limit: onebotMatchLimit,
+		})
+		if svcErr != NO_ERROR {
+			return svcErr.Msg()
+		}
+
+		comicID = ""
+		for _, c := range *matches.Data {
+			if c.Title == arg {
+				comicID = c.ID
+				break
+			}
+		}
+
+		switch {
+		case comicID != "":
+		case len(*matches.Data) == 0:
+			return fmt.Sprintf("未找到标题包含「%s」的漫画。", arg)
+		case len(*matches.Data) == 1:
+			comicID = (*matches.Data)[0].ID
+		default:
+			lines := []string{"找到多部漫画，请使用 ID 查询："}
+			for _, c := range *matches.Data {
+				lines = append(lines, fmt.Sprintf("《%s》 %s", c.Title, c.ID))
+			}
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	workflow, svcErr := obs.workflowSvc.GetComicWorkflow(user.ID, comicID)
+	if svcErr != NO_ERROR {
+		if svcErr == NOT_FOUND {
+			return "未找到该漫画。"
+		}
+		return svcErr.Msg()
+	}
+
+	comic, svcErr := obs.comicSvc.GetComicInfoByID(comicID)
+	if svcErr != NO_ERROR {
+		return svcErr.Msg()
+	}
+
+	lines := []string{
+		fmt.Sprintf("《%s》", comic.Data.Title),
+		fmt.Sprintf("阶段：%s", onebotStageNames[workflow.Data.Stage]),
+		fmt.Sprintf("页数：%d", comic.Data.PageCount),
+	}
+
+	if gate := workflow.Data.Gate; gate != nil {
+		units := 0
+		for _, p := range gate.Pages {
+			units += len(p.Units)
+		}
+
+		if units > 0 {
+			lines = append(lines, fmt.Sprintf("本阶段尚有 %d 个单元未完成，涉及 %d 页。", units, len(gate.Pages)))
+		} else {
+			lines = append(lines, "本阶段的单元均已完成。")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// claimTask claims the task whose ID is arg for user.
+func (obs *oneBotSvc) claimTask(meta model.ReqMeta, user *po.BasicUser, arg string) string {
+	if arg == "" {
+		return "用法：/领取 <任务ID>"
+	}
+
+	if _, svcErr := obs.taskSvc.ClaimTask(user.ID, meta, arg); svcErr != NO_ERROR {
+		return "领取失败：" + svcErr.Msg()
+	}
+
+	return "领取成功，已为你分配对应的角色。"
+}
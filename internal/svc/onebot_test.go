@@ -0,0 +1,286 @@
+package svc
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/onebot"
+	"poprako-main-server/internal/repo"
+)
+
+const (
+	tONEBOT_TOKEN  = "onebot-token"
+	tONEBOT_SECRET = "onebot-secret"
+)
+
+// fakeOneBot is a local OneBot endpoint recording the actions called.
+type fakeOneBot struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	calls []fakeOneBotCall
+}
+
+type fakeOneBotCall struct {
+	action string
+	auth   string
+	params map[string]any
+}
+
+func newFakeOneBot(t *testing.T) *fakeOneBot {
+	t.Helper()
+
+	fb := &fakeOneBot{}
+	fb.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var params map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			t.Errorf("params of %s: %v", req.URL.Path, err)
+		}
+
+		fb.mu.Lock()
+		fb.calls = append(fb.calls, fakeOneBotCall{
+			action: strings.TrimPrefix(req.URL.Path, "/"),
+			auth:   req.Header.Get("Authorization"),
+			params: params,
+		})
+		fb.mu.Unlock()
+
+		// The bot refuses to message strangers.
+		if uid, _ := params["user_id"].(float64); uid == 404 {
+			w.Write([]byte(`{"status":"failed","retcode":100,"wording":"not a friend"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok","retcode":0,"data":{"message_id":1}}`))
+	}))
+	t.Cleanup(fb.Close)
+
+	return fb
+}
+
+// fakePushNotificationRepo hands out its notifications once.
+type fakePushNotificationRepo struct {
+	repo.NotificationRepo
+
+	unpushed []po.PushNotification
+}
+
+func (r *fakePushNotificationRepo) ClaimUnpushed(_ repo.Exct, kinds []string, limit int) ([]po.PushNotification, error) {
+	n := min(limit, len(r.unpushed))
+	claimed := r.unpushed[:n]
+	r.unpushed = r.unpushed[n:]
+	return claimed, nil
+}
+
+type fakeQQUserRepo struct {
+	repo.UserRepo
+
+	users []po.BasicUser
+}
+
+func (r *fakeQQUserRepo) GetUserByQQ(_ repo.Exct, qq string) (*po.BasicUser, error) {
+	for i := range r.users {
+		if r.users[i].QQ == qq {
+			return &r.users[i], nil
+		}
+	}
+	return nil, repo.REC_NOT_FOUND
+}
+
+// fakeClaimTaskSvc claims only the task "task-1".
+type fakeClaimTaskSvc struct {
+	ComicTaskSvc
+
+	claimedBy string
+}
+
+func (s *fakeClaimTaskSvc) ClaimTask(opID string, _ model.ReqMeta, taskID string) (SvcRslt[string], SvcErr) {
+	if taskID != "task-1" {
+		return SvcRslt[string]{}, NOT_FOUND
+	}
+	s.claimedBy = opID
+	return accept(200, "asgn-1"), NO_ERROR
+}
+
+func newTestOneBotSvc(t *testing.T, nr repo.NotificationRepo, ur repo.UserRepo, taskSvc ComicTaskSvc, client onebot.Client, groupIDs []int64) *oneBotSvc {
+	t.Helper()
+
+	return NewOneBotSvc(
+		nr, ur,
+		struct{ ComicSvc }{}, struct{ ComicAsgnSvc }{}, struct{ WorkflowSvc }{},
+		taskSvc, client, groupIDs,
+	).(*oneBotSvc)
+}
+
+func TestOneBotPushNotifications(t *testing.T) {
+	t.Setenv("ONEBOT_ACCESS_TOKEN", tONEBOT_TOKEN)
+	t.Setenv("ONEBOT_SECRET", tONEBOT_SECRET)
+
+	fb := newFakeOneBot(t)
+
+	title, actor := "T", "Alice"
+	nr := &fakePushNotificationRepo{unpushed: []po.PushNotification{
+		{
+			BasicNotification: po.BasicNotification{
+				ID: "n-1", Kind: NOTIFICATION_ASSIGNED, ComicTitle: &title, ActorNickname: &actor,
+				Detail:    json.RawMessage(`{"assignment_id":"a-1","roles":["translator","proofreader"]}`),
+				CreatedAt: time.Now(),
+			},
+			UserQQ: "10001",
+		},
+		{
+			BasicNotification: po.BasicNotification{
+				ID: "n-2", Kind: NOTIFICATION_STAGE_CHANGE, ComicTitle: &title,
+				Detail:    json.RawMessage(`{"from":"translating","to":"proofreading"}`),
+				CreatedAt: time.Now(),
+			},
+			UserQQ: "10002",
+		},
+		// Not a friend of the bot.
+		{
+			BasicNotification: po.BasicNotification{ID: "n-3", Kind: NOTIFICATION_STAGE_CHANGE, Detail: json.RawMessage(`{}`), CreatedAt: time.Now()},
+			UserQQ:            "404",
+		},
+		// Stale ones and malformed QQ numbers are not pushed.
+		{
+			BasicNotification: po.BasicNotification{ID: "n-4", Kind: NOTIFICATION_STAGE_CHANGE, Detail: json.RawMessage(`{}`), CreatedAt: time.Now().Add(-2 * onebotPushMaxAge)},
+			UserQQ:            "10003",
+		},
+		{
+			BasicNotification: po.BasicNotification{ID: "n-5", Kind: NOTIFICATION_STAGE_CHANGE, Detail: json.RawMessage(`{}`), CreatedAt: time.Now()},
+			UserQQ:            "qq",
+		},
+	}}
+
+	obs := newTestOneBotSvc(t, nr, &fakeQQUserRepo{}, struct{ ComicTaskSvc }{}, onebot.NewHTTPClient(fb.URL, 5*time.Second), nil)
+
+	if n := obs.PushNotifications(); n != 2 {
+		t.Fatalf("pushed %d, want 2", n)
+	}
+
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	if len(fb.calls) != 3 {
+		t.Fatalf("bot called %d times, want 3", len(fb.calls))
+	}
+
+	want := []struct {
+		qq   float64
+		text string
+	}{
+		{10001, "Alice 将你分配为《T》的翻译、校对。"},
+		{10002, "《T》已由「翻译」阶段进入「校对」阶段。"},
+	}
+	for i, w := range want {
+		c := fb.calls[i]
+		if c.action != "send_private_msg" || c.auth != "Bearer "+tONEBOT_TOKEN {
+			t.Errorf("call %d: action %q, auth %q", i, c.action, c.auth)
+		}
+		if c.params["user_id"] != w.qq || c.params["message"] != w.text || c.params["auto_escape"] != true {
+			t.Errorf("call %d: unexpected params %v", i, c.params)
+		}
+	}
+}
+
+// postEvent posts a group message from qq to obs as the bot would, signed with secret.
+func postEvent(t *testing.T, obs *oneBotSvc, groupID int64, qq int64, message string, secret string) (*onebot.QuickReply, SvcErr) {
+	t.Helper()
+
+	body, _ := json.Marshal(onebot.Event{
+		Time: time.Now().Unix(), SelfID: 1, PostType: onebot.POST_TYPE_MESSAGE,
+		MessageType: onebot.MESSAGE_TYPE_GROUP, SubType: "normal",
+		GroupID: groupID, UserID: qq, RawMessage: message,
+	})
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+
+	res, svcErr := obs.HandleEvent(model.ReqMeta{}, body, "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	return res.Data, svcErr
+}
+
+func TestOneBotHandleEvent(t *testing.T) {
+	t.Setenv("ONEBOT_SECRET", tONEBOT_SECRET)
+
+	fb := newFakeOneBot(t)
+	ur := &fakeQQUserRepo{users: []po.BasicUser{{ID: "u-1", QQ: "10001"}}}
+	taskSvc := &fakeClaimTaskSvc{}
+
+	obs := newTestOneBotSvc(t, &fakePushNotificationRepo{}, ur, taskSvc, onebot.NewHTTPClient(fb.URL, 5*time.Second), []int64{100})
+
+	notFound := NOT_FOUND
+	notFoundMsg := notFound.Msg()
+
+	if _, svcErr := postEvent(t, obs, 100, 10001, "/领取 task-1", "forged"); svcErr != INVALID_ONEBOT_SIGNATURE {
+		t.Fatalf("forged event: got %v", svcErr)
+	}
+
+	unsigned, _ := json.Marshal(onebot.Event{
+		PostType: onebot.POST_TYPE_MESSAGE, MessageType: onebot.MESSAGE_TYPE_GROUP,
+		GroupID: 100, UserID: 10001, RawMessage: "/领取 task-1",
+	})
+	if _, svcErr := obs.HandleEvent(model.ReqMeta{}, unsigned, ""); svcErr != INVALID_ONEBOT_SIGNATURE {
+		t.Fatalf("unsigned event: got %v", svcErr)
+	}
+
+	cases := []struct {
+		name    string
+		groupID int64
+		qq      int64
+		message string
+		reply   string
+	}{
+		{"claim", 100, 10001, "[CQ:at,qq=1] ／领取　task-1", "领取成功，已为你分配对应的角色。"},
+		{"claim failure", 100, 10001, "/claim task-2", "领取失败：" + notFoundMsg},
+		{"usage", 100, 10001, "/领取", "用法：/领取 <任务ID>"},
+		{"unregistered", 100, 10002, "/领取 task-1", "你的 QQ 尚未注册，请先使用邀请码登录。"},
+		{"help", 100, 10002, "/HELP", onebotHelpText()},
+		{"chat", 100, 10001, "领取 task-1", ""},
+		{"unknown command", 100, 10001, "/roll", ""},
+		{"other group", 200, 10001, "/领取 task-1", ""},
+		{"bot itself", 100, 1, "/领取 task-1", ""},
+	}
+
+	for _, c := range cases {
+		reply, svcErr := postEvent(t, obs, c.groupID, c.qq, c.message, tONEBOT_SECRET)
+		if svcErr != NO_ERROR {
+			t.Errorf("%s: got %v", c.name, svcErr)
+			continue
+		}
+		if reply.Reply != c.reply || reply.AtSender != (c.reply != "") {
+			t.Errorf("%s: got reply %+v, want %q", c.name, reply, c.reply)
+		}
+	}
+
+	if taskSvc.claimedBy != "u-1" {
+		t.Fatalf("task claimed by %q, want u-1", taskSvc.claimedBy)
+	}
+
+	// Replies go back in the response, so the bot is never called.
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	if len(fb.calls) != 0 {
+		t.Fatalf("bot called %d times", len(fb.calls))
+	}
+}
+
+func TestOneBotDisabled(t *testing.T) {
+	obs := newTestOneBotSvc(t, &fakePushNotificationRepo{}, &fakeQQUserRepo{}, struct{ ComicTaskSvc }{}, nil, nil)
+
+	if n := obs.PushNotifications(); n != 0 {
+		t.Fatalf("pushed %d while disabled", n)
+	}
+	if _, svcErr := obs.HandleEvent(model.ReqMeta{}, []byte(`{}`), ""); svcErr != NOT_FOUND {
+		t.Fatalf("event handled while disabled: %v", svcErr)
+	}
+}
@@ -70,6 +70,78 @@ const (
 	INVALID_PROJ_DATA SvcErr = "Invalid project data"
 	// Invalid export format.
 	INVALID_EXPORT_FORMAT SvcErr = "Invalid export format"
+	// Invalid termbase data.
+	INVALID_TERMBASE_DATA SvcErr = "Invalid termbase data"
+	// A termbase of the same name already exists.
+	TERMBASE_EXISTS SvcErr = "Termbase already exists"
+	// Invalid term data.
+	INVALID_TERM_DATA SvcErr = "Invalid term data"
+	// Invalid term file extension.
+	INVALID_TERM_FILE_EXT SvcErr = "Invalid term file extension"
+	// Invalid term file data.
+	INVALID_TERM_FILE_DATA SvcErr = "Invalid term file data"
+	// Invalid tag data.
+	INVALID_TAG_DATA SvcErr = "Invalid tag data"
+	// A tag of the same name already exists.
+	TAG_EXISTS SvcErr = "Tag already exists"
+	// Invalid gallery metadata.
+	INVALID_GALLERY_META SvcErr = "Invalid gallery metadata"
+	// Session revoked, expired or unknown.
+	SESSION_INVALID SvcErr = "Invalid session"
+
+	INV_PENDING_EXISTING SvcErr = "Pending invitation already exists"
+
+	INV_NOT_PENDING SvcErr = "Invitation already consumed or revoked"
+	// Malformed QQ number.
+	INVALID_QQ_DATA SvcErr = "Invalid QQ data"
+	// Invalid bulk invitation file data.
+	INVALID_INV_FILE_DATA SvcErr = "Invalid invitation file data"
+	// Too many rows in a bulk invitation file.
+	INV_BATCH_TOO_LARGE SvcErr = "Invitation batch too large"
+	// A QQ repeated in a bulk invitation file.
+	INV_DUPLICATE_QQ SvcErr = "Duplicate QQ in invitation batch"
+	// Unknown workflow stage.
+	INVALID_STAGE SvcErr = "Invalid stage"
+	// A stage skipped or not changed.
+	INVALID_STAGE_TRANSITION SvcErr = "Invalid stage transition"
+	// Reopening an earlier stage without a reason.
+	REOPEN_REASON_REQUIRED SvcErr = "Reopen reason required"
+	// The stage was changed concurrently.
+	STAGE_CONFLICT SvcErr = "Stage changed concurrently"
+	// Units not done for the stage to be completed.
+	STAGE_GATE_BLOCKED SvcErr = "Stage completion blocked by units"
+	// A negative due date, or one for a role not assigned.
+	INVALID_DUE_DATE SvcErr = "Invalid due date"
+	// Unknown role, bad due date, or a role whose work is done.
+	INVALID_TASK_DATA SvcErr = "Invalid task data"
+	// An open task for the role of the comic already exists.
+	TASK_EXISTS SvcErr = "Open task already exists"
+	// Task claimed already, or its work done.
+	TASK_UNAVAILABLE SvcErr = "Task unavailable"
+	// The claimer holds the role of the task already.
+	TASK_ROLE_HELD SvcErr = "Task role already held"
+	// Too many open tasks claimed.
+	TASK_CLAIM_LIMIT SvcErr = "Task claim limit reached"
+	// Releasing a task not claimed.
+	TASK_NOT_CLAIMED SvcErr = "Task not claimed"
+	// A malformed page range, or page ranges for an assignment without the translator role.
+	INVALID_PAGE_RANGE SvcErr = "Invalid page range"
+	// Editing a page outside the page ranges of the translator.
+	PAGE_NOT_ASSIGNED SvcErr = "Page not assigned"
+	// A blank team name, or members missing, repeated or without roles.
+	INVALID_TEAM_DATA SvcErr = "Invalid team data"
+	// A team of the same name already exists.
+	TEAM_EXISTS SvcErr = "Team already exists"
+	// A webhook URL not http(s), an unknown event, or a secret too short.
+	INVALID_WEBHOOK_DATA SvcErr = "Invalid webhook data"
+	// A OneBot event not signed with the secret.
+	INVALID_ONEBOT_SIGNATURE SvcErr = "Invalid OneBot signature"
+	// A OneBot event not in JSON.
+	INVALID_ONEBOT_EVENT SvcErr = "Invalid OneBot event"
+	// Units changed by others since the versions patched.
+	UNIT_CONFLICT SvcErr = "Units changed concurrently"
+	// Comic updates carrying the stage toggles of the old API.
+	STAGE_TOGGLES_REMOVED SvcErr = "Stage toggles removed"
 )
 
 // Get a API error code for the ServError.
@@ -115,6 +187,78 @@ func (e *SvcErr) Code() uint16 {
 		return 400
 		case INVALID_EXPORT_FORMAT:
 		return 400
+	case INVALID_TERMBASE_DATA:
+		return 400
+	case TERMBASE_EXISTS:
+		return 409
+	case INVALID_TERM_DATA:
+		return 400
+	case INVALID_TERM_FILE_EXT:
+		return 400
+	case INVALID_TERM_FILE_DATA:
+		return 400
+	case INVALID_TAG_DATA:
+		return 400
+	case TAG_EXISTS:
+		return 409
+	case INVALID_GALLERY_META:
+		return 400
+	case SESSION_INVALID:
+		return 401
+	case INV_PENDING_EXISTING:
+		return 409
+	case INV_NOT_PENDING:
+		return 409
+	case INVALID_QQ_DATA:
+		return 400
+	case INVALID_INV_FILE_DATA:
+		return 400
+	case INV_BATCH_TOO_LARGE:
+		return 400
+	case INV_DUPLICATE_QQ:
+		return 400
+	case INVALID_STAGE:
+		return 400
+	case INVALID_STAGE_TRANSITION:
+		return 409
+	case REOPEN_REASON_REQUIRED:
+		return 400
+	case STAGE_CONFLICT:
+		return 409
+	case STAGE_GATE_BLOCKED:
+		return 409
+	case INVALID_DUE_DATE:
+		return 400
+	case INVALID_TASK_DATA:
+		return 400
+	case TASK_EXISTS:
+		return 409
+	case TASK_UNAVAILABLE:
+		return 409
+	case TASK_ROLE_HELD:
+		return 409
+	case TASK_CLAIM_LIMIT:
+		return 409
+	case TASK_NOT_CLAIMED:
+		return 409
+	case INVALID_PAGE_RANGE:
+		return 400
+	case PAGE_NOT_ASSIGNED:
+		return 403
+	case INVALID_TEAM_DATA:
+		return 400
+	case TEAM_EXISTS:
+		return 409
+	case INVALID_WEBHOOK_DATA:
+		return 400
+	case INVALID_ONEBOT_SIGNATURE:
+		return 401
+	case INVALID_ONEBOT_EVENT:
+		return 400
+	case UNIT_CONFLICT:
+		return 409
+	case STAGE_TOGGLES_REMOVED:
+		return 400
 	default:
 		return 500
 	}
@@ -165,6 +309,78 @@ func (e *SvcErr) Msg() string {
 		return "项目数据格式错误"
 		case INVALID_EXPORT_FORMAT:
 		return "不支持的导出格式"
+	case INVALID_TERMBASE_DATA:
+		return "无效的术语库数据"
+	case TERMBASE_EXISTS:
+		return "同名术语库已存在"
+	case INVALID_TERM_DATA:
+		return "无效的术语数据"
+	case INVALID_TERM_FILE_EXT:
+		return "不支持的术语文件格式"
+	case INVALID_TERM_FILE_DATA:
+		return "无效的术语文件内容"
+	case INVALID_TAG_DATA:
+		return "无效的标签数据"
+	case TAG_EXISTS:
+		return "同名标签已存在"
+	case INVALID_GALLERY_META:
+		return "无效的图库元数据"
+	case SESSION_INVALID:
+		return "会话无效或已过期，请重新登录"
+	case INV_PENDING_EXISTING:
+		return "该 QQ 已有未使用的邀请"
+	case INV_NOT_PENDING:
+		return "邀请已被使用或已撤销"
+	case INVALID_QQ_DATA:
+		return "无效的 QQ 号"
+	case INVALID_INV_FILE_DATA:
+		return "无效的邀请文件内容"
+	case INV_BATCH_TOO_LARGE:
+		return "单次邀请数量超过上限"
+	case INV_DUPLICATE_QQ:
+		return "文件中该 QQ 重复出现"
+	case INVALID_STAGE:
+		return "无效的阶段"
+	case INVALID_STAGE_TRANSITION:
+		return "无法从当前阶段流转到该阶段"
+	case REOPEN_REASON_REQUIRED:
+		return "退回到之前的阶段需要填写原因"
+	case STAGE_CONFLICT:
+		return "漫画阶段已被他人修改，请刷新后重试"
+	case STAGE_GATE_BLOCKED:
+		return "尚有单元未完成，无法结束当前阶段"
+	case INVALID_DUE_DATE:
+		return "截止时间无效，或对应角色未分配"
+	case INVALID_TASK_DATA:
+		return "任务数据无效"
+	case TASK_EXISTS:
+		return "该漫画的此角色已有待领取的任务"
+	case TASK_UNAVAILABLE:
+		return "任务已被领取或已无需进行"
+	case TASK_ROLE_HELD:
+		return "你已担任该漫画的此角色"
+	case TASK_CLAIM_LIMIT:
+		return "领取的任务已达上限"
+	case TASK_NOT_CLAIMED:
+		return "任务未被领取"
+	case INVALID_PAGE_RANGE:
+		return "页面范围无效，或分配不含翻译角色"
+	case PAGE_NOT_ASSIGNED:
+		return "页面不在你负责的范围内"
+	case INVALID_TEAM_DATA:
+		return "团队数据无效"
+	case TEAM_EXISTS:
+		return "同名团队已存在"
+	case INVALID_WEBHOOK_DATA:
+		return "网络钩子数据无效"
+	case INVALID_ONEBOT_SIGNATURE:
+		return "OneBot 事件签名无效"
+	case INVALID_ONEBOT_EVENT:
+		return "OneBot 事件格式错误"
+	case UNIT_CONFLICT:
+		return "翻译单元已被他人修改，请合并后重试"
+	case STAGE_TOGGLES_REMOVED:
+		return "阶段已不能在此修改，请通过 PUT /comics/{comic_id}/stage 流转"
 	default:
 		return "服务器内部错误"
 	}
@@ -0,0 +1,328 @@
+package svc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"poprako-main-server/internal/jwtcodec"
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// SessionSvc defines service operations for login sessions.
+// A session pairs short-lived access tokens with a rotating refresh token.
+type SessionSvc interface {
+	RefreshSession(args model.RefreshSessionArgs) (SvcRslt[model.LoginReply], SvcErr)
+
+	CheckSession(userID string, sessionID string) SvcErr
+
+	GetSessionsByUserID(opID string, currSessionID string, userID string) (SvcRslt[[]model.SessionInfo], SvcErr)
+
+	RevokeSessionByID(opID string, sessionID string) SvcErr
+	RevokeSessionsByUserID(opID string, userID string) SvcErr
+}
+
+type sessionSvc struct {
+	repo     repo.SessionRepo
+	userRepo repo.UserRepo
+	issuer   *sessionIssuer
+}
+
+// NewSessionSvc creates a new SessionSvc. r, ur and jwt must not be nil.
+func NewSessionSvc(r repo.SessionRepo, ur repo.UserRepo, jwt *jwtcodec.Codec, refreshExpSecs int64) SessionSvc {
+	if r == nil {
+		panic("SessionRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+
+	return &sessionSvc{
+		repo:     r,
+		userRepo: ur,
+		issuer:   newSessionIssuer(r, jwt, refreshExpSecs),
+	}
+}
+
+// RefreshSession exchanges a refresh token for a new access token and refresh token.
+// Presenting an already rotated refresh token revokes the session,
+// as the token has then been used by two parties.
+func (ss *sessionSvc) RefreshSession(args model.RefreshSessionArgs) (SvcRslt[model.LoginReply], SvcErr) {
+	if args.RefreshToken == "" {
+		return SvcRslt[model.LoginReply]{}, SESSION_INVALID
+	}
+
+	hash := hashRefreshToken(args.RefreshToken)
+
+	session, err := ss.repo.GetSessionByRefreshHash(nil, hash)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.LoginReply]{}, SESSION_INVALID
+		}
+		zap.L().Error("Failed to get session by refresh token", zap.Error(err))
+		return SvcRslt[model.LoginReply]{}, DB_FAILURE
+	}
+
+	if session.RefreshTokenHash != hash {
+		zap.L().Warn("Rotated refresh token reused, revoking session",
+			zap.String("sessionID", session.ID), zap.String("userID", session.UserID), zap.String("ip", args.IP))
+
+		if err := ss.repo.RevokeSessionByID(nil, session.ID); err != nil && err != repo.REC_NOT_FOUND {
+			zap.L().Error("Failed to revoke session on refresh token reuse", zap.String("sessionID", session.ID), zap.Error(err))
+		}
+
+		return SvcRslt[model.LoginReply]{}, SESSION_INVALID
+	}
+
+	if !session.Active(time.Now()) {
+		return SvcRslt[model.LoginReply]{}, SESSION_INVALID
+	}
+
+	reply, err := ss.issuer.rotate(session, hash, args.IP)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			// Lost a race against another refresh or a revocation.
+			return SvcRslt[model.LoginReply]{}, SESSION_INVALID
+		}
+		zap.L().Error("Failed to rotate session", zap.String("sessionID", session.ID), zap.Error(err))
+		return SvcRslt[model.LoginReply]{}, DB_FAILURE
+	}
+
+	return accept(200, reply), NO_ERROR
+}
+
+// CheckSession checks that the session of an access token is still active.
+func (ss *sessionSvc) CheckSession(userID string, sessionID string) SvcErr {
+	if sessionID == "" {
+		return SESSION_INVALID
+	}
+
+	session, err := ss.repo.GetSessionByID(nil, sessionID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SESSION_INVALID
+		}
+		zap.L().Error("Failed to get session for check", zap.String("sessionID", sessionID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if session.UserID != userID || !session.Active(time.Now()) {
+		return SESSION_INVALID
+	}
+
+	return NO_ERROR
+}
+
+// GetSessionsByUserID lists the active sessions of a user.
+// Only admins and the user themself are allowed.
+func (ss *sessionSvc) GetSessionsByUserID(
+	opID string,
+	currSessionID string,
+	userID string,
+) (SvcRslt[[]model.SessionInfo], SvcErr) {
+	if opID != userID {
+		if svcErr := ss.checkAdmin(opID); svcErr != NO_ERROR {
+			return SvcRslt[[]model.SessionInfo]{}, svcErr
+		}
+	}
+
+	sessions, err := ss.repo.GetActiveSessionsByUserID(nil, userID)
+	if err != nil {
+		zap.L().Error("Failed to get sessions by user ID", zap.String("userID", userID), zap.Error(err))
+		return SvcRslt[[]model.SessionInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		infos = append(infos, model.SessionInfo{
+			ID:         s.ID,
+			Device:     s.Device,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt.Unix(),
+			LastUsedAt: s.LastUsedAt.Unix(),
+			ExpiresAt:  s.ExpiresAt.Unix(),
+			Current:    s.ID == currSessionID,
+		})
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// RevokeSessionByID revokes a session, logging its device out.
+// Only admins and the owner of the session are allowed.
+func (ss *sessionSvc) RevokeSessionByID(opID string, sessionID string) SvcErr {
+	session, err := ss.repo.GetSessionByID(nil, sessionID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get session for revocation", zap.String("sessionID", sessionID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if session.UserID != opID {
+		if svcErr := ss.checkAdmin(opID); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	if err := ss.repo.RevokeSessionByID(nil, sessionID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to revoke session", zap.String("sessionID", sessionID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	zap.L().Info("Session revoked", zap.String("sessionID", sessionID), zap.String("operatorID", opID))
+
+	return NO_ERROR
+}
+
+// RevokeSessionsByUserID revokes all sessions of a user, logging every device out.
+// Only admins and the user themself are allowed.
+func (ss *sessionSvc) RevokeSessionsByUserID(opID string, userID string) SvcErr {
+	if opID != userID {
+		if svcErr := ss.checkAdmin(opID); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	n, err := ss.repo.RevokeSessionsByUserID(nil, userID)
+	if err != nil {
+		zap.L().Error("Failed to revoke sessions by user ID", zap.String("userID", userID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	zap.L().Info("Sessions revoked", zap.String("userID", userID), zap.String("operatorID", opID), zap.Int64("count", n))
+
+	return NO_ERROR
+}
+
+// checkAdmin checks whether opID is an admin.
+func (ss *sessionSvc) checkAdmin(opID string) SvcErr {
+	op, err := ss.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for sessions", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to access sessions of others", zap.String("userID", opID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// sessionIssuer creates and rotates sessions.
+// It is shared by UserSvc for logins and SessionSvc for refreshes.
+type sessionIssuer struct {
+	repo       repo.SessionRepo
+	jwt        *jwtcodec.Codec
+	refreshExp time.Duration
+}
+
+func newSessionIssuer(r repo.SessionRepo, jwt *jwtcodec.Codec, refreshExpSecs int64) *sessionIssuer {
+	if jwt == nil {
+		panic("JWT Codec cannot be nil")
+	}
+	if refreshExpSecs <= 0 {
+		panic("refreshExpSecs must be positive")
+	}
+
+	return &sessionIssuer{
+		repo:       r,
+		jwt:        jwt,
+		refreshExp: time.Duration(refreshExpSecs) * time.Second,
+	}
+}
+
+// create starts a new session for userID and issues its first token pair.
+func (si *sessionIssuer) create(userID string, device string, ip string) (model.LoginReply, error) {
+	sessionID, err := genUUID()
+	if err != nil {
+		return model.LoginReply{}, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	refreshToken, hash, err := genRefreshToken()
+	if err != nil {
+		return model.LoginReply{}, err
+	}
+
+	refreshExpiresAt := time.Now().Add(si.refreshExp)
+
+	if err := si.repo.CreateSession(nil, &po.NewSession{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: hash,
+		Device:           device,
+		IP:               ip,
+		ExpiresAt:        refreshExpiresAt,
+	}); err != nil {
+		return model.LoginReply{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return si.reply(userID, sessionID, refreshToken, refreshExpiresAt)
+}
+
+// rotate replaces the refresh token of a session and issues a new token pair.
+// repo.REC_NOT_FOUND is returned if oldHash is no longer current.
+func (si *sessionIssuer) rotate(session *po.BasicSession, oldHash string, ip string) (model.LoginReply, error) {
+	refreshToken, hash, err := genRefreshToken()
+	if err != nil {
+		return model.LoginReply{}, err
+	}
+
+	refreshExpiresAt := time.Now().Add(si.refreshExp)
+
+	if err := si.repo.RotateRefreshToken(nil, session.ID, oldHash, hash, ip, refreshExpiresAt); err != nil {
+		return model.LoginReply{}, err
+	}
+
+	return si.reply(session.UserID, session.ID, refreshToken, refreshExpiresAt)
+}
+
+func (si *sessionIssuer) reply(
+	userID, sessionID, refreshToken string,
+	refreshExpiresAt time.Time,
+) (model.LoginReply, error) {
+	token, err := si.jwt.Encode(userID, sessionID)
+	if err != nil {
+		return model.LoginReply{}, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+
+	return model.LoginReply{
+		Token:            token,
+		ExpiresAt:        time.Now().Unix() + si.jwt.ExpSecs(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+		SessionID:        sessionID,
+	}, nil
+}
+
+// genRefreshToken returns a random refresh token and its hash.
+func genRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, hashRefreshToken(token), nil
+}
+
+// hashRefreshToken hashes a refresh token for storage.
+// The token has 256 bits of entropy, so an unsalted fast hash suffices.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,354 @@
+package svc
+
+import (
+	"strings"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// TagSvc defines service operations for tags.
+type TagSvc interface {
+	GetTagByID(tagID string) (SvcRslt[model.TagInfo], SvcErr)
+	RetrieveTags(opt model.RetrieveTagOpt) (SvcRslt[[]model.TagInfo], SvcErr)
+
+	CreateTag(opID string, args model.CreateTagArgs) (SvcRslt[model.CreateTagReply], SvcErr)
+
+	UpdateTagByID(opID string, args model.UpdateTagArgs) SvcErr
+
+	DeleteTagByID(opID string, tagID string) SvcErr
+
+	AttachTagsToComic(opID string, comicID string, tagIDs []string) SvcErr
+	DetachTagFromComic(opID string, comicID, tagID string) SvcErr
+
+	AttachTagsToUser(opID string, userID string, tagIDs []string) SvcErr
+	DetachTagFromUser(opID string, userID, tagID string) SvcErr
+}
+
+type tagSvc struct {
+	repo      repo.TagRepo
+	userRepo  repo.UserRepo
+	comicRepo repo.ComicRepo
+}
+
+// NewTagSvc creates a new TagSvc. r, ur and cr must not be nil.
+func NewTagSvc(r repo.TagRepo, ur repo.UserRepo, cr repo.ComicRepo) TagSvc {
+	if r == nil {
+		panic("TagRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+
+	return &tagSvc{repo: r, userRepo: ur, comicRepo: cr}
+}
+
+// GetTagByID retrieves tag info by ID.
+func (ts *tagSvc) GetTagByID(tagID string) (SvcRslt[model.TagInfo], SvcErr) {
+	tag, err := ts.repo.GetTagByID(nil, tagID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.TagInfo]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get tag by ID", zap.String("tagID", tagID), zap.Error(err))
+		return SvcRslt[model.TagInfo]{}, DB_FAILURE
+	}
+
+	return accept(200, poTagToModelTag(tag)), NO_ERROR
+}
+
+// RetrieveTags retrieves tags with filtering and pagination.
+func (ts *tagSvc) RetrieveTags(opt model.RetrieveTagOpt) (SvcRslt[[]model.TagInfo], SvcErr) {
+	tags, err := ts.repo.RetrieveTags(nil, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve tags", zap.Error(err))
+		return SvcRslt[[]model.TagInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.TagInfo, 0, len(tags))
+	for _, t := range tags {
+		infos = append(infos, poTagToModelTag(&t))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// CreateTag creates a new tag. Only admins are allowed.
+func (ts *tagSvc) CreateTag(opID string, args model.CreateTagArgs) (SvcRslt[model.CreateTagReply], SvcErr) {
+	if svcErr := ts.checkAdmin(opID); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateTagReply]{}, svcErr
+	}
+
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		return SvcRslt[model.CreateTagReply]{}, INVALID_TAG_DATA
+	}
+
+	if svcErr := ts.checkNameFree(name, ""); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateTagReply]{}, svcErr
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for tag", zap.Error(err))
+		return SvcRslt[model.CreateTagReply]{}, ID_GEN_FAILURE
+	}
+
+	newTag := &po.NewTag{
+		ID:                id,
+		Name:              name,
+		PicaCandidates:    normalizeTagCandidates(args.PicaCandidates),
+		EhentaiCandidates: normalizeTagCandidates(args.EhentaiCandidates),
+		CreatorID:         opID,
+	}
+
+	if err := ts.repo.CreateTag(nil, newTag); err != nil {
+		zap.L().Error("Failed to create tag", zap.String("name", name), zap.Error(err))
+		return SvcRslt[model.CreateTagReply]{}, DB_FAILURE
+	}
+
+	return accept(201, model.CreateTagReply{ID: id}), NO_ERROR
+}
+
+// UpdateTagByID updates tag info. Only admins are allowed.
+func (ts *tagSvc) UpdateTagByID(opID string, args model.UpdateTagArgs) SvcErr {
+	if svcErr := ts.checkAdmin(opID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	patch := &po.PatchTag{ID: args.ID}
+
+	if args.Name != nil {
+		name := strings.TrimSpace(*args.Name)
+		if name == "" {
+			return INVALID_TAG_DATA
+		}
+		if svcErr := ts.checkNameFree(name, args.ID); svcErr != NO_ERROR {
+			return svcErr
+		}
+		patch.Name = &name
+	}
+	if args.PicaCandidates != nil {
+		candidates := normalizeTagCandidates(*args.PicaCandidates)
+		patch.PicaCandidates = &candidates
+	}
+	if args.EhentaiCandidates != nil {
+		candidates := normalizeTagCandidates(*args.EhentaiCandidates)
+		patch.EhentaiCandidates = &candidates
+	}
+
+	if err := ts.repo.UpdateTagByID(nil, patch); err != nil {
+		zap.L().Error("Failed to update tag", zap.String("tagID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DeleteTagByID deletes a tag and detaches it everywhere. Only admins are allowed.
+func (ts *tagSvc) DeleteTagByID(opID string, tagID string) SvcErr {
+	if svcErr := ts.checkAdmin(opID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ts.repo.DeleteTagByID(nil, tagID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete tag", zap.String("tagID", tagID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// AttachTagsToComic attaches tags to a comic.
+// Only admins and the creator of the comic are allowed.
+func (ts *tagSvc) AttachTagsToComic(opID string, comicID string, tagIDs []string) SvcErr {
+	if len(tagIDs) == 0 {
+		return INVALID_TAG_DATA
+	}
+
+	if svcErr := ts.checkComicOwner(opID, comicID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	// The insertion may fail due to unknown tag IDs.
+	if err := ts.repo.AttachTagsToComic(nil, comicID, tagIDs); err != nil {
+		zap.L().Error("Failed to attach tags to comic", zap.String("comicID", comicID), zap.Strings("tagIDs", tagIDs), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DetachTagFromComic detaches a tag from a comic.
+// Only admins and the creator of the comic are allowed.
+func (ts *tagSvc) DetachTagFromComic(opID string, comicID, tagID string) SvcErr {
+	if svcErr := ts.checkComicOwner(opID, comicID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ts.repo.DetachTagFromComic(nil, comicID, tagID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to detach tag from comic", zap.String("comicID", comicID), zap.String("tagID", tagID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// AttachTagsToUser attaches tags to a user.
+// Only admins and the user themself are allowed.
+func (ts *tagSvc) AttachTagsToUser(opID string, userID string, tagIDs []string) SvcErr {
+	if len(tagIDs) == 0 {
+		return INVALID_TAG_DATA
+	}
+
+	if opID != userID {
+		if svcErr := ts.checkAdmin(opID); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	// The insertion may fail due to unknown tag IDs.
+	if err := ts.repo.AttachTagsToUser(nil, userID, tagIDs); err != nil {
+		zap.L().Error("Failed to attach tags to user", zap.String("userID", userID), zap.Strings("tagIDs", tagIDs), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DetachTagFromUser detaches a tag from a user.
+// Only admins and the user themself are allowed.
+func (ts *tagSvc) DetachTagFromUser(opID string, userID, tagID string) SvcErr {
+	if opID != userID {
+		if svcErr := ts.checkAdmin(opID); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	if err := ts.repo.DetachTagFromUser(nil, userID, tagID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to detach tag from user", zap.String("userID", userID), zap.String("tagID", tagID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// checkNameFree returns TAG_EXISTS if a tag other than selfID is named name.
+func (ts *tagSvc) checkNameFree(name, selfID string) SvcErr {
+	tag, err := ts.repo.GetTagByName(nil, name)
+	if err == repo.REC_NOT_FOUND {
+		return NO_ERROR
+	}
+	if err != nil {
+		zap.L().Error("Failed to get tag by name", zap.String("name", name), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if tag.ID != selfID {
+		return TAG_EXISTS
+	}
+
+	return NO_ERROR
+}
+
+// checkAdmin checks whether opID is an admin.
+func (ts *tagSvc) checkAdmin(opID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for tag", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to modify tags", zap.String("userID", opID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// checkComicOwner checks whether opID is an admin or the creator of the comic.
+func (ts *tagSvc) checkComicOwner(opID string, comicID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for comic tags", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	comic, err := ts.comicRepo.GetComicByID(nil, comicID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for tag check", zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin && comic.CreatorID != opID {
+		zap.L().Warn("User attempted to modify comic tags without ownership",
+			zap.String("userID", opID), zap.String("comicID", comicID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// normalizeTagCandidates trims candidates and drops empty and duplicate ones.
+func normalizeTagCandidates(candidates []string) po.StringArray {
+	res := make(po.StringArray, 0, len(candidates))
+	seen := make(map[string]struct{}, len(candidates))
+
+	for _, c := range candidates {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		res = append(res, c)
+	}
+
+	return res
+}
+
+// poTagToModelTag converts po.BasicTag to model.TagInfo
+func poTagToModelTag(t *po.BasicTag) model.TagInfo {
+	return model.TagInfo{
+		ID:                t.ID,
+		Name:              t.Name,
+		PicaCandidates:    t.PicaCandidates,
+		EhentaiCandidates: t.EhentaiCandidates,
+		CreatorID:         t.CreatorID,
+		CreatorNickname:   t.CreatorNickname,
+		CreatedAt:         t.CreatedAt.Unix(),
+		UpdatedAt:         t.UpdatedAt.Unix(),
+	}
+}
+
+// poTagsToModelTags converts po.BriefTag slice to model.TagBrief slice.
+// A non-nil slice is always returned.
+func poTagsToModelTags(tags []po.BriefTag) []model.TagBrief {
+	res := make([]model.TagBrief, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, model.TagBrief{ID: t.ID, Name: t.Name})
+	}
+
+	return res
+}
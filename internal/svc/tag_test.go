@@ -0,0 +1,64 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeNamedTagRepo struct {
+	repo.TagRepo
+
+	tags    []po.BasicTag
+	created int
+	updated int
+}
+
+func (r *fakeNamedTagRepo) GetTagByName(_ repo.Exct, name string) (*po.BasicTag, error) {
+	for _, tag := range r.tags {
+		if tag.Name == name {
+			return &tag, nil
+		}
+	}
+	return nil, repo.REC_NOT_FOUND
+}
+
+func (r *fakeNamedTagRepo) CreateTag(repo.Exct, *po.NewTag) error {
+	r.created++
+	return nil
+}
+
+func (r *fakeNamedTagRepo) UpdateTagByID(repo.Exct, *po.PatchTag) error {
+	r.updated++
+	return nil
+}
+
+func TestTagNamesTaken(t *testing.T) {
+	r := &fakeNamedTagRepo{tags: []po.BasicTag{
+		{ID: "tag-1", Name: "Romance"},
+		{ID: "tag-2", Name: "Comedy"},
+	}}
+	ts := NewTagSvc(r, fakeAdminUserRepo{}, struct{ repo.ComicRepo }{})
+
+	if _, svcErr := ts.CreateTag("u-1", model.CreateTagArgs{Name: "Romance "}); svcErr != TAG_EXISTS || svcErr.Code() != 409 {
+		t.Fatalf("create taken: got %v", svcErr)
+	}
+	if _, svcErr := ts.CreateTag("u-1", model.CreateTagArgs{Name: "Action"}); svcErr != NO_ERROR {
+		t.Fatalf("create free: got %v", svcErr)
+	}
+
+	taken, own := "Comedy", "Romance"
+	if svcErr := ts.UpdateTagByID("u-1", model.UpdateTagArgs{ID: "tag-1", Name: &taken}); svcErr != TAG_EXISTS {
+		t.Fatalf("rename to taken: got %v", svcErr)
+	}
+	// Keeping its own name is no conflict.
+	if svcErr := ts.UpdateTagByID("u-1", model.UpdateTagArgs{ID: "tag-1", Name: &own}); svcErr != NO_ERROR {
+		t.Fatalf("rename to own: got %v", svcErr)
+	}
+
+	if r.created != 1 || r.updated != 1 {
+		t.Fatalf("got %d creations, %d updates", r.created, r.updated)
+	}
+}
@@ -0,0 +1,576 @@
+package svc
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// TeamSvc defines service operations for teams.
+//
+// A team is a reusable set of users along with the roles they usually take,
+// applied to comics to assign all of them at once.
+type TeamSvc interface {
+	GetTeamByID(teamID string) (SvcRslt[model.TeamInfo], SvcErr)
+	RetrieveTeams(opt model.RetrieveTeamOpt) (SvcRslt[[]model.TeamInfo], SvcErr)
+
+	CreateTeam(opID string, meta model.ReqMeta, args model.CreateTeamArgs) (SvcRslt[string], SvcErr)
+
+	UpdateTeamByID(opID string, meta model.ReqMeta, args model.UpdateTeamArgs) SvcErr
+
+	DeleteTeamByID(opID string, meta model.ReqMeta, teamID string) SvcErr
+
+	// ApplyTeamToComic assigns the members of a team to a comic.
+	// Members assigned already are given the roles they do not hold yet.
+	ApplyTeamToComic(opID string, meta model.ReqMeta, args model.ApplyTeamArgs) (SvcRslt[model.ApplyTeamReply], SvcErr)
+}
+
+type teamSvc struct {
+	repo      repo.TeamRepo
+	comicRepo repo.ComicRepo
+	asgnRepo  repo.ComicAsgnRepo
+	userRepo  repo.UserRepo
+	audit     *auditRecorder
+	events    *comicEventRecorder
+	notifier  *notifier
+	webhooks  *webhookQueue
+}
+
+// NewTeamSvc creates a new TeamSvc. r, cr, car and ur must not be nil.
+func NewTeamSvc(
+	r repo.TeamRepo,
+	cr repo.ComicRepo,
+	car repo.ComicAsgnRepo,
+	ur repo.UserRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	nr repo.NotificationRepo,
+	whr repo.WebhookRepo,
+) TeamSvc {
+	if r == nil {
+		panic("TeamRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+	if car == nil {
+		panic("ComicAsgnRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+
+	return &teamSvc{
+		repo:      r,
+		comicRepo: cr,
+		asgnRepo:  car,
+		userRepo:  ur,
+		audit:     newAuditRecorder(alr),
+		events:    newComicEventRecorder(cer),
+		notifier:  newNotifier(nr),
+		webhooks:  newWebhookQueue(whr),
+	}
+}
+
+func (ts *teamSvc) GetTeamByID(teamID string) (SvcRslt[model.TeamInfo], SvcErr) {
+	team, err := ts.repo.GetTeamByID(nil, teamID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.TeamInfo]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get team by ID", zap.String("teamID", teamID), zap.Error(err))
+		return SvcRslt[model.TeamInfo]{}, DB_FAILURE
+	}
+
+	infos, err := ts.toTeamInfos([]po.BasicTeam{*team})
+	if err != nil {
+		zap.L().Error("Failed to get team members", zap.String("teamID", teamID), zap.Error(err))
+		return SvcRslt[model.TeamInfo]{}, DB_FAILURE
+	}
+
+	return accept(200, infos[0]), NO_ERROR
+}
+
+func (ts *teamSvc) RetrieveTeams(opt model.RetrieveTeamOpt) (SvcRslt[[]model.TeamInfo], SvcErr) {
+	teams, err := ts.repo.RetrieveTeams(nil, opt.Offset, opt.Limit)
+	if err != nil {
+		zap.L().Error("Failed to retrieve teams", zap.Error(err))
+		return SvcRslt[[]model.TeamInfo]{}, DB_FAILURE
+	}
+
+	infos, err := ts.toTeamInfos(teams)
+	if err != nil {
+		zap.L().Error("Failed to get team members", zap.Error(err))
+		return SvcRslt[[]model.TeamInfo]{}, DB_FAILURE
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// toTeamInfos converts teams to TeamInfo along with their members.
+func (ts *teamSvc) toTeamInfos(teams []po.BasicTeam) ([]model.TeamInfo, error) {
+	infos := make([]model.TeamInfo, 0, len(teams))
+	if len(teams) == 0 {
+		return infos, nil
+	}
+
+	teamIDs := make([]string, 0, len(teams))
+	for _, t := range teams {
+		teamIDs = append(teamIDs, t.ID)
+	}
+
+	members, err := ts.repo.GetMembersByTeamIDs(nil, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	membersByTeam := make(map[string][]model.TeamMemberInfo, len(teams))
+	for _, m := range members {
+		membersByTeam[m.TeamID] = append(membersByTeam[m.TeamID], model.TeamMemberInfo{
+			UserID:        m.UserID,
+			UserNickname:  m.UserNickname,
+			IsTranslator:  m.IsTranslator,
+			IsProofreader: m.IsProofreader,
+			IsTypesetter:  m.IsTypesetter,
+			IsRedrawer:    m.IsRedrawer,
+			IsReviewer:    m.IsReviewer,
+		})
+	}
+
+	for _, t := range teams {
+		teamMembers := membersByTeam[t.ID]
+		if teamMembers == nil {
+			teamMembers = []model.TeamMemberInfo{}
+		}
+
+		infos = append(infos, model.TeamInfo{
+			ID:          t.ID,
+			Name:        t.Name,
+			Description: t.Description,
+			CreatorID:   t.CreatorID,
+			Members:     teamMembers,
+			CreatedAt:   t.CreatedAt.Unix(),
+			UpdatedAt:   t.UpdatedAt.Unix(),
+		})
+	}
+
+	return infos, nil
+}
+
+func (ts *teamSvc) CreateTeam(opID string, meta model.ReqMeta, args model.CreateTeamArgs) (SvcRslt[string], SvcErr) {
+	args.Name = strings.TrimSpace(args.Name)
+	if args.Name == "" {
+		return SvcRslt[string]{}, INVALID_TEAM_DATA
+	}
+
+	if svcErr := ts.validateMembers(args.Members); svcErr != NO_ERROR {
+		return SvcRslt[string]{}, svcErr
+	}
+
+	if svcErr := ts.checkNameFree(args.Name, ""); svcErr != NO_ERROR {
+		return SvcRslt[string]{}, svcErr
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for team", zap.Error(err))
+		return SvcRslt[string]{}, ID_GEN_FAILURE
+	}
+
+	newTeam := &po.NewTeam{
+		ID:          id,
+		Name:        args.Name,
+		Description: args.Description,
+		CreatorID:   opID,
+	}
+
+	if err := ts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ts.repo.CreateTeam(tx, newTeam); err != nil {
+			return err
+		}
+
+		if err := ts.repo.CreateMembers(tx, toNewTeamMembers(id, args.Members)); err != nil {
+			return err
+		}
+
+		return ts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_CREATE,
+			EntityType: AUDIT_ENTITY_TEAM,
+			EntityID:   id,
+			After:      args,
+		})
+	}); err != nil {
+		zap.L().Error("Failed to create team", zap.String("name", args.Name), zap.Error(err))
+		return SvcRslt[string]{}, DB_FAILURE
+	}
+
+	return accept(201, id), NO_ERROR
+}
+
+func (ts *teamSvc) UpdateTeamByID(opID string, meta model.ReqMeta, args model.UpdateTeamArgs) SvcErr {
+	if args.Name != nil {
+		name := strings.TrimSpace(*args.Name)
+		if name == "" {
+			return INVALID_TEAM_DATA
+		}
+		args.Name = &name
+	}
+
+	if args.Members != nil {
+		if svcErr := ts.validateMembers(*args.Members); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	before, svcErr := ts.GetTeamByID(args.ID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if args.Name != nil {
+		if svcErr := ts.checkNameFree(*args.Name, args.ID); svcErr != NO_ERROR {
+			return svcErr
+		}
+	}
+
+	patch := &po.PatchTeam{
+		ID:          args.ID,
+		Name:        args.Name,
+		Description: args.Description,
+	}
+
+	if err := ts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ts.repo.UpdateTeamByID(tx, patch); err != nil {
+			return err
+		}
+
+		if args.Members != nil {
+			if err := ts.repo.DeleteMembersByTeamID(tx, args.ID); err != nil {
+				return err
+			}
+
+			if err := ts.repo.CreateMembers(tx, toNewTeamMembers(args.ID, *args.Members)); err != nil {
+				return err
+			}
+		}
+
+		return ts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_UPDATE,
+			EntityType: AUDIT_ENTITY_TEAM,
+			EntityID:   args.ID,
+			Before:     before.Data,
+			After:      args,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to update team", zap.String("teamID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+func (ts *teamSvc) DeleteTeamByID(opID string, meta model.ReqMeta, teamID string) SvcErr {
+	before, svcErr := ts.GetTeamByID(teamID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	// Members are deleted along by the foreign key.
+	if err := ts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ts.repo.DeleteTeamByID(tx, teamID); err != nil {
+			return err
+		}
+
+		return ts.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_TEAM,
+			EntityID:   teamID,
+			Before:     before.Data,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete team", zap.String("teamID", teamID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// ApplyTeamToComic checks the qualifications of the members again,
+// as they may have been revoked since the team was saved.
+func (ts *teamSvc) ApplyTeamToComic(
+	opID string,
+	meta model.ReqMeta,
+	args model.ApplyTeamArgs,
+) (SvcRslt[model.ApplyTeamReply], SvcErr) {
+	if _, err := ts.comicRepo.GetComicByID(nil, args.ComicID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.ApplyTeamReply]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for team", zap.String("comicID", args.ComicID), zap.Error(err))
+		return SvcRslt[model.ApplyTeamReply]{}, DB_FAILURE
+	}
+
+	preAsgns, svcErr := getTeamPreAsgns(ts.repo, args.TeamID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.ApplyTeamReply]{}, svcErr
+	}
+
+	if svcErr := validatePreAssignments(ts.userRepo, preAsgns); svcErr != NO_ERROR {
+		return SvcRslt[model.ApplyTeamReply]{}, svcErr
+	}
+
+	reply := model.ApplyTeamReply{Created: []string{}, Updated: []string{}}
+
+	if err := ts.repo.Exct().Transaction(func(tx repo.Exct) error {
+		now := time.Now()
+
+		for i := range preAsgns {
+			preAsgn := &preAsgns[i]
+
+			before, err := ts.asgnRepo.GetAsgnsByUserAndComicID(tx, preAsgn.AssigneeID, args.ComicID)
+			if err != nil && err != repo.REC_NOT_FOUND {
+				return err
+			}
+
+			asgnID := ""
+			if before != nil {
+				asgnID = before.ID
+			} else if asgnID, err = genUUID(); err != nil {
+				return err
+			}
+
+			// Only the roles not held yet are assigned.
+			patch := po.PatchComicAsgn{ID: asgnID}
+			missing := false
+			for _, f := range asgnRoleFields {
+				if isRole := *f.preAsgn(preAsgn); isRole != nil && *isRole &&
+					(before == nil || f.assigned(before) == nil) {
+					*f.patchAssigned(&patch) = &now
+					missing = true
+				}
+			}
+
+			if !missing {
+				continue
+			}
+
+			if before == nil {
+				if err := ts.asgnRepo.CreateAsgn(tx, &po.NewComicAsgn{
+					ID:      asgnID,
+					ComicID: args.ComicID,
+					UserID:  preAsgn.AssigneeID,
+				}); err != nil {
+					return err
+				}
+			}
+
+			if err := ts.asgnRepo.UpdateAsgnByID(tx, &patch); err != nil {
+				return err
+			}
+
+			after, err := ts.asgnRepo.GetAsgnByID(tx, asgnID)
+			if err != nil {
+				return err
+			}
+
+			kind := COMIC_EVENT_ASGN_CREATE
+			detail := asgnEventDetail{
+				AsgnID: asgnID,
+				UserID: preAsgn.AssigneeID,
+				Roles:  basicAsgnRoleNames(after),
+			}
+			entry := auditEntry{
+				Action:     AUDIT_ACTION_CREATE,
+				EntityType: AUDIT_ENTITY_ASGN,
+				EntityID:   asgnID,
+				After:      after,
+			}
+
+			if before != nil {
+				kind = COMIC_EVENT_ASGN_UPDATE
+				detail.PrevRoles = basicAsgnRoleNames(before)
+				entry.Action = AUDIT_ACTION_UPDATE
+				entry.Before = before
+				reply.Updated = append(reply.Updated, asgnID)
+			} else {
+				reply.Created = append(reply.Created, asgnID)
+			}
+
+			if err := ts.events.record(tx, args.ComicID, opID, kind, detail); err != nil {
+				return err
+			}
+
+			// Roles added to existing assignments are not posted.
+			if before == nil {
+				if err := ts.webhooks.enqueue(tx, WEBHOOK_EVENT_ASGN_CREATE, args.ComicID, opID, detail); err != nil {
+					return err
+				}
+			}
+
+			// Only the roles newly assigned are notified.
+			if err := ts.notifier.notify(tx, []string{preAsgn.AssigneeID}, notification{
+				Kind:    NOTIFICATION_ASSIGNED,
+				ComicID: args.ComicID,
+				ActorID: opID,
+				Detail: assignedNotification{
+					AsgnID: asgnID,
+					Roles: asgnRoleNames(
+						patch.AssignedTranslatorAt,
+						patch.AssignedProofreaderAt,
+						patch.AssignedTypesetterAt,
+						patch.AssignedRedrawerAt,
+						patch.AssignedReviewerAt,
+					),
+				},
+			}); err != nil {
+				return err
+			}
+
+			if err := ts.audit.record(tx, opID, meta, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		zap.L().Error("Failed to apply team to comic",
+			zap.String("teamID", args.TeamID), zap.String("comicID", args.ComicID), zap.Error(err))
+		return SvcRslt[model.ApplyTeamReply]{}, DB_FAILURE
+	}
+
+	return accept(200, reply), NO_ERROR
+}
+
+// validateMembers checks that members are given, each once with some role,
+// and qualified for their roles.
+func (ts *teamSvc) validateMembers(members []model.TeamMemberArgs) SvcErr {
+	if len(members) == 0 {
+		return INVALID_TEAM_DATA
+	}
+
+	seen := make(map[string]struct{}, len(members))
+	preAsgns := make([]model.PreAsgnArgs, 0, len(members))
+
+	for _, m := range members {
+		if _, ok := seen[m.UserID]; ok || strings.TrimSpace(m.UserID) == "" {
+			return INVALID_TEAM_DATA
+		}
+		seen[m.UserID] = struct{}{}
+
+		if !m.IsTranslator && !m.IsProofreader && !m.IsTypesetter && !m.IsRedrawer && !m.IsReviewer {
+			return INVALID_TEAM_DATA
+		}
+
+		preAsgns = append(preAsgns, teamMemberToPreAsgn(
+			m.UserID, m.IsTranslator, m.IsProofreader, m.IsTypesetter, m.IsRedrawer, m.IsReviewer,
+		))
+	}
+
+	return validatePreAssignments(ts.userRepo, preAsgns)
+}
+
+// checkNameFree returns TEAM_EXISTS if a team other than selfID is named name.
+func (ts *teamSvc) checkNameFree(name, selfID string) SvcErr {
+	team, err := ts.repo.GetTeamByName(nil, name)
+	if err == repo.REC_NOT_FOUND {
+		return NO_ERROR
+	}
+	if err != nil {
+		zap.L().Error("Failed to get team by name", zap.String("name", name), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if team.ID != selfID {
+		return TEAM_EXISTS
+	}
+
+	return NO_ERROR
+}
+
+func toNewTeamMembers(teamID string, members []model.TeamMemberArgs) []po.NewTeamMember {
+	newMembers := make([]po.NewTeamMember, 0, len(members))
+
+	for _, m := range members {
+		newMembers = append(newMembers, po.NewTeamMember{
+			TeamID:        teamID,
+			UserID:        m.UserID,
+			IsTranslator:  m.IsTranslator,
+			IsProofreader: m.IsProofreader,
+			IsTypesetter:  m.IsTypesetter,
+			IsRedrawer:    m.IsRedrawer,
+			IsReviewer:    m.IsReviewer,
+		})
+	}
+
+	return newMembers
+}
+
+// getTeamPreAsgns loads the members of a team as pre-assignments.
+func getTeamPreAsgns(teamRepo repo.TeamRepo, teamID string) ([]model.PreAsgnArgs, SvcErr) {
+	if _, err := teamRepo.GetTeamByID(nil, teamID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return nil, NOT_FOUND
+		}
+		zap.L().Error("Failed to get team by ID", zap.String("teamID", teamID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	members, err := teamRepo.GetMembersByTeamIDs(nil, []string{teamID})
+	if err != nil {
+		zap.L().Error("Failed to get team members", zap.String("teamID", teamID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	preAsgns := make([]model.PreAsgnArgs, 0, len(members))
+	for _, m := range members {
+		preAsgns = append(preAsgns, teamMemberToPreAsgn(
+			m.UserID, m.IsTranslator, m.IsProofreader, m.IsTypesetter, m.IsRedrawer, m.IsReviewer,
+		))
+	}
+
+	return preAsgns, NO_ERROR
+}
+
+func teamMemberToPreAsgn(userID string, translator, proofreader, typesetter, redrawer, reviewer bool) model.PreAsgnArgs {
+	return model.PreAsgnArgs{
+		AssigneeID:    userID,
+		IsTranslator:  &translator,
+		IsProofreader: &proofreader,
+		IsTypesetter:  &typesetter,
+		IsRedrawer:    &redrawer,
+		IsReviewer:    &reviewer,
+	}
+}
+
+// mergePreAsgns merges pre-assignments of the same user, who then holds the roles of either.
+func mergePreAsgns(preAsgns ...[]model.PreAsgnArgs) []model.PreAsgnArgs {
+	merged := []model.PreAsgnArgs{}
+
+	for _, p := range slices.Concat(preAsgns...) {
+		i := slices.IndexFunc(merged, func(m model.PreAsgnArgs) bool { return m.AssigneeID == p.AssigneeID })
+		if i < 0 {
+			merged = append(merged, model.PreAsgnArgs{AssigneeID: p.AssigneeID})
+			i = len(merged) - 1
+		}
+
+		for _, f := range asgnRoleFields {
+			if isRole := *f.preAsgn(&p); isRole != nil && *isRole {
+				*f.preAsgn(&merged[i]) = isRole
+			}
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,205 @@
+package svc
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+)
+
+const (
+	TERM_FIELD_TRANSLATED = "translated_text"
+	TERM_FIELD_PROVED     = "proved_text"
+)
+
+// termRendering is one distinct target text of a source text.
+type termRendering struct {
+	norm string
+	term po.BasicTerm
+}
+
+// effectiveUnitText returns the text a reader will finally see for a unit:
+// the proved text if any, otherwise the translated text.
+func effectiveUnitText(u *po.BasicComicUnit) string {
+	if u.ProvedText != nil && *u.ProvedText != "" {
+		return *u.ProvedText
+	}
+	if u.TranslatedText != nil {
+		return *u.TranslatedText
+	}
+
+	return ""
+}
+
+// isWordRune reports whether r is part of a word of a script written with spaces
+// between words. Scripts without them, such as Chinese and Japanese, have no word
+// boundaries to respect, and terms in them are matched anywhere.
+func isWordRune(r rune) bool {
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return false
+	}
+
+	return !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai)
+}
+
+// containsTerm reports whether target appears in text as a whole word,
+// so that the term "ann" is found in "ann, wait" but not in "anna" or "joanne".
+// Both are expected in lower case.
+func containsTerm(text, target string) bool {
+	if target == "" {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(target)
+	last, _ := utf8.DecodeLastRuneInString(target)
+
+	for off := 0; off < len(text); {
+		i := strings.Index(text[off:], target)
+		if i < 0 {
+			return false
+		}
+		start, end := off+i, off+i+len(target)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+
+		if !(isWordRune(before) && isWordRune(first)) && !(isWordRune(after) && isWordRune(last)) {
+			return true
+		}
+
+		off = start + 1
+	}
+
+	return false
+}
+
+// annotateUnitTerms fills TermHits and TermInconsistencies of infos.
+//
+// A term hits a unit when its target text appears as a whole word in the unit's
+// translated or proved text, ignoring case.
+//
+// Units carry no source text, so a rendering can only be told inconsistent
+// among the renderings the termbases know of. A hit is inconsistent when the
+// linked termbases give its source text other target texts too, and some other
+// unit of the comic uses one of them. Terms with a single rendering, as in a
+// one-to-one glossary, are hit but never inconsistent.
+func annotateUnitTerms(infos []model.ComicUnitInfo, terms []po.BasicTerm, comicUnits []po.BasicComicUnit) {
+	if len(terms) == 0 {
+		return
+	}
+
+	// Group distinct renderings by source text.
+	renderings := make(map[string][]termRendering)
+	for _, t := range terms {
+		src := strings.ToLower(t.SourceText)
+		tgt := strings.ToLower(t.TargetText)
+
+		dup := false
+		for _, r := range renderings[src] {
+			if r.norm == tgt {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			renderings[src] = append(renderings[src], termRendering{norm: tgt, term: t})
+		}
+	}
+
+	// Find which units use each rendering that has alternatives.
+	usages := make(map[string][]string)
+	for i := range comicUnits {
+		text := strings.ToLower(effectiveUnitText(&comicUnits[i]))
+		if text == "" {
+			continue
+		}
+
+		for _, rs := range renderings {
+			if len(rs) < 2 {
+				continue
+			}
+			for _, r := range rs {
+				if containsTerm(text, r.norm) {
+					usages[r.norm] = append(usages[r.norm], comicUnits[i].ID)
+				}
+			}
+		}
+	}
+
+	for i := range infos {
+		info := &infos[i]
+
+		fields := []struct {
+			name string
+			text *string
+		}{
+			{TERM_FIELD_TRANSLATED, info.TranslatedText},
+			{TERM_FIELD_PROVED, info.ProvedText},
+		}
+
+		for _, f := range fields {
+			if f.text == nil || *f.text == "" {
+				continue
+			}
+
+			text := strings.ToLower(*f.text)
+			for _, t := range terms {
+				if containsTerm(text, strings.ToLower(t.TargetText)) {
+					info.TermHits = append(info.TermHits, model.TermHit{
+						TermID:     t.ID,
+						TermbaseID: t.TermbaseID,
+						SourceText: t.SourceText,
+						TargetText: t.TargetText,
+						Field:      f.name,
+					})
+				}
+			}
+		}
+
+		text := info.TranslatedText
+		if info.ProvedText != nil && *info.ProvedText != "" {
+			text = info.ProvedText
+		}
+		if text == nil || *text == "" {
+			continue
+		}
+		lowered := strings.ToLower(*text)
+
+		for _, rs := range renderings {
+			if len(rs) < 2 {
+				continue
+			}
+
+			for _, used := range rs {
+				if !containsTerm(lowered, used.norm) {
+					continue
+				}
+
+				for _, other := range rs {
+					if other.norm == used.norm {
+						continue
+					}
+
+					var unitIDs []string
+					for _, id := range usages[other.norm] {
+						if id != info.ID {
+							unitIDs = append(unitIDs, id)
+						}
+					}
+					if len(unitIDs) == 0 {
+						continue
+					}
+
+					info.TermInconsistencies = append(info.TermInconsistencies, model.TermInconsistency{
+						SourceText:      used.term.SourceText,
+						TargetText:      used.term.TargetText,
+						OtherTargetText: other.term.TargetText,
+						UnitIDs:         unitIDs,
+					})
+				}
+			}
+		}
+	}
+}
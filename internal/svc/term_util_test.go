@@ -0,0 +1,97 @@
+package svc
+
+import (
+	"slices"
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+)
+
+func TestContainsTerm(t *testing.T) {
+	cases := []struct {
+		text   string
+		target string
+		want   bool
+	}{
+		{"ann, wait!", "ann", true},
+		{"wait for ann", "ann", true},
+		{"“ann”", "ann", true},
+		{"anna left", "ann", false},
+		{"joanne left", "ann", false},
+		{"joanne and ann left", "ann", true},
+		{"ann-marie left", "ann-marie", true},
+		{"the mages' guild", "mages' guild", true},
+		{"the 3rd floor", "3rd", true},
+		{"the 33rd floor", "3rd", false},
+		// Scripts written without spaces are matched anywhere.
+		{"安娜说她不去", "安娜", true},
+		{"アンナさん", "アンナ", true},
+		{"安娜ann", "ann", true},
+		{"", "ann", false},
+		{"ann", "", false},
+	}
+
+	for _, c := range cases {
+		if got := containsTerm(c.text, c.target); got != c.want {
+			t.Errorf("containsTerm(%q, %q) = %v, want %v", c.text, c.target, got, c.want)
+		}
+	}
+}
+
+func TestAnnotateUnitTerms(t *testing.T) {
+	terms := []po.BasicTerm{
+		{ID: "term-1", TermbaseID: "tb-1", SourceText: "アンナ", TargetText: "Anna"},
+		{ID: "term-2", TermbaseID: "tb-2", SourceText: "アンナ", TargetText: "Ann"},
+		{ID: "term-3", TermbaseID: "tb-1", SourceText: "魔法", TargetText: "magic"},
+	}
+
+	units := []po.BasicComicUnit{
+		{ID: "unit-1", TranslatedText: strPtr("Anna cast magic.")},
+		{ID: "unit-2", TranslatedText: strPtr("Where is Ann?")},
+		{ID: "unit-3", TranslatedText: strPtr("Joanne"), ProvedText: strPtr("Magical Joanne")},
+	}
+
+	infos := make([]model.ComicUnitInfo, len(units))
+	for i := range units {
+		infos[i] = unitInfo(&units[i])
+	}
+
+	annotateUnitTerms(infos, terms, units)
+
+	hits := func(info model.ComicUnitInfo) []string {
+		var ids []string
+		for _, h := range info.TermHits {
+			ids = append(ids, h.TermID+"@"+h.Field)
+		}
+		return ids
+	}
+
+	if got, want := hits(infos[0]), []string{"term-1@" + TERM_FIELD_TRANSLATED, "term-3@" + TERM_FIELD_TRANSLATED}; !slices.Equal(got, want) {
+		t.Errorf("unit-1 hits: got %v, want %v", got, want)
+	}
+	if got, want := hits(infos[1]), []string{"term-2@" + TERM_FIELD_TRANSLATED}; !slices.Equal(got, want) {
+		t.Errorf("unit-2 hits: got %v, want %v", got, want)
+	}
+	if got := hits(infos[2]); got != nil {
+		t.Errorf("unit-3 hits: got %v, want none", got)
+	}
+
+	// Anna and Ann render the same source, each used by the other unit.
+	for i, other := range []string{"unit-2", "unit-1"} {
+		inc := infos[i].TermInconsistencies
+		if len(inc) != 1 || inc[0].SourceText != "アンナ" || !slices.Equal(inc[0].UnitIDs, []string{other}) {
+			t.Errorf("%s inconsistencies: got %+v", infos[i].ID, inc)
+		}
+	}
+	if inc := infos[2].TermInconsistencies; inc != nil {
+		t.Errorf("unit-3 inconsistencies: got %+v", inc)
+	}
+
+	// A one-to-one glossary only produces hits.
+	infos = []model.ComicUnitInfo{unitInfo(&units[0])}
+	annotateUnitTerms(infos, terms[2:], units)
+	if len(infos[0].TermHits) != 1 || infos[0].TermInconsistencies != nil {
+		t.Errorf("one-to-one glossary: got %+v", infos[0])
+	}
+}
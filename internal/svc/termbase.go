@@ -0,0 +1,704 @@
+package svc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+	termbasePkg "poprako-main-server/internal/svc/termbase"
+
+	"go.uber.org/zap"
+)
+
+// TermbaseSvc defines service operations for termbases and their terms.
+type TermbaseSvc interface {
+	GetTermbaseByID(termbaseID string) (SvcRslt[model.TermbaseInfo], SvcErr)
+	RetrieveTermbases(opt model.RetrieveTermbaseOpt) (SvcRslt[[]model.TermbaseInfo], SvcErr)
+
+	CreateTermbase(opID string, args model.CreateTermbaseArgs) (SvcRslt[model.CreateTermbaseReply], SvcErr)
+
+	UpdateTermbaseByID(opID string, args model.UpdateTermbaseArgs) SvcErr
+
+	DeleteTermbaseByID(opID string, termbaseID string) SvcErr
+
+	RetrieveTerms(termbaseID string, opt model.RetrieveTermOpt) (SvcRslt[[]model.TermInfo], SvcErr)
+
+	CreateTerm(opID string, args model.CreateTermArgs) (SvcRslt[model.CreateTermReply], SvcErr)
+
+	UpdateTermByID(opID string, args model.UpdateTermArgs) SvcErr
+
+	DeleteTermByID(opID string, termbaseID, termID string) SvcErr
+
+	ImportTerms(
+		opID string,
+		termbaseID string,
+		fileName string,
+		reader io.Reader,
+		dryRun bool,
+	) (SvcRslt[model.ImportTermsReply], SvcErr)
+
+	ExportTerms(termbaseID string, format string) (SvcRslt[model.ExportTermsReply], SvcErr)
+
+	GetTermbasesByComicID(comicID string) (SvcRslt[[]model.TermbaseInfo], SvcErr)
+
+	LinkTermbaseToComic(opID string, comicID, termbaseID string) SvcErr
+
+	UnlinkTermbaseFromComic(opID string, comicID, termbaseID string) SvcErr
+}
+
+type termbaseSvc struct {
+	repo      repo.TermbaseRepo
+	termRepo  repo.TermRepo
+	userRepo  repo.UserRepo
+	comicRepo repo.ComicRepo
+}
+
+// NewTermbaseSvc creates a new TermbaseSvc. r, tr, ur and cr must not be nil.
+func NewTermbaseSvc(r repo.TermbaseRepo, tr repo.TermRepo, ur repo.UserRepo, cr repo.ComicRepo) TermbaseSvc {
+	if r == nil {
+		panic("TermbaseRepo cannot be nil")
+	}
+	if tr == nil {
+		panic("TermRepo cannot be nil")
+	}
+	if ur == nil {
+		panic("UserRepo cannot be nil")
+	}
+	if cr == nil {
+		panic("ComicRepo cannot be nil")
+	}
+
+	return &termbaseSvc{repo: r, termRepo: tr, userRepo: ur, comicRepo: cr}
+}
+
+// GetTermbaseByID retrieves termbase info by ID.
+func (ts *termbaseSvc) GetTermbaseByID(termbaseID string) (SvcRslt[model.TermbaseInfo], SvcErr) {
+	tb, err := ts.repo.GetTermbaseByID(nil, termbaseID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.TermbaseInfo]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get termbase by ID", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[model.TermbaseInfo]{}, DB_FAILURE
+	}
+
+	return accept(200, poTermbaseToModelTermbase(tb)), NO_ERROR
+}
+
+// RetrieveTermbases retrieves termbases with filtering and pagination.
+func (ts *termbaseSvc) RetrieveTermbases(opt model.RetrieveTermbaseOpt) (SvcRslt[[]model.TermbaseInfo], SvcErr) {
+	tbs, err := ts.repo.RetrieveTermbases(nil, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve termbases", zap.Error(err))
+		return SvcRslt[[]model.TermbaseInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.TermbaseInfo, 0, len(tbs))
+	for _, tb := range tbs {
+		infos = append(infos, poTermbaseToModelTermbase(&tb))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// CreateTermbase creates a new termbase. Only admins are allowed.
+func (ts *termbaseSvc) CreateTermbase(
+	opID string,
+	args model.CreateTermbaseArgs,
+) (SvcRslt[model.CreateTermbaseReply], SvcErr) {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get user info for termbase creation", zap.String("userID", opID), zap.Error(err))
+		return SvcRslt[model.CreateTermbaseReply]{}, DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to create termbase", zap.String("userID", opID))
+		return SvcRslt[model.CreateTermbaseReply]{}, PERMISSION_DENIED
+	}
+
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		return SvcRslt[model.CreateTermbaseReply]{}, INVALID_TERMBASE_DATA
+	}
+
+	if svcErr := ts.checkNameFree(name, ""); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateTermbaseReply]{}, svcErr
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for termbase", zap.Error(err))
+		return SvcRslt[model.CreateTermbaseReply]{}, ID_GEN_FAILURE
+	}
+
+	newTermbase := &po.NewTermbase{
+		ID:          id,
+		Name:        name,
+		Description: args.Description,
+		CreatorID:   opID,
+	}
+
+	if err := ts.repo.CreateTermbase(nil, newTermbase); err != nil {
+		zap.L().Error("Failed to create termbase", zap.String("name", name), zap.Error(err))
+		return SvcRslt[model.CreateTermbaseReply]{}, DB_FAILURE
+	}
+
+	return accept(201, model.CreateTermbaseReply{ID: id}), NO_ERROR
+}
+
+// UpdateTermbaseByID updates termbase info. Only admins and the creator are allowed.
+func (ts *termbaseSvc) UpdateTermbaseByID(opID string, args model.UpdateTermbaseArgs) SvcErr {
+	if svcErr := ts.checkTermbaseOwner(opID, args.ID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if args.Name != nil {
+		name := strings.TrimSpace(*args.Name)
+		if name == "" {
+			return INVALID_TERMBASE_DATA
+		}
+		if svcErr := ts.checkNameFree(name, args.ID); svcErr != NO_ERROR {
+			return svcErr
+		}
+		args.Name = &name
+	}
+
+	patch := &po.PatchTermbase{
+		ID:          args.ID,
+		Name:        args.Name,
+		Description: args.Description,
+	}
+
+	if err := ts.repo.UpdateTermbaseByID(nil, patch); err != nil {
+		zap.L().Error("Failed to update termbase", zap.String("termbaseID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DeleteTermbaseByID deletes a termbase and all its terms.
+// Only admins and the creator are allowed.
+func (ts *termbaseSvc) DeleteTermbaseByID(opID string, termbaseID string) SvcErr {
+	if svcErr := ts.checkTermbaseOwner(opID, termbaseID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ts.repo.DeleteTermbaseByID(nil, termbaseID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			zap.L().Warn("Termbase not found for deletion", zap.String("termbaseID", termbaseID))
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete termbase", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// RetrieveTerms retrieves terms of a termbase with fuzzy source text search and pagination.
+func (ts *termbaseSvc) RetrieveTerms(termbaseID string, opt model.RetrieveTermOpt) (SvcRslt[[]model.TermInfo], SvcErr) {
+	terms, err := ts.termRepo.RetrieveTerms(nil, termbaseID, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve terms", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[[]model.TermInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.TermInfo, 0, len(terms))
+	for _, t := range terms {
+		infos = append(infos, poTermToModelTerm(&t))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// CreateTerm creates a new term in a termbase.
+// Admins, translators and proofreaders are allowed.
+func (ts *termbaseSvc) CreateTerm(opID string, args model.CreateTermArgs) (SvcRslt[model.CreateTermReply], SvcErr) {
+	if svcErr := ts.checkTermEditor(opID); svcErr != NO_ERROR {
+		return SvcRslt[model.CreateTermReply]{}, svcErr
+	}
+
+	src := strings.TrimSpace(args.SourceText)
+	tgt := strings.TrimSpace(args.TargetText)
+	if src == "" || tgt == "" {
+		return SvcRslt[model.CreateTermReply]{}, INVALID_TERM_DATA
+	}
+
+	// Verify termbase exists
+	if _, err := ts.repo.GetTermbaseByID(nil, args.TermbaseID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.CreateTermReply]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to verify termbase exists", zap.String("termbaseID", args.TermbaseID), zap.Error(err))
+		return SvcRslt[model.CreateTermReply]{}, DB_FAILURE
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for term", zap.Error(err))
+		return SvcRslt[model.CreateTermReply]{}, ID_GEN_FAILURE
+	}
+
+	newTerm := po.NewTerm{
+		ID:         id,
+		TermbaseID: args.TermbaseID,
+		SourceText: src,
+		TargetText: tgt,
+		Note:       args.Note,
+		CreatorID:  opID,
+	}
+
+	if err := ts.termRepo.CreateTerms(nil, []po.NewTerm{newTerm}); err != nil {
+		zap.L().Error("Failed to create term", zap.String("termbaseID", args.TermbaseID), zap.Error(err))
+		return SvcRslt[model.CreateTermReply]{}, DB_FAILURE
+	}
+
+	return accept(201, model.CreateTermReply{ID: id}), NO_ERROR
+}
+
+// UpdateTermByID updates a term. Admins, translators and proofreaders are allowed.
+func (ts *termbaseSvc) UpdateTermByID(opID string, args model.UpdateTermArgs) SvcErr {
+	if svcErr := ts.checkTermEditor(opID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if svcErr := ts.checkTermInTermbase(args.TermbaseID, args.ID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if args.SourceText != nil {
+		src := strings.TrimSpace(*args.SourceText)
+		if src == "" {
+			return INVALID_TERM_DATA
+		}
+		args.SourceText = &src
+	}
+	if args.TargetText != nil {
+		tgt := strings.TrimSpace(*args.TargetText)
+		if tgt == "" {
+			return INVALID_TERM_DATA
+		}
+		args.TargetText = &tgt
+	}
+
+	patch := &po.PatchTerm{
+		ID:         args.ID,
+		SourceText: args.SourceText,
+		TargetText: args.TargetText,
+		Note:       args.Note,
+	}
+
+	if err := ts.termRepo.UpdateTermByID(nil, patch); err != nil {
+		zap.L().Error("Failed to update term", zap.String("termID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// DeleteTermByID deletes a term. Admins, translators and proofreaders are allowed.
+func (ts *termbaseSvc) DeleteTermByID(opID string, termbaseID, termID string) SvcErr {
+	if svcErr := ts.checkTermEditor(opID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if svcErr := ts.checkTermInTermbase(termbaseID, termID); svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ts.termRepo.DeleteTermByID(nil, termID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete term", zap.String("termID", termID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// ImportTerms imports terms from a CSV or TBX file into a termbase.
+// Entries duplicating an existing term or an earlier entry are skipped,
+// and entries giving a different target for a known source are reported as conflicts.
+// In dry-run mode nothing is written. Only admins and the creator are allowed.
+func (ts *termbaseSvc) ImportTerms(
+	opID string,
+	termbaseID string,
+	fileName string,
+	reader io.Reader,
+	dryRun bool,
+) (SvcRslt[model.ImportTermsReply], SvcErr) {
+	if svcErr := ts.checkTermbaseOwner(opID, termbaseID); svcErr != NO_ERROR {
+		return SvcRslt[model.ImportTermsReply]{}, svcErr
+	}
+
+	var (
+		parsed []termbasePkg.ParsedTerm
+		err    error
+	)
+
+	switch termbasePkg.FormatByFileName(fileName) {
+	case termbasePkg.FORMAT_CSV:
+		parsed, err = termbasePkg.ParseCSV(reader)
+	case termbasePkg.FORMAT_TBX:
+		parsed, err = termbasePkg.ParseTBX(reader)
+	default:
+		zap.L().Warn("Unsupported term file extension", zap.String("termbaseID", termbaseID), zap.String("fileName", fileName))
+		return SvcRslt[model.ImportTermsReply]{}, INVALID_TERM_FILE_EXT
+	}
+	if err != nil {
+		zap.L().Warn("Failed to parse term file",
+			zap.String("termbaseID", termbaseID),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+
+		svcErr := INVALID_TERM_FILE_DATA
+
+		var parseErr *termbasePkg.ParseError
+		if !errors.As(err, &parseErr) {
+			return SvcRslt[model.ImportTermsReply]{}, svcErr
+		}
+
+		return SvcRslt[model.ImportTermsReply]{Code: svcErr.Code(), Data: &model.ImportTermsReply{
+			DryRun:     dryRun,
+			Duplicates: []model.TermImportIssue{},
+			Conflicts:  []model.TermImportIssue{},
+			Invalid:    &model.TermFileIssue{Pos: parseErr.Pos, Reason: parseErr.Reason},
+		}}, svcErr
+	}
+
+	existing, err := ts.termRepo.RetrieveTerms(nil, termbaseID, model.RetrieveTermOpt{})
+	if err != nil {
+		zap.L().Error("Failed to retrieve existing terms for import", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[model.ImportTermsReply]{}, DB_FAILURE
+	}
+
+	reply := model.ImportTermsReply{
+		DryRun:     dryRun,
+		Total:      len(parsed),
+		Duplicates: []model.TermImportIssue{},
+		Conflicts:  []model.TermImportIssue{},
+	}
+
+	// Known renderings by lowered source text,
+	// from the termbase first and then from earlier entries.
+	type rendering struct {
+		termID *string
+		pos    *int
+		target string
+	}
+	known := make(map[string][]rendering)
+	for _, t := range existing {
+		src := strings.ToLower(t.SourceText)
+		known[src] = append(known[src], rendering{termID: &t.ID, target: t.TargetText})
+	}
+
+	var newTerms []po.NewTerm
+	for _, p := range parsed {
+		src := strings.ToLower(p.SourceText)
+
+		issue := model.TermImportIssue{
+			Pos:        p.Pos,
+			SourceText: p.SourceText,
+			TargetText: p.TargetText,
+		}
+
+		var dup, conflict *rendering
+		for i, r := range known[src] {
+			if strings.EqualFold(r.target, p.TargetText) {
+				dup = &known[src][i]
+				break
+			}
+			if conflict == nil {
+				conflict = &known[src][i]
+			}
+		}
+
+		if dup != nil {
+			issue.ExistingTermID = dup.termID
+			issue.ExistingPos = dup.pos
+			issue.ExistingTargetText = dup.target
+			reply.Duplicates = append(reply.Duplicates, issue)
+			continue
+		}
+
+		if conflict != nil {
+			issue.ExistingTermID = conflict.termID
+			issue.ExistingPos = conflict.pos
+			issue.ExistingTargetText = conflict.target
+			reply.Conflicts = append(reply.Conflicts, issue)
+		}
+
+		pos := p.Pos
+		known[src] = append(known[src], rendering{pos: &pos, target: p.TargetText})
+
+		id, err := genUUID()
+		if err != nil {
+			zap.L().Error("Failed to generate UUID for imported term", zap.Error(err))
+			return SvcRslt[model.ImportTermsReply]{}, ID_GEN_FAILURE
+		}
+
+		newTerms = append(newTerms, po.NewTerm{
+			ID:         id,
+			TermbaseID: termbaseID,
+			SourceText: p.SourceText,
+			TargetText: p.TargetText,
+			Note:       p.Note,
+			CreatorID:  opID,
+		})
+	}
+
+	reply.Created = len(newTerms)
+
+	if dryRun {
+		return accept(200, reply), NO_ERROR
+	}
+
+	if err := ts.termRepo.CreateTerms(nil, newTerms); err != nil {
+		zap.L().Error("Failed to create imported terms",
+			zap.String("termbaseID", termbaseID),
+			zap.Int("count", len(newTerms)),
+			zap.Error(err))
+		return SvcRslt[model.ImportTermsReply]{}, DB_FAILURE
+	}
+
+	return accept(200, reply), NO_ERROR
+}
+
+// ExportTerms exports all terms of a termbase as CSV or TBX.
+func (ts *termbaseSvc) ExportTerms(termbaseID string, format string) (SvcRslt[model.ExportTermsReply], SvcErr) {
+	if format != termbasePkg.FORMAT_CSV && format != termbasePkg.FORMAT_TBX {
+		zap.L().Warn("Invalid term export format", zap.String("termbaseID", termbaseID), zap.String("format", format))
+		return SvcRslt[model.ExportTermsReply]{}, INVALID_EXPORT_FORMAT
+	}
+
+	tb, err := ts.repo.GetTermbaseByID(nil, termbaseID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return SvcRslt[model.ExportTermsReply]{}, NOT_FOUND
+		}
+		zap.L().Error("Failed to get termbase for export", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[model.ExportTermsReply]{}, DB_FAILURE
+	}
+
+	terms, err := ts.termRepo.RetrieveTerms(nil, termbaseID, model.RetrieveTermOpt{})
+	if err != nil {
+		zap.L().Error("Failed to retrieve terms for export", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[model.ExportTermsReply]{}, DB_FAILURE
+	}
+
+	var buf bytes.Buffer
+
+	reply := model.ExportTermsReply{FileName: tb.Name + "." + format}
+
+	switch format {
+	case termbasePkg.FORMAT_CSV:
+		reply.ContentType = "text/csv; charset=utf-8"
+		err = termbasePkg.WriteCSV(&buf, terms)
+	case termbasePkg.FORMAT_TBX:
+		reply.ContentType = "application/x-tbx+xml; charset=utf-8"
+		err = termbasePkg.WriteTBX(&buf, tb.Name, terms)
+	}
+	if err != nil {
+		zap.L().Error("Failed to encode terms for export", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return SvcRslt[model.ExportTermsReply]{}, DB_FAILURE
+	}
+
+	reply.Content = buf.Bytes()
+
+	return accept(200, reply), NO_ERROR
+}
+
+// GetTermbasesByComicID retrieves the termbases linked to a comic.
+func (ts *termbaseSvc) GetTermbasesByComicID(comicID string) (SvcRslt[[]model.TermbaseInfo], SvcErr) {
+	tbs, err := ts.repo.GetTermbasesByComicID(nil, comicID)
+	if err != nil {
+		zap.L().Error("Failed to get termbases by comic ID", zap.String("comicID", comicID), zap.Error(err))
+		return SvcRslt[[]model.TermbaseInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.TermbaseInfo, 0, len(tbs))
+	for _, tb := range tbs {
+		infos = append(infos, poTermbaseToModelTermbase(&tb))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// LinkTermbaseToComic links a termbase to a comic. Only admins are allowed.
+func (ts *termbaseSvc) LinkTermbaseToComic(opID string, comicID, termbaseID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for termbase linking", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to link termbase to comic", zap.String("userID", opID))
+		return PERMISSION_DENIED
+	}
+
+	if _, err := ts.comicRepo.GetComicByID(nil, comicID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to verify comic exists", zap.String("comicID", comicID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if _, err := ts.repo.GetTermbaseByID(nil, termbaseID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to verify termbase exists", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := ts.repo.LinkTermbaseToComic(nil, comicID, termbaseID); err != nil {
+		zap.L().Error("Failed to link termbase to comic",
+			zap.String("comicID", comicID), zap.String("termbaseID", termbaseID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// UnlinkTermbaseFromComic removes the link between a termbase and a comic.
+// Only admins are allowed.
+func (ts *termbaseSvc) UnlinkTermbaseFromComic(opID string, comicID, termbaseID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for termbase unlinking", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin {
+		zap.L().Warn("Non-admin user attempted to unlink termbase from comic", zap.String("userID", opID))
+		return PERMISSION_DENIED
+	}
+
+	if err := ts.repo.UnlinkTermbaseFromComic(nil, comicID, termbaseID); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to unlink termbase from comic",
+			zap.String("comicID", comicID), zap.String("termbaseID", termbaseID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// checkNameFree returns TERMBASE_EXISTS if a termbase other than selfID is named name.
+func (ts *termbaseSvc) checkNameFree(name, selfID string) SvcErr {
+	tb, err := ts.repo.GetTermbaseByName(nil, name)
+	if err == repo.REC_NOT_FOUND {
+		return NO_ERROR
+	}
+	if err != nil {
+		zap.L().Error("Failed to get termbase by name", zap.String("name", name), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if tb.ID != selfID {
+		return TERMBASE_EXISTS
+	}
+
+	return NO_ERROR
+}
+
+// checkTermbaseOwner checks whether opID is an admin or the creator of the termbase.
+func (ts *termbaseSvc) checkTermbaseOwner(opID string, termbaseID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for termbase", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	tb, err := ts.repo.GetTermbaseByID(nil, termbaseID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get termbase for ownership check", zap.String("termbaseID", termbaseID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin && tb.CreatorID != opID {
+		zap.L().Warn("User attempted to modify termbase without ownership",
+			zap.String("userID", opID), zap.String("termbaseID", termbaseID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// checkTermEditor checks whether opID is an admin, translator or proofreader.
+func (ts *termbaseSvc) checkTermEditor(opID string) SvcErr {
+	op, err := ts.userRepo.GetUserByID(nil, opID)
+	if err != nil {
+		zap.L().Error("Failed to get operator info for term editing", zap.String("userID", opID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if !op.IsAdmin && op.AssignedTranslatorAt == nil && op.AssignedProofreaderAt == nil {
+		zap.L().Warn("User without translator or proofreader qualification attempted to edit terms", zap.String("userID", opID))
+		return PERMISSION_DENIED
+	}
+
+	return NO_ERROR
+}
+
+// checkTermInTermbase checks that the term exists and belongs to the termbase from the path.
+func (ts *termbaseSvc) checkTermInTermbase(termbaseID, termID string) SvcErr {
+	term, err := ts.termRepo.GetTermByID(nil, termID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get term by ID", zap.String("termID", termID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if term.TermbaseID != termbaseID {
+		zap.L().Warn("Term does not belong to termbase", zap.String("termID", termID), zap.String("termbaseID", termbaseID))
+		return NOT_FOUND
+	}
+
+	return NO_ERROR
+}
+
+// poTermbaseToModelTermbase converts po.BasicTermbase to model.TermbaseInfo
+func poTermbaseToModelTermbase(tb *po.BasicTermbase) model.TermbaseInfo {
+	return model.TermbaseInfo{
+		ID:              tb.ID,
+		Name:            tb.Name,
+		Description:     tb.Description,
+		CreatorID:       tb.CreatorID,
+		CreatorNickname: tb.CreatorNickname,
+		TermCount:       tb.TermCount,
+		CreatedAt:       tb.CreatedAt.Unix(),
+		UpdatedAt:       tb.UpdatedAt.Unix(),
+	}
+}
+
+// poTermToModelTerm converts po.BasicTerm to model.TermInfo
+func poTermToModelTerm(t *po.BasicTerm) model.TermInfo {
+	return model.TermInfo{
+		ID:              t.ID,
+		TermbaseID:      t.TermbaseID,
+		SourceText:      t.SourceText,
+		TargetText:      t.TargetText,
+		Note:            t.Note,
+		CreatorID:       t.CreatorID,
+		CreatorNickname: t.CreatorNickname,
+		CreatedAt:       t.CreatedAt.Unix(),
+		UpdatedAt:       t.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,124 @@
+package termbase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"poprako-main-server/internal/model/po"
+)
+
+// csvHeader is written on export and skipped on import if present.
+var csvHeader = []string{"source", "target", "note"}
+
+// ParseCSV parses a CSV file with columns source,target[,note].
+// A leading header row and blank rows are skipped.
+func ParseCSV(file io.Reader) ([]ParsedTerm, error) {
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+
+	var terms []ParsedTerm
+
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return nil, &ParseError{Pos: csvErr.Line, Reason: "CSV 格式错误：" + csvErr.Err.Error()}
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		pos, _ := r.FieldPos(0)
+
+		if first {
+			// Excel likes to prepend a BOM.
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+
+			if isCSVHeader(record) {
+				continue
+			}
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		if len(record) < 2 {
+			return nil, &ParseError{Pos: pos, Reason: fmt.Sprintf("需要原文与译文两列，只有 %d 列", len(record))}
+		}
+
+		term := ParsedTerm{
+			Pos:        pos,
+			SourceText: strings.TrimSpace(record[0]),
+			TargetText: strings.TrimSpace(record[1]),
+		}
+
+		if term.SourceText == "" || term.TargetText == "" {
+			return nil, &ParseError{Pos: pos, Reason: "原文与译文不能为空"}
+		}
+
+		if len(record) > 2 {
+			if note := strings.TrimSpace(record[2]); note != "" {
+				term.Note = &note
+			}
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+// WriteCSV writes terms as CSV with a source,target,note header.
+func WriteCSV(w io.Writer, terms []po.BasicTerm) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, t := range terms {
+		note := ""
+		if t.Note != nil {
+			note = *t.Note
+		}
+
+		if err := cw.Write([]string{t.SourceText, t.TargetText, note}); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func isCSVHeader(record []string) bool {
+	if len(record) < 2 {
+		return false
+	}
+
+	for i, field := range record {
+		if i >= len(csvHeader) || !strings.EqualFold(strings.TrimSpace(field), csvHeader[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,119 @@
+package termbase
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"poprako-main-server/internal/model/po"
+)
+
+func TestParseCSV(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		want []ParsedTerm
+	}{
+		{
+			name: "header with BOM",
+			file: "\ufeffSource, Target ,note\n魔法,magic,\n",
+			want: []ParsedTerm{{Pos: 2, SourceText: "魔法", TargetText: "magic"}},
+		},
+		{
+			name: "no header",
+			file: "魔法,magic\n勇者, hero , 主角 \n",
+			want: []ParsedTerm{
+				{Pos: 1, SourceText: "魔法", TargetText: "magic"},
+				{Pos: 2, SourceText: "勇者", TargetText: "hero", Note: strPtr("主角")},
+			},
+		},
+		{
+			name: "quoted fields",
+			file: "\"魔法, 法术\",\"\"\"magic\"\"\",\"a\nb\"\n勇者,hero\n",
+			want: []ParsedTerm{
+				{Pos: 1, SourceText: "魔法, 法术", TargetText: "\"magic\"", Note: strPtr("a\nb")},
+				{Pos: 3, SourceText: "勇者", TargetText: "hero"},
+			},
+		},
+		{
+			name: "blank rows",
+			file: "source,target\n\n , \n魔法,magic\n",
+			want: []ParsedTerm{{Pos: 4, SourceText: "魔法", TargetText: "magic"}},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := ParseCSV(strings.NewReader(c.file))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !slices.EqualFunc(got, c.want, equalParsed) {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseCSVRejects(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		pos  int
+	}{
+		{"one column", "魔法,magic\n勇者\n", 2},
+		{"empty target", "source,target\n魔法,magic\n勇者, \n", 3},
+		{"bare quote", "魔法,magic\n勇\"者,hero\n", 2},
+	}
+
+	for _, c := range cases {
+		_, err := ParseCSV(strings.NewReader(c.file))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%s: got %v, want a ParseError", c.name, err)
+		}
+		if parseErr.Pos != c.pos || parseErr.Reason == "" {
+			t.Errorf("%s: got %+v, want position %d", c.name, parseErr, c.pos)
+		}
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	terms := []po.BasicTerm{
+		{ID: "t-1", SourceText: "魔法, 法术", TargetText: "\"magic\"", Note: strPtr("a\nb")},
+		{ID: "t-2", SourceText: "勇者", TargetText: "hero"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, terms); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ParsedTerm{
+		{Pos: 2, SourceText: "魔法, 法术", TargetText: "\"magic\"", Note: strPtr("a\nb")},
+		{Pos: 4, SourceText: "勇者", TargetText: "hero"},
+	}
+	if !slices.EqualFunc(got, want, equalParsed) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func equalParsed(a, b ParsedTerm) bool {
+	if a.Note == nil || b.Note == nil {
+		if a.Note != b.Note {
+			return false
+		}
+	} else if *a.Note != *b.Note {
+		return false
+	}
+
+	return a.Pos == b.Pos && a.SourceText == b.SourceText && a.TargetText == b.TargetText
+}
+
+func strPtr(s string) *string { return &s }
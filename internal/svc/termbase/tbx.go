@@ -0,0 +1,170 @@
+package termbase
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"poprako-main-server/internal/model/po"
+)
+
+// Languages declared on export.
+// The tables do not record languages, and on import the first language
+// section of an entry is taken as source and the second as target.
+const (
+	TBX_SOURCE_LANG = "ja"
+	TBX_TARGET_LANG = "zh"
+)
+
+// tbxDoc accepts both TBX v3 (<tbx>/conceptEntry/langSec/termSec)
+// and TBX 2008 (<martif>/termEntry/langSet/tig) documents.
+type tbxDoc struct {
+	ConceptEntries []tbxEntry `xml:"text>body>conceptEntry"`
+	TermEntries    []tbxEntry `xml:"text>body>termEntry"`
+}
+
+type tbxEntry struct {
+	Notes    []string     `xml:"note"`
+	LangSecs []tbxLangSec `xml:"langSec"`
+	LangSets []tbxLangSec `xml:"langSet"`
+}
+
+type tbxLangSec struct {
+	TermSecs []string `xml:"termSec>term"`
+	Tigs     []string `xml:"tig>term"`
+	Ntigs    []string `xml:"ntig>termGrp>term"`
+	Notes    []string `xml:"note"`
+}
+
+// firstTerm returns the first non-empty term of a language section.
+func (ls *tbxLangSec) firstTerm() string {
+	for _, group := range [][]string{ls.TermSecs, ls.Tigs, ls.Ntigs} {
+		for _, t := range group {
+			if t = strings.TrimSpace(t); t != "" {
+				return t
+			}
+		}
+	}
+
+	return ""
+}
+
+// ParseTBX parses a TBX-Basic document.
+// Entries without both a source and a target term are rejected.
+func ParseTBX(file io.Reader) ([]ParsedTerm, error) {
+	var doc tbxDoc
+
+	if err := xml.NewDecoder(file).Decode(&doc); err != nil {
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, &ParseError{Reason: "TBX 格式错误：" + err.Error()}
+		}
+		return nil, fmt.Errorf("failed to decode TBX: %w", err)
+	}
+
+	entries := append(doc.ConceptEntries, doc.TermEntries...)
+
+	terms := make([]ParsedTerm, 0, len(entries))
+	for i, e := range entries {
+		pos := i + 1
+
+		langSecs := append(e.LangSecs, e.LangSets...)
+		if len(langSecs) < 2 {
+			return nil, &ParseError{Pos: pos, Reason: fmt.Sprintf("需要原文与译文两个语言节，只有 %d 个", len(langSecs))}
+		}
+
+		term := ParsedTerm{
+			Pos:        pos,
+			SourceText: langSecs[0].firstTerm(),
+			TargetText: langSecs[1].firstTerm(),
+		}
+
+		if term.SourceText == "" || term.TargetText == "" {
+			return nil, &ParseError{Pos: pos, Reason: "原文与译文不能为空"}
+		}
+
+		notes := e.Notes
+		for _, ls := range langSecs {
+			notes = append(notes, ls.Notes...)
+		}
+		if note := joinNotes(notes); note != "" {
+			term.Note = &note
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms, nil
+}
+
+type tbxOutDoc struct {
+	XMLName xml.Name      `xml:"tbx"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Type    string        `xml:"type,attr"`
+	Style   string        `xml:"style,attr"`
+	Lang    string        `xml:"xml:lang,attr"`
+	Title   string        `xml:"tbxHeader>fileDesc>titleStmt>title"`
+	Entries []tbxOutEntry `xml:"text>body>conceptEntry"`
+}
+
+type tbxOutEntry struct {
+	ID       string          `xml:"id,attr"`
+	Note     *string         `xml:"note,omitempty"`
+	LangSecs []tbxOutLangSec `xml:"langSec"`
+}
+
+type tbxOutLangSec struct {
+	Lang string `xml:"xml:lang,attr"`
+	Term string `xml:"termSec>term"`
+}
+
+// WriteTBX writes terms as a TBX-Basic (TBX v3) document titled by name.
+func WriteTBX(w io.Writer, name string, terms []po.BasicTerm) error {
+	doc := tbxOutDoc{
+		Xmlns:   "urn:iso:std:iso:30042:ed-2",
+		Type:    "TBX-Basic",
+		Style:   "dca",
+		Lang:    TBX_SOURCE_LANG,
+		Title:   name,
+		Entries: make([]tbxOutEntry, 0, len(terms)),
+	}
+
+	for _, t := range terms {
+		doc.Entries = append(doc.Entries, tbxOutEntry{
+			// XML IDs must not start with a digit,
+			// which UUIDs may do.
+			ID:   "t-" + t.ID,
+			Note: t.Note,
+			LangSecs: []tbxOutLangSec{
+				{Lang: TBX_SOURCE_LANG, Term: t.SourceText},
+				{Lang: TBX_TARGET_LANG, Term: t.TargetText},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write TBX header: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode TBX: %w", err)
+	}
+
+	return enc.Close()
+}
+
+func joinNotes(notes []string) string {
+	var parts []string
+	for _, n := range notes {
+		if n = strings.TrimSpace(n); n != "" {
+			parts = append(parts, n)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
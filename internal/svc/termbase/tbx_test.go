@@ -0,0 +1,129 @@
+package termbase
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"poprako-main-server/internal/model/po"
+)
+
+const tbxV3 = `<?xml version="1.0" encoding="UTF-8"?>
+<tbx type="TBX-Basic" style="dca" xml:lang="ja" xmlns="urn:iso:std:iso:30042:ed-2">
+  <text><body>
+    <conceptEntry id="c1">
+      <note>主角</note>
+      <langSec xml:lang="ja"><termSec><term> 勇者 </term></termSec><note>称号</note></langSec>
+      <langSec xml:lang="zh"><termSec><term></term></termSec><termSec><term>勇者</term></termSec></langSec>
+    </conceptEntry>
+    <conceptEntry id="c2">
+      <langSec xml:lang="ja"><termSec><term>魔法</term></termSec></langSec>
+      <langSec xml:lang="zh"><termSec><term>魔法</term></termSec></langSec>
+      <langSec xml:lang="en"><termSec><term>magic</term></termSec></langSec>
+    </conceptEntry>
+  </body></text>
+</tbx>`
+
+const tbx2008 = `<?xml version="1.0" encoding="UTF-8"?>
+<martif type="TBX-Basic" xml:lang="ja">
+  <text><body>
+    <termEntry id="e1">
+      <langSet xml:lang="ja"><tig><term>剣</term></tig></langSet>
+      <langSet xml:lang="zh"><ntig><termGrp><term>剑</term></termGrp></ntig></langSet>
+    </termEntry>
+  </body></text>
+</martif>`
+
+func TestParseTBX(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		want []ParsedTerm
+	}{
+		{
+			name: "v3",
+			file: tbxV3,
+			want: []ParsedTerm{
+				{Pos: 1, SourceText: "勇者", TargetText: "勇者", Note: strPtr("主角\n称号")},
+				{Pos: 2, SourceText: "魔法", TargetText: "魔法"},
+			},
+		},
+		{
+			name: "2008",
+			file: tbx2008,
+			want: []ParsedTerm{{Pos: 1, SourceText: "剣", TargetText: "剑"}},
+		},
+	}
+
+	for _, c := range cases {
+		got, err := ParseTBX(strings.NewReader(c.file))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if !slices.EqualFunc(got, c.want, equalParsed) {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseTBXRejects(t *testing.T) {
+	entry := func(langSecs string) string {
+		return `<tbx><text><body><conceptEntry>` +
+			`<langSec><termSec><term>魔法</term></termSec></langSec><langSec><termSec><term>magic</term></termSec></langSec>` +
+			`</conceptEntry><conceptEntry>` + langSecs + `</conceptEntry></body></text></tbx>`
+	}
+
+	cases := []struct {
+		name string
+		file string
+		pos  int
+	}{
+		{"one language", entry(`<langSec><termSec><term>勇者</term></termSec></langSec>`), 2},
+		{"empty target", entry(`<langSec><termSec><term>勇者</term></termSec></langSec><langSec><termSec><term> </term></termSec></langSec>`), 2},
+		{"malformed", `<tbx><text><body><conceptEntry>`, 0},
+		{"empty", ``, 0},
+	}
+
+	for _, c := range cases {
+		_, err := ParseTBX(strings.NewReader(c.file))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%s: got %v, want a ParseError", c.name, err)
+		}
+		if parseErr.Pos != c.pos || parseErr.Reason == "" {
+			t.Errorf("%s: got %+v, want position %d", c.name, parseErr, c.pos)
+		}
+	}
+}
+
+func TestWriteTBXRoundTrip(t *testing.T) {
+	terms := []po.BasicTerm{
+		// IDs starting with a digit are not valid XML IDs as is.
+		{ID: "019a0000-0000-7000-8000-000000000001", SourceText: "勇者 & <魔王>", TargetText: "\"勇者\"", Note: strPtr("a\nb")},
+		{ID: "t-2", SourceText: "魔法", TargetText: "magic"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTBX(&buf, "术语 & 表", terms); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `id="t-019a0000-0000-7000-8000-000000000001"`) {
+		t.Fatalf("unexpected entry IDs in\n%s", buf.String())
+	}
+
+	got, err := ParseTBX(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ParsedTerm{
+		{Pos: 1, SourceText: "勇者 & <魔王>", TargetText: "\"勇者\"", Note: strPtr("a\nb")},
+		{Pos: 2, SourceText: "魔法", TargetText: "magic"},
+	}
+	if !slices.EqualFunc(got, want, equalParsed) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package termbase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Supported file formats.
+const (
+	FORMAT_CSV = "csv"
+	FORMAT_TBX = "tbx"
+)
+
+// ParsedTerm represents a term parsed from an imported file.
+type ParsedTerm struct {
+	// 1-based position of the entry in the file,
+	// a line number for CSV and an entry number for TBX.
+	Pos        int
+	SourceText string
+	TargetText string
+	Note       *string
+}
+
+// ParseError reports why an imported file was rejected, for clients to show.
+type ParseError struct {
+	// Position of the offending entry as in ParsedTerm, 0 if the file is malformed as a whole.
+	Pos    int
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	if e.Pos == 0 {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("entry %d: %s", e.Pos, e.Reason)
+}
+
+// FormatByFileName detects the file format by extension.
+// An empty string is returned for unsupported extensions.
+func FormatByFileName(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FORMAT_CSV
+	case ".tbx", ".xml":
+		return FORMAT_TBX
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,79 @@
+package svc
+
+import (
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeNamedTermbaseRepo struct {
+	repo.TermbaseRepo
+
+	termbases []po.BasicTermbase
+	created   int
+	updated   int
+}
+
+func (r *fakeNamedTermbaseRepo) GetTermbaseByID(_ repo.Exct, termbaseID string) (*po.BasicTermbase, error) {
+	for _, tb := range r.termbases {
+		if tb.ID == termbaseID {
+			return &tb, nil
+		}
+	}
+	return nil, repo.REC_NOT_FOUND
+}
+
+func (r *fakeNamedTermbaseRepo) GetTermbaseByName(_ repo.Exct, name string) (*po.BasicTermbase, error) {
+	for _, tb := range r.termbases {
+		if tb.Name == name {
+			return &tb, nil
+		}
+	}
+	return nil, repo.REC_NOT_FOUND
+}
+
+func (r *fakeNamedTermbaseRepo) CreateTermbase(repo.Exct, *po.NewTermbase) error {
+	r.created++
+	return nil
+}
+
+func (r *fakeNamedTermbaseRepo) UpdateTermbaseByID(repo.Exct, *po.PatchTermbase) error {
+	r.updated++
+	return nil
+}
+
+type fakeAdminUserRepo struct{ repo.UserRepo }
+
+func (fakeAdminUserRepo) GetUserByID(_ repo.Exct, userID string) (*po.BasicUser, error) {
+	return &po.BasicUser{ID: userID, IsAdmin: true}, nil
+}
+
+func TestTermbaseNamesTaken(t *testing.T) {
+	r := &fakeNamedTermbaseRepo{termbases: []po.BasicTermbase{
+		{ID: "tb-1", Name: "Names"},
+		{ID: "tb-2", Name: "Places"},
+	}}
+	ts := NewTermbaseSvc(r, struct{ repo.TermRepo }{}, fakeAdminUserRepo{}, struct{ repo.ComicRepo }{})
+
+	if _, svcErr := ts.CreateTermbase("u-1", model.CreateTermbaseArgs{Name: " Names "}); svcErr != TERMBASE_EXISTS || svcErr.Code() != 409 {
+		t.Fatalf("create taken: got %v", svcErr)
+	}
+	if _, svcErr := ts.CreateTermbase("u-1", model.CreateTermbaseArgs{Name: "Spells"}); svcErr != NO_ERROR {
+		t.Fatalf("create free: got %v", svcErr)
+	}
+
+	taken, own := "Places", "Names"
+	if svcErr := ts.UpdateTermbaseByID("u-1", model.UpdateTermbaseArgs{ID: "tb-1", Name: &taken}); svcErr != TERMBASE_EXISTS {
+		t.Fatalf("rename to taken: got %v", svcErr)
+	}
+	// Keeping its own name is no conflict.
+	if svcErr := ts.UpdateTermbaseByID("u-1", model.UpdateTermbaseArgs{ID: "tb-1", Name: &own}); svcErr != NO_ERROR {
+		t.Fatalf("rename to own: got %v", svcErr)
+	}
+
+	if r.created != 1 || r.updated != 1 {
+		t.Fatalf("got %d creations, %d updates", r.created, r.updated)
+	}
+}
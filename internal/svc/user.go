@@ -1,7 +1,6 @@
 package svc
 
 import (
-	"sync"
 	"time"
 
 	"poprako-main-server/internal/jwtcodec"
@@ -20,7 +19,7 @@ type UserSvc interface {
 	LoginUser(args model.LoginArgs) (SvcRslt[model.LoginReply], SvcErr)
 
 	UpdateUserInfo(args model.UpdateUserArgs) SvcErr
-	AssignUserRole(opID string, args model.AssignUserRoleArgs) SvcErr
+	AssignUserRole(opID string, meta model.ReqMeta, args model.AssignUserRoleArgs) SvcErr
 
 	GetUserInfos(opt model.RetrieveUserOpt) (SvcRslt[[]model.UserInfo], SvcErr)
 }
@@ -28,17 +27,23 @@ type UserSvc interface {
 type userSvc struct {
 	repo    repo.UserRepo
 	invRepo repo.InvitationRepo
-	jwt     *jwtcodec.Codec
+	tagRepo repo.TagRepo
 
-	mu       sync.Mutex
-	invCodes map[string]struct{}
+	sessions *sessionIssuer
+	audit    *auditRecorder
+	notifier *notifier
 }
 
 // NewUserSvc creates a new UserSvc. If r is nil, the default repo implementation is used.
 func NewUserSvc(
 	r repo.UserRepo,
 	ir repo.InvitationRepo,
+	tr repo.TagRepo,
+	sr repo.SessionRepo,
+	alr repo.AuditLogRepo,
+	nr repo.NotificationRepo,
 	jwt *jwtcodec.Codec,
+	refreshExpSecs int64,
 ) UserSvc {
 	if r == nil {
 		panic("UserRepo cannot be nil")
@@ -46,15 +51,20 @@ func NewUserSvc(
 	if ir == nil {
 		panic("InvitationRepo cannot be nil")
 	}
-	if jwt == nil {
-		panic("JWT Codec cannot be nil")
+	if tr == nil {
+		panic("TagRepo cannot be nil")
+	}
+	if sr == nil {
+		panic("SessionRepo cannot be nil")
 	}
 
 	return &userSvc{
 		repo:     r,
 		invRepo:  ir,
-		jwt:      jwt,
-		invCodes: make(map[string]struct{}),
+		tagRepo:  tr,
+		sessions: newSessionIssuer(sr, jwt, refreshExpSecs),
+		audit:    newAuditRecorder(alr),
+		notifier: newNotifier(nr),
 	}
 }
 
@@ -98,6 +108,13 @@ func (us *userSvc) GetUserInfoByID(userID string) (SvcRslt[model.UserInfo], SvcE
 		userInfo.AssignedUploaderAt = &ts
 	}
 
+	tags, err := us.tagRepo.GetTagsByUserIDs(nil, []string{userID})
+	if err != nil {
+		zap.L().Error("Failed to get tags by user ID", zap.String("userID", userID), zap.Error(err))
+		return SvcRslt[model.UserInfo]{}, DB_FAILURE
+	}
+	userInfo.Tags = poTagsToModelTags(tags[userID])
+
 	return accept(200, userInfo), NO_ERROR
 }
 
@@ -151,6 +168,17 @@ func (us *userSvc) GetUserInfos(opt model.RetrieveUserOpt) (SvcRslt[[]model.User
 		return SvcRslt[[]model.UserInfo]{}, DB_FAILURE
 	}
 
+	userIDs := make([]string, 0, len(userBasics))
+	for _, ub := range userBasics {
+		userIDs = append(userIDs, ub.ID)
+	}
+
+	tagsByUser, err := us.tagRepo.GetTagsByUserIDs(nil, userIDs)
+	if err != nil {
+		zap.L().Error("Failed to get tags by user IDs", zap.Error(err))
+		return SvcRslt[[]model.UserInfo]{}, DB_FAILURE
+	}
+
 	userInfos := make([]model.UserInfo, 0, len(userBasics))
 
 	for _, ub := range userBasics {
@@ -186,6 +214,8 @@ func (us *userSvc) GetUserInfos(opt model.RetrieveUserOpt) (SvcRslt[[]model.User
 			ui.AssignedUploaderAt = &ts
 		}
 
+		ui.Tags = poTagsToModelTags(tagsByUser[ub.ID])
+
 		userInfos = append(userInfos, ui)
 	}
 
@@ -194,6 +224,7 @@ func (us *userSvc) GetUserInfos(opt model.RetrieveUserOpt) (SvcRslt[[]model.User
 
 func (us *userSvc) AssignUserRole(
 	opID string,
+	meta model.ReqMeta,
 	args model.AssignUserRoleArgs,
 ) SvcErr {
 	var patch po.PatchUser
@@ -274,7 +305,33 @@ func (us *userSvc) AssignUserRole(
 
 	patch.ID = args.ID
 
-	if err := us.repo.UpdateUserByID(nil, &patch); err != nil {
+	before, err := us.repo.GetUserByID(nil, args.ID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return USER_NOT_FOUND
+		}
+		zap.L().Error("Failed to get user in AssignUserRole", zap.String("userID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := us.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := us.repo.UpdateUserByID(tx, &patch); err != nil {
+			return err
+		}
+
+		after, err := us.repo.GetUserByID(tx, args.ID)
+		if err != nil {
+			return err
+		}
+
+		return us.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_ASSIGN_ROLE,
+			EntityType: AUDIT_ENTITY_USER,
+			EntityID:   args.ID,
+			Before:     before,
+			After:      after,
+		})
+	}); err != nil {
 		zap.L().Error("Failed to update user roles in AssignUserRole", zap.String("userID", args.ID), zap.Error(err))
 		return DB_FAILURE
 	}
@@ -288,7 +345,7 @@ func (us *userSvc) registerUser(args model.LoginArgs) (SvcRslt[model.LoginReply]
 
 	invitation, err := us.verifyInvCode(args.InvCode, args.QQ)
 	if err != nil || invitation == nil {
-		zap.L().Warn("Invalid invitation code during user login", zap.String("invCode", args.InvCode), zap.Error(err))
+		zap.L().Warn("Invalid invitation code during user login", zap.String("qq", args.QQ), zap.Error(err))
 		return SvcRslt[model.LoginReply]{}, INV_CODE_INVALID
 	}
 
@@ -339,28 +396,46 @@ func (us *userSvc) registerUser(args model.LoginArgs) (SvcRslt[model.LoginReply]
 		newUser.AssignedUploaderAt = &now
 	}
 
+	// The invitation is consumed in the same transaction,
+	// so that one code cannot create two accounts.
 	// The insertion may fail due to UNIQUE qq constraint violation.
 	// This is expected and acceptable in concurrent login scenarios.
-	if err := us.repo.CreateUser(nil, newUser); err != nil {
+	if err := us.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := us.repo.CreateUser(tx, newUser); err != nil {
+			return err
+		}
+
+		if err := us.invRepo.ConsumeInvitation(tx, invitation.ID, newUser.ID); err != nil {
+			return err
+		}
+
+		return us.notifier.notify(tx, []string{invitation.InvitorID}, notification{
+			Kind:    NOTIFICATION_INVITATION_USED,
+			ActorID: newUser.ID,
+			Detail: invitationUsedNotification{
+				InvitationID: invitation.ID,
+				InviteeID:    newUser.ID,
+				InviteeQQ:    newUser.QQ,
+			},
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			zap.L().Warn("Invitation consumed concurrently during user login", zap.String("invitationID", invitation.ID))
+			return SvcRslt[model.LoginReply]{}, INV_CODE_INVALID
+		}
 		zap.L().Error("Failed to create new user during login", zap.String("qq", args.QQ), zap.Error(err))
 		return SvcRslt[model.LoginReply]{}, DB_FAILURE
 	}
 
-	// Mark the invitation as used (set pending to false)
-	if err := us.invRepo.MarkInvitationAsUsed(nil, invitation.ID); err != nil {
-		zap.L().Error("Failed to mark invitation as used after user creation", zap.String("invitationID", invitation.ID), zap.Error(err))
-		// Continue anyway, as the user was created successfully
-	}
-
-	// Creation succeeded, generate JWT token for the new user.
+	// Creation succeeded, start a session for the new user.
 	// ID is automatically populated after creation.
-	token, err := us.genJWT(newUser.ID)
+	reply, err := us.sessions.create(newUser.ID, args.Device, args.IP)
 	if err != nil {
-		zap.L().Error("Failed to generate JWT for new user during login", zap.String("qq", args.QQ), zap.Error(err))
+		zap.L().Error("Failed to create session for new user during login", zap.String("qq", args.QQ), zap.Error(err))
 		return SvcRslt[model.LoginReply]{}, DB_FAILURE
 	}
 
-	return accept(200, model.LoginReply{Token: token}), NO_ERROR
+	return accept(200, reply), NO_ERROR
 }
 
 func (us *userSvc) loginUser(args model.LoginArgs) (SvcRslt[model.LoginReply], SvcErr) {
@@ -377,12 +452,12 @@ func (us *userSvc) loginUser(args model.LoginArgs) (SvcRslt[model.LoginReply], S
 		return SvcRslt[model.LoginReply]{}, PWD_MISMATCH
 	}
 
-	// Verification succeeded, generate JWT token for the existing user.
-	token, err := us.genJWT(secret.ID)
+	// Verification succeeded, start a session for the existing user.
+	reply, err := us.sessions.create(secret.ID, args.Device, args.IP)
 	if err != nil {
-		zap.L().Error("Failed to generate JWT for existing user during login", zap.String("qq", args.QQ), zap.Error(err))
+		zap.L().Error("Failed to create session for existing user during login", zap.String("qq", args.QQ), zap.Error(err))
 		return SvcRslt[model.LoginReply]{}, DB_FAILURE
 	}
 
-	return accept(200, model.LoginReply{Token: token}), NO_ERROR
+	return accept(200, reply), NO_ERROR
 }
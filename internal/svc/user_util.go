@@ -1,9 +1,9 @@
 package svc
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
-	"strconv"
 
 	"poprako-main-server/internal/model"
 	"poprako-main-server/internal/repo"
@@ -40,46 +40,8 @@ func (us *userSvc) verifyPwd(hashedPwd, plainPwd string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPwd), []byte(plainPwd)) == nil
 }
 
-// Generate a JWT token for a given user ID.
-func (us *userSvc) genJWT(userID string) (string, error) {
-	if us.jwt == nil {
-		zap.L().Warn("us.jwt not set in generateJWT")
-		return "", fmt.Errorf("jwt codec is not configured on userSvc")
-	}
-
-	token, err := us.jwt.Encode(userID)
-	if err != nil {
-		return "", fmt.Errorf("Failed to generate JWT: %w", err)
-	}
-
-	return token, nil
-}
-
-func (us *userSvc) genInvCode(decStr string) string {
-	// Encode invitation code: from dec string to hex string.
-	num, err := strconv.ParseInt(decStr, 10, 32)
-	if err != nil {
-		zap.L().Error("Failed to parse invitation code number", zap.String("decStr", decStr), zap.Error(err))
-		return ""
-	}
-
-	hexStr := strconv.FormatInt(num, 16)
-
-	us.mu.Lock()
-	defer us.mu.Unlock()
-
-	if len(us.invCodes) >= 50 {
-		// Limit the number of stored invitation codes up to 50.
-		zap.L().Warn("Failed add any more invitation code due to capacity issues")
-		return ""
-	}
-
-	us.invCodes[hexStr] = struct{}{}
-
-	return hexStr
-}
-
-// Check whether a invitation code is valid.
+// Check whether a invitation code is valid,
+// that is it matches the usable invitation for the QQ.
 func (us *userSvc) verifyInvCode(code string, qq string) (*model.InvitationInfo, error) {
 	invitation, err := us.invRepo.GetInvitationByQQ(nil, qq)
 	if err == repo.REC_NOT_FOUND {
@@ -90,7 +52,7 @@ func (us *userSvc) verifyInvCode(code string, qq string) (*model.InvitationInfo,
 		return nil, err
 	}
 
-	if invitation.InvCode != code {
+	if subtle.ConstantTimeCompare([]byte(hashInvCode(code)), []byte(invitation.InvCodeHash)) != 1 {
 		return nil, errors.New("invitation code does not match")
 	}
 
@@ -0,0 +1,680 @@
+package svc
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// Events posted to webhooks.
+const (
+	WEBHOOK_EVENT_COMIC_CREATE = "comic.created"
+	WEBHOOK_EVENT_COMIC_STAGE  = "comic.stage_changed"
+	WEBHOOK_EVENT_COMIC_DELETE = "comic.deleted"
+	WEBHOOK_EVENT_PAGE_UPLOAD  = "page.uploaded"
+	WEBHOOK_EVENT_ASGN_CREATE  = "assignment.created"
+	WEBHOOK_EVENT_ASGN_DELETE  = "assignment.deleted"
+)
+
+var webhookEvents = []string{
+	WEBHOOK_EVENT_COMIC_CREATE,
+	WEBHOOK_EVENT_COMIC_STAGE,
+	WEBHOOK_EVENT_COMIC_DELETE,
+	WEBHOOK_EVENT_PAGE_UPLOAD,
+	WEBHOOK_EVENT_ASGN_CREATE,
+	WEBHOOK_EVENT_ASGN_DELETE,
+}
+
+// Statuses of webhook deliveries.
+const (
+	WEBHOOK_DELIVERY_PENDING   = "pending"
+	WEBHOOK_DELIVERY_SUCCEEDED = "succeeded"
+	WEBHOOK_DELIVERY_FAILED    = "failed"
+)
+
+// Headers of webhook requests.
+const (
+	WEBHOOK_HEADER_EVENT     = "X-Poprako-Event"
+	WEBHOOK_HEADER_DELIVERY  = "X-Poprako-Delivery"
+	WEBHOOK_HEADER_TIMESTAMP = "X-Poprako-Timestamp"
+	WEBHOOK_HEADER_SIGNATURE = "X-Poprako-Signature"
+)
+
+const (
+	defaultWebhookLimit         = 20
+	maxWebhookLimit             = 100
+	defaultWebhookDeliveryLimit = 20
+	maxWebhookDeliveryLimit     = 100
+
+	minWebhookSecretLen = 16
+
+	// Used if no timeout is configured.
+	defaultWebhookTimeout = 10 * time.Second
+
+	// Deliveries attempted at once by one dispatch.
+	webhookDispatchBatch = 20
+	// Delay after the first failed attempt, doubled after each further one.
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+	// Kept of the error of the last attempt.
+	maxWebhookErrorLen = 500
+)
+
+// WebhookSvc defines service operations for webhooks.
+//
+// Changes are queued as deliveries to the webhooks subscribed to their event,
+// in the transaction of the change, and posted by DispatchDueDeliveries,
+// which retries failed attempts with exponential backoff.
+type WebhookSvc interface {
+	GetWebhookByID(webhookID string) (SvcRslt[model.WebhookInfo], SvcErr)
+	RetrieveWebhooks(opt model.RetrieveWebhookOpt) (SvcRslt[[]model.WebhookInfo], SvcErr)
+
+	CreateWebhook(opID string, meta model.ReqMeta, args model.CreateWebhookArgs) (SvcRslt[model.CreateWebhookReply], SvcErr)
+
+	UpdateWebhookByID(opID string, meta model.ReqMeta, args model.UpdateWebhookArgs) SvcErr
+
+	DeleteWebhookByID(opID string, meta model.ReqMeta, webhookID string) SvcErr
+
+	RetrieveDeliveries(webhookID string, opt model.RetrieveWebhookDeliveryOpt) (SvcRslt[[]model.WebhookDeliveryInfo], SvcErr)
+
+	// DispatchDueDeliveries posts the deliveries due, returning how many were attempted.
+	DispatchDueDeliveries() int
+}
+
+type webhookSvc struct {
+	repo   repo.WebhookRepo
+	audit  *auditRecorder
+	client *http.Client
+
+	// Attempts after which a delivery is given up.
+	maxAttempts int
+}
+
+// NewWebhookSvc creates a new WebhookSvc. r and alr must not be nil.
+// Each attempt times out after timeout, and a delivery fails after maxAttempts.
+func NewWebhookSvc(r repo.WebhookRepo, alr repo.AuditLogRepo, timeout time.Duration, maxAttempts int) WebhookSvc {
+	if r == nil {
+		panic("WebhookRepo cannot be nil")
+	}
+
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	return &webhookSvc{
+		repo:        r,
+		audit:       newAuditRecorder(alr),
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: max(maxAttempts, 1),
+	}
+}
+
+func (ws *webhookSvc) GetWebhookByID(webhookID string) (SvcRslt[model.WebhookInfo], SvcErr) {
+	webhook, svcErr := ws.getWebhook(webhookID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.WebhookInfo]{}, svcErr
+	}
+
+	return accept(200, toWebhookInfo(webhook)), NO_ERROR
+}
+
+func (ws *webhookSvc) RetrieveWebhooks(opt model.RetrieveWebhookOpt) (SvcRslt[[]model.WebhookInfo], SvcErr) {
+	if opt.Limit <= 0 {
+		opt.Limit = defaultWebhookLimit
+	}
+	opt.Limit = min(opt.Limit, maxWebhookLimit)
+
+	webhooks, err := ws.repo.RetrieveWebhooks(nil, opt.Offset, opt.Limit)
+	if err != nil {
+		zap.L().Error("Failed to retrieve webhooks", zap.Error(err))
+		return SvcRslt[[]model.WebhookInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.WebhookInfo, 0, len(webhooks))
+	for i := range webhooks {
+		infos = append(infos, toWebhookInfo(&webhooks[i]))
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+func (ws *webhookSvc) CreateWebhook(
+	opID string,
+	meta model.ReqMeta,
+	args model.CreateWebhookArgs,
+) (SvcRslt[model.CreateWebhookReply], SvcErr) {
+	webhookURL, svcErr := normalizeWebhookURL(args.URL)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.CreateWebhookReply]{}, svcErr
+	}
+
+	events, svcErr := normalizeWebhookEvents(args.Events)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.CreateWebhookReply]{}, svcErr
+	}
+
+	var secret string
+	if args.Secret != nil {
+		if secret, svcErr = normalizeWebhookSecret(*args.Secret); svcErr != NO_ERROR {
+			return SvcRslt[model.CreateWebhookReply]{}, svcErr
+		}
+	} else {
+		var err error
+		if secret, err = genWebhookSecret(); err != nil {
+			zap.L().Error("Failed to generate webhook secret", zap.Error(err))
+			return SvcRslt[model.CreateWebhookReply]{}, ID_GEN_FAILURE
+		}
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		zap.L().Error("Failed to generate UUID for webhook", zap.Error(err))
+		return SvcRslt[model.CreateWebhookReply]{}, ID_GEN_FAILURE
+	}
+
+	newWebhook := &po.NewWebhook{
+		ID:          id,
+		URL:         webhookURL,
+		Secret:      secret,
+		Events:      events,
+		Description: args.Description,
+		Active:      true,
+		CreatorID:   opID,
+	}
+
+	if err := ws.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ws.repo.CreateWebhook(tx, newWebhook); err != nil {
+			return err
+		}
+
+		after, err := ws.repo.GetWebhookByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		// The secret is kept out of the audit log.
+		return ws.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_CREATE,
+			EntityType: AUDIT_ENTITY_WEBHOOK,
+			EntityID:   id,
+			After:      toWebhookInfo(after),
+		})
+	}); err != nil {
+		zap.L().Error("Failed to create webhook", zap.String("url", webhookURL), zap.Error(err))
+		return SvcRslt[model.CreateWebhookReply]{}, DB_FAILURE
+	}
+
+	return accept(201, model.CreateWebhookReply{ID: id, Secret: secret}), NO_ERROR
+}
+
+func (ws *webhookSvc) UpdateWebhookByID(opID string, meta model.ReqMeta, args model.UpdateWebhookArgs) SvcErr {
+	patch := &po.PatchWebhook{
+		ID:          args.ID,
+		Description: args.Description,
+		Active:      args.Active,
+	}
+
+	if args.URL != nil {
+		webhookURL, svcErr := normalizeWebhookURL(*args.URL)
+		if svcErr != NO_ERROR {
+			return svcErr
+		}
+		patch.URL = &webhookURL
+	}
+
+	if args.Secret != nil {
+		secret, svcErr := normalizeWebhookSecret(*args.Secret)
+		if svcErr != NO_ERROR {
+			return svcErr
+		}
+		patch.Secret = &secret
+	}
+
+	if args.Events != nil {
+		events, svcErr := normalizeWebhookEvents(*args.Events)
+		if svcErr != NO_ERROR {
+			return svcErr
+		}
+		patch.Events = events
+	}
+
+	before, svcErr := ws.getWebhook(args.ID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ws.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ws.repo.UpdateWebhookByID(tx, patch); err != nil {
+			return err
+		}
+
+		after, err := ws.repo.GetWebhookByID(tx, args.ID)
+		if err != nil {
+			return err
+		}
+
+		return ws.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_UPDATE,
+			EntityType: AUDIT_ENTITY_WEBHOOK,
+			EntityID:   args.ID,
+			Before:     toWebhookInfo(before),
+			After:      toWebhookInfo(after),
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to update webhook", zap.String("webhookID", args.ID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+func (ws *webhookSvc) DeleteWebhookByID(opID string, meta model.ReqMeta, webhookID string) SvcErr {
+	before, svcErr := ws.getWebhook(webhookID)
+	if svcErr != NO_ERROR {
+		return svcErr
+	}
+
+	if err := ws.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ws.repo.DeleteWebhookByID(tx, webhookID); err != nil {
+			return err
+		}
+
+		return ws.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_WEBHOOK,
+			EntityID:   webhookID,
+			Before:     toWebhookInfo(before),
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to delete webhook", zap.String("webhookID", webhookID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	return NO_ERROR
+}
+
+// RetrieveDeliveries lists the deliveries of a webhook matching opt, newest first.
+func (ws *webhookSvc) RetrieveDeliveries(
+	webhookID string,
+	opt model.RetrieveWebhookDeliveryOpt,
+) (SvcRslt[[]model.WebhookDeliveryInfo], SvcErr) {
+	if _, svcErr := ws.getWebhook(webhookID); svcErr != NO_ERROR {
+		return SvcRslt[[]model.WebhookDeliveryInfo]{}, svcErr
+	}
+
+	if opt.Limit <= 0 {
+		opt.Limit = defaultWebhookDeliveryLimit
+	}
+	opt.Limit = min(opt.Limit, maxWebhookDeliveryLimit)
+
+	deliveries, err := ws.repo.RetrieveDeliveries(nil, webhookID, opt)
+	if err != nil {
+		zap.L().Error("Failed to retrieve webhook deliveries", zap.String("webhookID", webhookID), zap.Error(err))
+		return SvcRslt[[]model.WebhookDeliveryInfo]{}, DB_FAILURE
+	}
+
+	infos := make([]model.WebhookDeliveryInfo, 0, len(deliveries))
+	for _, d := range deliveries {
+		info := model.WebhookDeliveryInfo{
+			ID:             d.ID,
+			WebhookID:      d.WebhookID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			DeliveredAt:    timePtrToInt64Ptr(d.DeliveredAt),
+			CreatedAt:      d.CreatedAt.Unix(),
+		}
+		if d.Status == WEBHOOK_DELIVERY_PENDING {
+			info.NextAttemptAt = timePtrToInt64Ptr(&d.NextAttemptAt)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return accept(200, infos), NO_ERROR
+}
+
+// DispatchDueDeliveries claims the deliveries due in batches and posts those of a batch concurrently.
+// Failures are logged and recorded on the deliveries, to be retried on later dispatches.
+func (ws *webhookSvc) DispatchDueDeliveries() int {
+	attempted := 0
+
+	for {
+		now := time.Now()
+
+		// Outlast the attempts, so that a delivery is retried only if its attempt was never recorded.
+		leaseUntil := now.Add(ws.client.Timeout + time.Minute)
+
+		due, err := ws.repo.ClaimDueDeliveries(nil, now, leaseUntil, webhookDispatchBatch)
+		if err != nil {
+			zap.L().Error("Failed to claim due webhook deliveries", zap.Error(err))
+			return attempted
+		}
+
+		var wg sync.WaitGroup
+		for i := range due {
+			wg.Add(1)
+			go func(d *po.DueWebhookDelivery) {
+				defer wg.Done()
+				ws.attempt(d)
+			}(&due[i])
+		}
+		wg.Wait()
+
+		attempted += len(due)
+
+		if len(due) < webhookDispatchBatch {
+			return attempted
+		}
+	}
+}
+
+// attempt posts a delivery and records the result,
+// scheduling a retry on failure unless it has been attempted maxAttempts times.
+func (ws *webhookSvc) attempt(d *po.DueWebhookDelivery) {
+	statusCode, err := postWebhook(ws.client, d.URL, d.Secret, d.Event, d.ID, d.Payload, time.Now())
+
+	now := time.Now()
+
+	patch := &po.PatchWebhookDelivery{
+		ID:            d.ID,
+		Status:        WEBHOOK_DELIVERY_PENDING,
+		Attempts:      d.Attempts + 1,
+		NextAttemptAt: now.Add(webhookRetryDelay(d.Attempts + 1)),
+	}
+
+	if statusCode != 0 {
+		patch.LastStatusCode = &statusCode
+	}
+
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		patch.Status = WEBHOOK_DELIVERY_SUCCEEDED
+		patch.NextAttemptAt = now
+		patch.DeliveredAt = &now
+	default:
+		msg := fmt.Sprintf("unexpected status %d", statusCode)
+		if err != nil {
+			msg = err.Error()
+		}
+		if len(msg) > maxWebhookErrorLen {
+			msg = msg[:maxWebhookErrorLen]
+		}
+		patch.LastError = &msg
+
+		if patch.Attempts >= ws.maxAttempts {
+			patch.Status = WEBHOOK_DELIVERY_FAILED
+			patch.NextAttemptAt = now
+		}
+
+		zap.L().Warn("Webhook delivery attempt failed",
+			zap.String("deliveryID", d.ID),
+			zap.String("url", d.URL),
+			zap.Int("attempts", patch.Attempts),
+			zap.String("error", msg))
+	}
+
+	if err := ws.repo.UpdateDeliveryResult(nil, patch); err != nil {
+		zap.L().Error("Failed to record webhook delivery result", zap.String("deliveryID", d.ID), zap.Error(err))
+	}
+}
+
+func (ws *webhookSvc) getWebhook(webhookID string) (*po.BasicWebhook, SvcErr) {
+	webhook, err := ws.repo.GetWebhookByID(nil, webhookID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return nil, NOT_FOUND
+		}
+		zap.L().Error("Failed to get webhook", zap.String("webhookID", webhookID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	return webhook, NO_ERROR
+}
+
+func toWebhookInfo(w *po.BasicWebhook) model.WebhookInfo {
+	events := []string{}
+	if err := json.Unmarshal(w.Events, &events); err != nil {
+		zap.L().Warn("Malformed webhook events", zap.String("webhookID", w.ID), zap.Error(err))
+	}
+
+	return model.WebhookInfo{
+		ID:          w.ID,
+		URL:         w.URL,
+		Events:      events,
+		Description: w.Description,
+		Active:      w.Active,
+		CreatorID:   w.CreatorID,
+		CreatedAt:   w.CreatedAt.Unix(),
+		UpdatedAt:   w.UpdatedAt.Unix(),
+	}
+}
+
+// normalizeWebhookURL requires an absolute http or https URL.
+func normalizeWebhookURL(raw string) (string, SvcErr) {
+	raw = strings.TrimSpace(raw)
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", INVALID_WEBHOOK_DATA
+	}
+
+	return raw, NO_ERROR
+}
+
+// normalizeWebhookEvents checks and dedupes events, returning them marshalled for the events column.
+func normalizeWebhookEvents(events []string) (json.RawMessage, SvcErr) {
+	normalized := []string{}
+
+	for _, e := range events {
+		if !slices.Contains(webhookEvents, e) {
+			return nil, INVALID_WEBHOOK_DATA
+		}
+		if !slices.Contains(normalized, e) {
+			normalized = append(normalized, e)
+		}
+	}
+
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, INVALID_WEBHOOK_DATA
+	}
+
+	return raw, NO_ERROR
+}
+
+func normalizeWebhookSecret(secret string) (string, SvcErr) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minWebhookSecretLen {
+		return "", INVALID_WEBHOOK_DATA
+	}
+
+	return secret, NO_ERROR
+}
+
+// genWebhookSecret returns a random secret of 32 bytes in hex.
+func genWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// signWebhook signs the timestamp and body of a request with HMAC-SHA256,
+// in the form "sha256=<hex>", over "<timestamp>.<body>".
+// Receivers should reject requests whose timestamp is too old, to stop replays.
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// postWebhook posts a signed payload, returning the status code of the response,
+// or 0 if none was received.
+func postWebhook(
+	client *http.Client,
+	webhookURL string,
+	secret string,
+	event string,
+	deliveryID string,
+	payload []byte,
+	now time.Time,
+) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := now.Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "poprako-webhook")
+	req.Header.Set(WEBHOOK_HEADER_EVENT, event)
+	req.Header.Set(WEBHOOK_HEADER_DELIVERY, deliveryID)
+	req.Header.Set(WEBHOOK_HEADER_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WEBHOOK_HEADER_SIGNATURE, signWebhook(secret, timestamp, payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body, so that the connection may be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// webhookRetryDelay returns how long to wait after the given number of failed attempts.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, webhookRetryMax)
+}
+
+// webhookQueue queues webhook deliveries on behalf of the services making the changes.
+type webhookQueue struct {
+	repo repo.WebhookRepo
+}
+
+func newWebhookQueue(r repo.WebhookRepo) *webhookQueue {
+	if r == nil {
+		panic("WebhookRepo cannot be nil")
+	}
+
+	return &webhookQueue{repo: r}
+}
+
+// webhookPayload is the body posted to webhooks.
+type webhookPayload struct {
+	// Shared by the deliveries of the same change, so that receivers may tell repeats.
+	ID         string `json:"id"`
+	Event      string `json:"event"`
+	ComicID    string `json:"comic_id"`
+	ActorID    string `json:"actor_id"`
+	OccurredAt int64  `json:"occurred_at"`
+	Data       any    `json:"data"`
+}
+
+// enqueue queues a delivery of event to each active webhook subscribed to it, with data marshalled as JSON.
+// Pass the transaction of the change as ex, so that deliveries are queued only if the change is made.
+func (wq *webhookQueue) enqueue(ex repo.Exct, event string, comicID string, actorID string, data any) error {
+	webhooks, err := wq.repo.GetActiveWebhooks(ex)
+	if err != nil {
+		return err
+	}
+
+	var subscribed []*po.BasicWebhook
+	for i := range webhooks {
+		events := []string{}
+		if err := json.Unmarshal(webhooks[i].Events, &events); err != nil {
+			return fmt.Errorf("failed to unmarshal webhook events: %w", err)
+		}
+
+		if len(events) == 0 || slices.Contains(events, event) {
+			subscribed = append(subscribed, &webhooks[i])
+		}
+	}
+
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	id, err := genUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook event ID: %w", err)
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:         id,
+		Event:      event,
+		ComicID:    comicID,
+		ActorID:    actorID,
+		OccurredAt: time.Now().Unix(),
+		Data:       data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	deliveries := make([]po.NewWebhookDelivery, 0, len(subscribed))
+	for _, w := range subscribed {
+		deliveryID, err := genUUID()
+		if err != nil {
+			return fmt.Errorf("failed to generate webhook delivery ID: %w", err)
+		}
+
+		deliveries = append(deliveries, po.NewWebhookDelivery{
+			ID:        deliveryID,
+			WebhookID: w.ID,
+			Event:     event,
+			Payload:   payload,
+		})
+	}
+
+	return wq.repo.CreateDeliveries(ex, deliveries)
+}
+
+// comicWebhookData describes the comic created or deleted.
+type comicWebhookData struct {
+	ID        string `json:"id"`
+	WorksetID string `json:"workset_id"`
+	Title     string `json:"title"`
+	Author    string `json:"author"`
+}
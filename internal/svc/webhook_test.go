@@ -0,0 +1,302 @@
+package svc
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+const tWEBHOOK_SECRET = "0123456789abcdef0123456789abcdef"
+
+// fakeWebhookRepo keeps webhooks and deliveries in memory.
+type fakeWebhookRepo struct {
+	repo.WebhookRepo
+
+	mu         sync.Mutex
+	webhooks   []po.BasicWebhook
+	deliveries []po.DueWebhookDelivery
+}
+
+func (r *fakeWebhookRepo) GetActiveWebhooks(repo.Exct) ([]po.BasicWebhook, error) {
+	var lst []po.BasicWebhook
+	for _, w := range r.webhooks {
+		if w.Active {
+			lst = append(lst, w)
+		}
+	}
+	return lst, nil
+}
+
+func (r *fakeWebhookRepo) CreateDeliveries(_ repo.Exct, newDeliveries []po.NewWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range newDeliveries {
+		r.deliveries = append(r.deliveries, po.DueWebhookDelivery{
+			BasicWebhookDelivery: po.BasicWebhookDelivery{
+				ID:        d.ID,
+				WebhookID: d.WebhookID,
+				Event:     d.Event,
+				Payload:   d.Payload,
+				Status:    WEBHOOK_DELIVERY_PENDING,
+			},
+		})
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepo) ClaimDueDeliveries(_ repo.Exct, now time.Time, leaseUntil time.Time, limit int) ([]po.DueWebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []po.DueWebhookDelivery
+	for i := range r.deliveries {
+		d := &r.deliveries[i]
+		if len(due) == limit || d.Status != WEBHOOK_DELIVERY_PENDING || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = leaseUntil
+		due = append(due, *d)
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepo) UpdateDeliveryResult(_ repo.Exct, patch *po.PatchWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		d := &r.deliveries[i]
+		if d.ID != patch.ID {
+			continue
+		}
+		d.Status = patch.Status
+		d.Attempts = patch.Attempts
+		d.NextAttemptAt = patch.NextAttemptAt
+		d.LastStatusCode = patch.LastStatusCode
+		d.LastError = patch.LastError
+		d.DeliveredAt = patch.DeliveredAt
+		return nil
+	}
+	return repo.REC_NOT_FOUND
+}
+
+// delivery returns the only delivery queued.
+func (r *fakeWebhookRepo) delivery(t *testing.T) po.DueWebhookDelivery {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.deliveries) != 1 {
+		t.Fatalf("want 1 delivery, got %d", len(r.deliveries))
+	}
+	return r.deliveries[0]
+}
+
+// makeDue brings the next attempts of all deliveries forward to now.
+func (r *fakeWebhookRepo) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		r.deliveries[i].NextAttemptAt = time.Time{}
+	}
+}
+
+func newTestWebhookSvc(r *fakeWebhookRepo, maxAttempts int) *webhookSvc {
+	return NewWebhookSvc(r, struct{ repo.AuditLogRepo }{}, 5*time.Second, maxAttempts).(*webhookSvc)
+}
+
+// queueTestDelivery queues a comic.created delivery to url.
+func queueTestDelivery(t *testing.T, r *fakeWebhookRepo, url string) {
+	t.Helper()
+
+	r.webhooks = []po.BasicWebhook{{ID: "hook-1", URL: url, Secret: tWEBHOOK_SECRET, Events: json.RawMessage(`[]`), Active: true}}
+
+	if err := newWebhookQueue(r).enqueue(nil, WEBHOOK_EVENT_COMIC_CREATE, "c-1", "u-1", comicWebhookData{ID: "c-1", Title: "T"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	// The fake does not join webhooks, so the delivery carries where to post.
+	r.deliveries[0].URL = url
+	r.deliveries[0].Secret = tWEBHOOK_SECRET
+}
+
+func TestWebhookDispatchSignsAndRetries(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %q", req.Method, req.Header.Get("Content-Type"))
+		}
+		if got := req.Header.Get(WEBHOOK_HEADER_EVENT); got != WEBHOOK_EVENT_COMIC_CREATE {
+			t.Errorf("event header = %q", got)
+		}
+		if req.Header.Get(WEBHOOK_HEADER_DELIVERY) == "" {
+			t.Error("delivery header missing")
+		}
+
+		timestamp, err := strconv.ParseInt(req.Header.Get(WEBHOOK_HEADER_TIMESTAMP), 10, 64)
+		if err != nil {
+			t.Errorf("timestamp header: %v", err)
+		}
+		if got, want := req.Header.Get(WEBHOOK_HEADER_SIGNATURE), signWebhook(tWEBHOOK_SECRET, timestamp, body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("payload: %v", err)
+		}
+		if payload.Event != WEBHOOK_EVENT_COMIC_CREATE || payload.ComicID != "c-1" || payload.ActorID != "u-1" {
+			t.Errorf("unexpected payload %s", body)
+		}
+
+		mu.Lock()
+		calls++
+		first := calls == 1
+		mu.Unlock()
+
+		// Fail the first attempt only.
+		if first {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	r := &fakeWebhookRepo{}
+	ws := newTestWebhookSvc(r, 3)
+	queueTestDelivery(t, r, receiver.URL)
+
+	if n := ws.DispatchDueDeliveries(); n != 1 {
+		t.Fatalf("first dispatch attempted %d, want 1", n)
+	}
+
+	d := r.delivery(t)
+	if d.Status != WEBHOOK_DELIVERY_PENDING || d.Attempts != 1 {
+		t.Fatalf("after failure: status %s, attempts %d", d.Status, d.Attempts)
+	}
+	if d.LastStatusCode == nil || *d.LastStatusCode != http.StatusInternalServerError || d.LastError == nil {
+		t.Fatalf("after failure: status code %v, error %v", d.LastStatusCode, d.LastError)
+	}
+	if !d.NextAttemptAt.After(time.Now()) {
+		t.Fatal("retry not scheduled in the future")
+	}
+
+	// Not due yet.
+	if n := ws.DispatchDueDeliveries(); n != 0 {
+		t.Fatalf("dispatch before retry attempted %d, want 0", n)
+	}
+
+	r.makeDue()
+
+	if n := ws.DispatchDueDeliveries(); n != 1 {
+		t.Fatalf("retry attempted %d, want 1", n)
+	}
+
+	d = r.delivery(t)
+	if d.Status != WEBHOOK_DELIVERY_SUCCEEDED || d.Attempts != 2 || d.DeliveredAt == nil {
+		t.Fatalf("after retry: status %s, attempts %d, delivered at %v", d.Status, d.Attempts, d.DeliveredAt)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Fatalf("receiver called %d times, want 2", calls)
+	}
+}
+
+func TestWebhookDispatchGivesUp(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	url := receiver.URL
+	// Refuse connections from now on.
+	receiver.Close()
+
+	r := &fakeWebhookRepo{}
+	ws := newTestWebhookSvc(r, 2)
+	queueTestDelivery(t, r, url)
+
+	ws.DispatchDueDeliveries()
+	if d := r.delivery(t); d.Status != WEBHOOK_DELIVERY_PENDING || d.LastStatusCode != nil || d.LastError == nil {
+		t.Fatalf("after first attempt: status %s, status code %v, error %v", d.Status, d.LastStatusCode, d.LastError)
+	}
+
+	r.makeDue()
+	ws.DispatchDueDeliveries()
+	if d := r.delivery(t); d.Status != WEBHOOK_DELIVERY_FAILED || d.Attempts != 2 {
+		t.Fatalf("after last attempt: status %s, attempts %d", d.Status, d.Attempts)
+	}
+
+	// Failed deliveries are not claimed again.
+	r.makeDue()
+	if n := ws.DispatchDueDeliveries(); n != 0 {
+		t.Fatalf("failed delivery attempted again")
+	}
+}
+
+func TestWebhookQueueFiltersEvents(t *testing.T) {
+	r := &fakeWebhookRepo{webhooks: []po.BasicWebhook{
+		{ID: "all", Events: json.RawMessage(`[]`), Active: true},
+		{ID: "created", Events: json.RawMessage(`["comic.created"]`), Active: true},
+		{ID: "uploaded", Events: json.RawMessage(`["page.uploaded"]`), Active: true},
+		{ID: "inactive", Events: json.RawMessage(`[]`), Active: false},
+	}}
+
+	if err := newWebhookQueue(r).enqueue(nil, WEBHOOK_EVENT_COMIC_CREATE, "c-1", "u-1", nil); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	if len(r.deliveries) != 2 || r.deliveries[0].WebhookID != "all" || r.deliveries[1].WebhookID != "created" {
+		t.Fatalf("unexpected deliveries %+v", r.deliveries)
+	}
+
+	// Receivers tell repeats of the same change by the ID of the payload.
+	if string(r.deliveries[0].Payload) != string(r.deliveries[1].Payload) {
+		t.Fatal("payloads of the same change differ")
+	}
+	if r.deliveries[0].ID == r.deliveries[1].ID {
+		t.Fatal("deliveries share an ID")
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, webhookRetryBase},
+		{2, 2 * webhookRetryBase},
+		{4, 8 * webhookRetryBase},
+		{20, webhookRetryMax},
+	}
+
+	for _, c := range cases {
+		if got := webhookRetryDelay(c.attempts); got != c.want {
+			t.Errorf("webhookRetryDelay(%d) = %v, want %v", c.attempts, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeWebhookEvents(t *testing.T) {
+	raw, svcErr := normalizeWebhookEvents([]string{WEBHOOK_EVENT_PAGE_UPLOAD, WEBHOOK_EVENT_PAGE_UPLOAD})
+	if svcErr != NO_ERROR || string(raw) != `["page.uploaded"]` {
+		t.Fatalf("got %s, %v", raw, svcErr)
+	}
+
+	if _, svcErr := normalizeWebhookEvents([]string{"comic.liked"}); svcErr != INVALID_WEBHOOK_DATA {
+		t.Fatalf("unknown event accepted")
+	}
+}
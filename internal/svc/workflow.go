@@ -0,0 +1,498 @@
+package svc
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// workflowStages lists the stages in order.
+var workflowStages = []string{
+	model.COMIC_STAGE_PENDING,
+	model.COMIC_STAGE_TRANSLATING,
+	model.COMIC_STAGE_PROOFREADING,
+	model.COMIC_STAGE_TYPESETTING,
+	model.COMIC_STAGE_REVIEWING,
+	model.COMIC_STAGE_UPLOADING,
+	model.COMIC_STAGE_COMPLETED,
+}
+
+// advanceRoles maps each stage to the role that may move a comic on from it.
+var advanceRoles = map[string]string{
+	model.COMIC_STAGE_PENDING:      ROLE_TRANSLATOR,
+	model.COMIC_STAGE_TRANSLATING:  ROLE_TRANSLATOR,
+	model.COMIC_STAGE_PROOFREADING: ROLE_PROOFREADER,
+	model.COMIC_STAGE_TYPESETTING:  ROLE_TYPESETTER,
+	model.COMIC_STAGE_REVIEWING:    ROLE_REVIEWER,
+	model.COMIC_STAGE_UPLOADING:    ROLE_UPLOADER,
+}
+
+// reopenRole is the role that may send a comic back to an earlier stage.
+const reopenRole = ROLE_REVIEWER
+
+// stageTimestamps pairs each timestamp column with the stage from which on it is set,
+// so that the columns can be derived from the stage.
+var stageTimestamps = []struct {
+	since string
+	field func(*po.PatchComic) **time.Time
+	value func(*po.BasicComic) *time.Time
+}{
+	{
+		model.COMIC_STAGE_TRANSLATING,
+		func(p *po.PatchComic) **time.Time { return &p.TranslatingStartedAt },
+		func(c *po.BasicComic) *time.Time { return c.TranslatingStartedAt },
+	},
+	{
+		model.COMIC_STAGE_PROOFREADING,
+		func(p *po.PatchComic) **time.Time { return &p.TranslatingCompletedAt },
+		func(c *po.BasicComic) *time.Time { return c.TranslatingCompletedAt },
+	},
+	{
+		model.COMIC_STAGE_PROOFREADING,
+		func(p *po.PatchComic) **time.Time { return &p.ProofreadingStartedAt },
+		func(c *po.BasicComic) *time.Time { return c.ProofreadingStartedAt },
+	},
+	{
+		model.COMIC_STAGE_TYPESETTING,
+		func(p *po.PatchComic) **time.Time { return &p.ProofreadingCompletedAt },
+		func(c *po.BasicComic) *time.Time { return c.ProofreadingCompletedAt },
+	},
+	{
+		model.COMIC_STAGE_TYPESETTING,
+		func(p *po.PatchComic) **time.Time { return &p.TypesettingStartedAt },
+		func(c *po.BasicComic) *time.Time { return c.TypesettingStartedAt },
+	},
+	{
+		model.COMIC_STAGE_REVIEWING,
+		func(p *po.PatchComic) **time.Time { return &p.TypesettingCompletedAt },
+		func(c *po.BasicComic) *time.Time { return c.TypesettingCompletedAt },
+	},
+	{
+		model.COMIC_STAGE_UPLOADING,
+		func(p *po.PatchComic) **time.Time { return &p.ReviewingCompletedAt },
+		func(c *po.BasicComic) *time.Time { return c.ReviewingCompletedAt },
+	},
+	{
+		model.COMIC_STAGE_COMPLETED,
+		func(p *po.PatchComic) **time.Time { return &p.UploadingCompletedAt },
+		func(c *po.BasicComic) *time.Time { return c.UploadingCompletedAt },
+	},
+}
+
+// stageGate tells why a unit keeps a comic from completing a stage, nil if it does not.
+type stageGate func(unit *po.BasicComicUnit) []string
+
+// stageGates maps the stages whose completion is gated to their gates.
+var stageGates = map[string]stageGate{
+	model.COMIC_STAGE_TRANSLATING: func(u *po.BasicComicUnit) []string {
+		if isBlank(u.TranslatedText) {
+			return []string{model.UNIT_BLOCK_UNTRANSLATED}
+		}
+		return nil
+	},
+	model.COMIC_STAGE_PROOFREADING: func(u *po.BasicComicUnit) []string {
+		var reasons []string
+		if !u.Proved {
+			reasons = append(reasons, model.UNIT_BLOCK_UNPROVED)
+		}
+		// A translator comment counts as resolved once the proofreader has answered it.
+		if !isBlank(u.TranslatorComment) && isBlank(u.ProofreaderComment) {
+			reasons = append(reasons, model.UNIT_BLOCK_UNRESOLVED_COMMENT)
+		}
+		return reasons
+	},
+}
+
+// WorkflowSvc moves comics through the workflow stages.
+// A comic advances one stage at a time, by users assigned the role of its current stage,
+// and may be reopened at any earlier stage by a reviewer with a reason.
+// Admins and the creator of a comic may make any of these transitions.
+//
+// Translating and proofreading are only completed once the units are done,
+// unless an admin forces the transition.
+type WorkflowSvc interface {
+	GetComicWorkflow(opID string, comicID string) (SvcRslt[model.ComicWorkflowInfo], SvcErr)
+
+	// On STAGE_GATE_BLOCKED the result holds the blocking units.
+	TransitionComicStage(
+		opID string,
+		meta model.ReqMeta,
+		args model.TransitionComicStageArgs,
+	) (SvcRslt[model.StageGateReport], SvcErr)
+}
+
+type workflowSvc struct {
+	repo     repo.ComicRepo
+	pageRepo repo.ComicPageRepo
+	unitRepo repo.ComicUnitRepo
+	asgnRepo repo.ComicAsgnRepo
+	authz    AuthzSvc
+	audit    *auditRecorder
+	events   *comicEventRecorder
+	notifier *notifier
+	webhooks *webhookQueue
+}
+
+// NewWorkflowSvc creates a new WorkflowSvc. None of the arguments may be nil.
+func NewWorkflowSvc(
+	r repo.ComicRepo,
+	cpr repo.ComicPageRepo,
+	cur repo.ComicUnitRepo,
+	car repo.ComicAsgnRepo,
+	alr repo.AuditLogRepo,
+	cer repo.ComicEventRepo,
+	nr repo.NotificationRepo,
+	whr repo.WebhookRepo,
+	authz AuthzSvc,
+) WorkflowSvc {
+	if r == nil {
+		panic("ComicRepo cannot be nil")
+	}
+	if cpr == nil {
+		panic("ComicPageRepo cannot be nil")
+	}
+	if cur == nil {
+		panic("ComicUnitRepo cannot be nil")
+	}
+	if car == nil {
+		panic("ComicAsgnRepo cannot be nil")
+	}
+	if authz == nil {
+		panic("AuthzSvc cannot be nil")
+	}
+
+	return &workflowSvc{
+		repo:     r,
+		pageRepo: cpr,
+		unitRepo: cur,
+		asgnRepo: car,
+		authz:    authz,
+		audit:    newAuditRecorder(alr),
+		events:   newComicEventRecorder(cer),
+		notifier: newNotifier(nr),
+		webhooks: newWebhookQueue(whr),
+	}
+}
+
+// GetComicWorkflow returns the stage of a comic
+// and the stages opID may move it to.
+func (ws *workflowSvc) GetComicWorkflow(opID string, comicID string) (SvcRslt[model.ComicWorkflowInfo], SvcErr) {
+	comic, svcErr := ws.getComic(comicID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.ComicWorkflowInfo]{}, svcErr
+	}
+
+	allowed, svcErr := ws.allowedTransitions(opID, comic)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.ComicWorkflowInfo]{}, svcErr
+	}
+
+	gate, svcErr := ws.checkStageGate(comic)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.ComicWorkflowInfo]{}, svcErr
+	}
+
+	return accept(200, model.ComicWorkflowInfo{
+		ComicID:     comic.ID,
+		Stage:       comic.Stage,
+		Transitions: allowed,
+		Gate:        gate,
+	}), NO_ERROR
+}
+
+// TransitionComicStage moves a comic to args.Stage, setting the timestamps of
+// the stages passed and clearing those of the stages reopened.
+func (ws *workflowSvc) TransitionComicStage(
+	opID string,
+	meta model.ReqMeta,
+	args model.TransitionComicStageArgs,
+) (SvcRslt[model.StageGateReport], SvcErr) {
+	to := stageIndex(args.Stage)
+	if to < 0 {
+		return SvcRslt[model.StageGateReport]{}, INVALID_STAGE
+	}
+
+	comic, svcErr := ws.getComic(args.ComicID)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.StageGateReport]{}, svcErr
+	}
+
+	from := stageIndex(comic.Stage)
+	if to == from || to > from+1 {
+		zap.L().Warn("Invalid comic stage transition",
+			zap.String("comicID", args.ComicID),
+			zap.String("from", comic.Stage),
+			zap.String("to", args.Stage))
+		return SvcRslt[model.StageGateReport]{}, INVALID_STAGE_TRANSITION
+	}
+
+	var reason string
+	if args.Reason != nil {
+		reason = strings.TrimSpace(*args.Reason)
+	}
+	if to < from && reason == "" {
+		return SvcRslt[model.StageGateReport]{}, REOPEN_REASON_REQUIRED
+	}
+
+	allowed, svcErr := ws.allowedTransitions(opID, comic)
+	if svcErr != NO_ERROR {
+		return SvcRslt[model.StageGateReport]{}, svcErr
+	}
+	if !slices.Contains(allowed, args.Stage) {
+		zap.L().Warn("User not allowed to transition comic stage",
+			zap.String("userID", opID),
+			zap.String("comicID", args.ComicID),
+			zap.String("to", args.Stage))
+		return SvcRslt[model.StageGateReport]{}, PERMISSION_DENIED
+	}
+
+	if args.Force {
+		isAdmin, svcErr := ws.authz.IsAdmin(opID)
+		if svcErr != NO_ERROR {
+			return SvcRslt[model.StageGateReport]{}, svcErr
+		}
+		if !isAdmin {
+			zap.L().Warn("Non-admin user attempted to force comic stage", zap.String("userID", opID), zap.String("comicID", args.ComicID))
+			return SvcRslt[model.StageGateReport]{}, PERMISSION_DENIED
+		}
+	}
+
+	// Only completing a stage is gated, reopening never is.
+	var gate *model.StageGateReport
+	if to > from {
+		gate, svcErr = ws.checkStageGate(comic)
+		if svcErr != NO_ERROR {
+			return SvcRslt[model.StageGateReport]{}, svcErr
+		}
+	}
+	if gate != nil && !args.Force {
+		svcErr = STAGE_GATE_BLOCKED
+		return SvcRslt[model.StageGateReport]{Code: svcErr.Code(), Data: gate}, svcErr
+	}
+
+	patch := syncStageTimestamps(comic, args.Stage, time.Now())
+
+	if err := ws.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ws.repo.UpdateComicStageByID(tx, patch, comic.Stage); err != nil {
+			return err
+		}
+
+		after := map[string]any{"stage": args.Stage}
+		if to < from {
+			after["reason"] = reason
+		}
+
+		detail := stageEventDetail{
+			From:   comic.Stage,
+			To:     args.Stage,
+			Reason: reason,
+		}
+
+		// Record the override along with what it overrode.
+		if gate != nil {
+			after["forced"] = true
+			after["blocking"] = gate
+
+			detail.Forced = true
+			for _, p := range gate.Pages {
+				detail.BlockingUnits += len(p.Units)
+			}
+		}
+
+		if err := ws.events.record(tx, comic.ID, opID, COMIC_EVENT_STAGE_TRANSITION, detail); err != nil {
+			return err
+		}
+
+		asgns, err := ws.asgnRepo.GetAsgnsByComicID(tx, comic.ID, 0, 0)
+		if err != nil {
+			return err
+		}
+
+		assignees := make([]string, 0, len(asgns))
+		for _, a := range asgns {
+			assignees = append(assignees, a.UserID)
+		}
+
+		if err := ws.notifier.notify(tx, assignees, notification{
+			Kind:    NOTIFICATION_STAGE_CHANGE,
+			ComicID: comic.ID,
+			ActorID: opID,
+			Detail:  detail,
+		}); err != nil {
+			return err
+		}
+
+		if err := ws.webhooks.enqueue(tx, WEBHOOK_EVENT_COMIC_STAGE, comic.ID, opID, detail); err != nil {
+			return err
+		}
+
+		return ws.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_TRANSITION,
+			EntityType: AUDIT_ENTITY_COMIC,
+			EntityID:   comic.ID,
+			Before:     map[string]any{"stage": comic.Stage},
+			After:      after,
+		})
+	}); err != nil {
+		if err == repo.REC_NOT_FOUND {
+			// Moved by someone else in the meantime.
+			return SvcRslt[model.StageGateReport]{}, STAGE_CONFLICT
+		}
+		zap.L().Error("Failed to transition comic stage", zap.String("comicID", args.ComicID), zap.Error(err))
+		return SvcRslt[model.StageGateReport]{}, DB_FAILURE
+	}
+
+	return SvcRslt[model.StageGateReport]{}, NO_ERROR
+}
+
+// stageEventDetail describes a stage transition in comic events.
+type stageEventDetail struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Set when reopening.
+	Reason string `json:"reason,omitempty"`
+
+	// Set when an admin completed the stage despite blocking units.
+	Forced        bool `json:"forced,omitempty"`
+	BlockingUnits int  `json:"blocking_units,omitempty"`
+}
+
+// checkStageGate reports the units keeping comic from completing its current stage,
+// nil if there are none or the stage is not gated.
+// All units of the comic are loaded and judged one by one by the gate of the stage.
+func (ws *workflowSvc) checkStageGate(comic *po.BasicComic) (*model.StageGateReport, SvcErr) {
+	gate, ok := stageGates[comic.Stage]
+	if !ok {
+		return nil, NO_ERROR
+	}
+
+	pages, err := ws.pageRepo.GetPagesByComicID(nil, comic.ID)
+	if err != nil {
+		zap.L().Error("Failed to get pages for stage gate", zap.String("comicID", comic.ID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	units, err := ws.unitRepo.GetUnitsByComicID(nil, comic.ID)
+	if err != nil {
+		zap.L().Error("Failed to get units for stage gate", zap.String("comicID", comic.ID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	// Pages are blocked by their units alone, judged by the same gate that reports them.
+	blocking := map[string][]model.StageGateBlockUnit{}
+	for i := range units {
+		if reasons := gate(&units[i]); len(reasons) > 0 {
+			blocking[units[i].PageID] = append(blocking[units[i].PageID], model.StageGateBlockUnit{
+				UnitID:  units[i].ID,
+				Index:   units[i].Index,
+				Reasons: reasons,
+			})
+		}
+	}
+
+	slices.SortFunc(pages, func(a, b po.BasicComicPage) int { return cmp.Compare(a.Index, b.Index) })
+
+	report := &model.StageGateReport{Stage: comic.Stage, Pages: []model.StageGateBlockPage{}}
+
+	for _, page := range pages {
+		blockUnits, ok := blocking[page.ID]
+		if !ok {
+			continue
+		}
+
+		slices.SortFunc(blockUnits, func(a, b model.StageGateBlockUnit) int { return cmp.Compare(a.Index, b.Index) })
+
+		report.Pages = append(report.Pages, model.StageGateBlockPage{
+			PageID: page.ID,
+			Index:  page.Index,
+			Units:  blockUnits,
+		})
+	}
+
+	if len(report.Pages) == 0 {
+		return nil, NO_ERROR
+	}
+
+	return report, NO_ERROR
+}
+
+func (ws *workflowSvc) getComic(comicID string) (*po.BasicComic, SvcErr) {
+	comic, err := ws.repo.GetComicByID(nil, comicID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			return nil, NOT_FOUND
+		}
+		zap.L().Error("Failed to get comic for workflow", zap.String("comicID", comicID), zap.Error(err))
+		return nil, DB_FAILURE
+	}
+
+	return comic, NO_ERROR
+}
+
+// allowedTransitions lists the stages opID may move the comic to, in order.
+func (ws *workflowSvc) allowedTransitions(opID string, comic *po.BasicComic) ([]string, SvcErr) {
+	isAdmin, svcErr := ws.authz.IsAdmin(opID)
+	if svcErr != NO_ERROR {
+		return nil, svcErr
+	}
+
+	access, svcErr := ws.authz.GetComicAccess(opID, comic.ID)
+	if svcErr != NO_ERROR {
+		return nil, svcErr
+	}
+
+	privileged := isAdmin || access.IsCreator
+	from := stageIndex(comic.Stage)
+
+	allowed := []string{}
+
+	if privileged || access.Roles[reopenRole] {
+		allowed = append(allowed, workflowStages[:max(from, 0)]...)
+	}
+
+	if role, ok := advanceRoles[comic.Stage]; ok && (privileged || access.Roles[role]) {
+		allowed = append(allowed, workflowStages[from+1])
+	}
+
+	return allowed, NO_ERROR
+}
+
+// syncStageTimestamps builds the patch moving comic to stage.
+// Timestamps of the stages up to it are kept or set to now, the others are cleared.
+func syncStageTimestamps(comic *po.BasicComic, stage string, now time.Time) *po.PatchComic {
+	patch := &po.PatchComic{ID: comic.ID, Stage: &stage}
+
+	to := stageIndex(stage)
+
+	for _, ts := range stageTimestamps {
+		want := to >= stageIndex(ts.since)
+		have := ts.value(comic) != nil
+
+		switch {
+		case want && !have:
+			*ts.field(patch) = &now
+		case !want && have:
+			*ts.field(patch) = &time.Time{}
+		}
+	}
+
+	return patch
+}
+
+// isBlank reports whether s is nil or only whitespace.
+func isBlank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// stageIndex returns the position of stage in the workflow, or -1 if unknown.
+func stageIndex(stage string) int {
+	return slices.Index(workflowStages, stage)
+}
@@ -0,0 +1,109 @@
+package svc
+
+import (
+	"slices"
+	"testing"
+
+	"poprako-main-server/internal/model"
+	"poprako-main-server/internal/model/po"
+	"poprako-main-server/internal/repo"
+)
+
+type fakeGatePageRepo struct {
+	repo.ComicPageRepo
+
+	pages []po.BasicComicPage
+}
+
+func (r *fakeGatePageRepo) GetPagesByComicID(repo.Exct, string) ([]po.BasicComicPage, error) {
+	return slices.Clone(r.pages), nil
+}
+
+type fakeGateUnitRepo struct {
+	repo.ComicUnitRepo
+
+	units []po.BasicComicUnit
+}
+
+func (r *fakeGateUnitRepo) GetUnitsByComicID(repo.Exct, string) ([]po.BasicComicUnit, error) {
+	return slices.Clone(r.units), nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestStageGates(t *testing.T) {
+	cases := []struct {
+		name  string
+		stage string
+		unit  po.BasicComicUnit
+		want  []string
+	}{
+		{"untranslated", model.COMIC_STAGE_TRANSLATING, po.BasicComicUnit{}, []string{model.UNIT_BLOCK_UNTRANSLATED}},
+		{"blank translation", model.COMIC_STAGE_TRANSLATING, po.BasicComicUnit{TranslatedText: strPtr(" \t　")}, []string{model.UNIT_BLOCK_UNTRANSLATED}},
+		{"translated", model.COMIC_STAGE_TRANSLATING, po.BasicComicUnit{TranslatedText: strPtr("译文")}, nil},
+		{"unproved", model.COMIC_STAGE_PROOFREADING, po.BasicComicUnit{}, []string{model.UNIT_BLOCK_UNPROVED}},
+		{"proved", model.COMIC_STAGE_PROOFREADING, po.BasicComicUnit{Proved: true}, nil},
+		{"blank translator comment", model.COMIC_STAGE_PROOFREADING, po.BasicComicUnit{Proved: true, TranslatorComment: strPtr("  ")}, nil},
+		{
+			"unanswered comment", model.COMIC_STAGE_PROOFREADING,
+			po.BasicComicUnit{TranslatorComment: strPtr("?"), ProofreaderComment: strPtr(" ")},
+			[]string{model.UNIT_BLOCK_UNPROVED, model.UNIT_BLOCK_UNRESOLVED_COMMENT},
+		},
+		{"answered comment", model.COMIC_STAGE_PROOFREADING, po.BasicComicUnit{Proved: true, TranslatorComment: strPtr("?"), ProofreaderComment: strPtr("ok")}, nil},
+	}
+
+	for _, c := range cases {
+		if got := stageGates[c.stage](&c.unit); !slices.Equal(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	for _, stage := range []string{model.COMIC_STAGE_PENDING, model.COMIC_STAGE_TYPESETTING, model.COMIC_STAGE_REVIEWING} {
+		if _, ok := stageGates[stage]; ok {
+			t.Errorf("stage %s gated", stage)
+		}
+	}
+}
+
+func TestCheckStageGate(t *testing.T) {
+	ws := &workflowSvc{
+		pageRepo: &fakeGatePageRepo{pages: []po.BasicComicPage{
+			{ID: "p-2", Index: 2},
+			{ID: "p-1", Index: 1},
+			{ID: "p-3", Index: 3},
+		}},
+		unitRepo: &fakeGateUnitRepo{units: []po.BasicComicUnit{
+			{ID: "u-22", PageID: "p-2", Index: 2, TranslatedText: strPtr(" ")},
+			{ID: "u-21", PageID: "p-2", Index: 1},
+			{ID: "u-11", PageID: "p-1", Index: 1, TranslatedText: strPtr("done")},
+			{ID: "u-31", PageID: "p-3", Index: 1, TranslatedText: strPtr("done"), TranslatorComment: strPtr(" ")},
+		}},
+	}
+
+	report, svcErr := ws.checkStageGate(&po.BasicComic{ID: "c-1", Stage: model.COMIC_STAGE_TRANSLATING})
+	if svcErr != NO_ERROR {
+		t.Fatalf("got %v", svcErr)
+	}
+
+	// Only the page with blocking units is listed, its units in order.
+	if report == nil || report.Stage != model.COMIC_STAGE_TRANSLATING || len(report.Pages) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	page := report.Pages[0]
+	if page.PageID != "p-2" || len(page.Units) != 2 || page.Units[0].UnitID != "u-21" || page.Units[1].UnitID != "u-22" {
+		t.Fatalf("unexpected page %+v", page)
+	}
+
+	// Whitespace comments do not block proofreading.
+	ws.unitRepo = &fakeGateUnitRepo{units: []po.BasicComicUnit{
+		{ID: "u-31", PageID: "p-3", Index: 1, Proved: true, TranslatorComment: strPtr(" \n")},
+	}}
+	if report, svcErr := ws.checkStageGate(&po.BasicComic{ID: "c-1", Stage: model.COMIC_STAGE_PROOFREADING}); svcErr != NO_ERROR || report != nil {
+		t.Fatalf("proofreading blocked: %+v, %v", report, svcErr)
+	}
+
+	// Ungated stages are not checked.
+	if report, svcErr := ws.checkStageGate(&po.BasicComic{ID: "c-1", Stage: model.COMIC_STAGE_TYPESETTING}); svcErr != NO_ERROR || report != nil {
+		t.Fatalf("typesetting gated: %+v, %v", report, svcErr)
+	}
+}
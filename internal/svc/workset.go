@@ -17,16 +17,17 @@ type WorksetSvc interface {
 
 	UpdateWorksetByID(args *model.UpdateWorksetArgs) SvcErr
 
-	DeleteWorksetByID(worksetID string) SvcErr
+	DeleteWorksetByID(opID string, meta model.ReqMeta, worksetID string) SvcErr
 }
 
 type worksetSvc struct {
 	repo     repo.WorksetRepo
 	userRepo repo.UserRepo
+	audit    *auditRecorder
 }
 
-// NewWorksetSvc creates a new WorksetSvc. r must not be nil.
-func NewWorksetSvc(r repo.WorksetRepo, ur repo.UserRepo) WorksetSvc {
+// NewWorksetSvc creates a new WorksetSvc. r, ur and alr must not be nil.
+func NewWorksetSvc(r repo.WorksetRepo, ur repo.UserRepo, alr repo.AuditLogRepo) WorksetSvc {
 	if r == nil {
 		panic("WorksetRepo cannot be nil")
 	}
@@ -34,7 +35,7 @@ func NewWorksetSvc(r repo.WorksetRepo, ur repo.UserRepo) WorksetSvc {
 		panic("UserRepo cannot be nil")
 	}
 
-	return &worksetSvc{repo: r, userRepo: ur}
+	return &worksetSvc{repo: r, userRepo: ur, audit: newAuditRecorder(alr)}
 }
 
 func (ws *worksetSvc) GetWorksetByID(worksetID string) (SvcRslt[model.WorksetInfo], SvcErr) {
@@ -135,8 +136,29 @@ func (ws *worksetSvc) UpdateWorksetByID(args *model.UpdateWorksetArgs) SvcErr {
 	return NO_ERROR
 }
 
-func (ws *worksetSvc) DeleteWorksetByID(worksetID string) SvcErr {
-	if err := ws.repo.DeleteWorksetByID(nil, worksetID); err != nil {
+func (ws *worksetSvc) DeleteWorksetByID(opID string, meta model.ReqMeta, worksetID string) SvcErr {
+	workset, err := ws.repo.GetWorksetByID(nil, worksetID)
+	if err != nil {
+		if err == repo.REC_NOT_FOUND {
+			zap.L().Warn("Workset not found for deletion", zap.String("worksetID", worksetID))
+			return NOT_FOUND
+		}
+		zap.L().Error("Failed to get workset for deletion", zap.String("worksetID", worksetID), zap.Error(err))
+		return DB_FAILURE
+	}
+
+	if err := ws.repo.Exct().Transaction(func(tx repo.Exct) error {
+		if err := ws.repo.DeleteWorksetByID(tx, worksetID); err != nil {
+			return err
+		}
+
+		return ws.audit.record(tx, opID, meta, auditEntry{
+			Action:     AUDIT_ACTION_DELETE,
+			EntityType: AUDIT_ENTITY_WORKSET,
+			EntityID:   worksetID,
+			Before:     workset,
+		})
+	}); err != nil {
 		if err == repo.REC_NOT_FOUND {
 			zap.L().Warn("Workset not found for deletion", zap.String("worksetID", worksetID))
 			return NOT_FOUND
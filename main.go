@@ -2,13 +2,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"poprako-main-server/internal/api/http"
 	"poprako-main-server/internal/config"
 	"poprako-main-server/internal/jwtcodec"
 	"poprako-main-server/internal/logger"
+	"poprako-main-server/internal/onebot"
 	"poprako-main-server/internal/oss"
 	"poprako-main-server/internal/repo"
 	"poprako-main-server/internal/seeder"
@@ -38,7 +44,21 @@ func main() {
 
 	state := initAppState(cfg, ex)
 
-	http.Run(state)
+	// Cancelled on shutdown, stopping the server and the background loops.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var loops sync.WaitGroup
+
+	startStallChecker(ctx, &loops, state.ComicAsgnSvc, cfg)
+	startWebhookDispatcher(ctx, &loops, state.WebhookSvc, cfg)
+	startOneBotPusher(ctx, &loops, state.OneBotSvc, cfg)
+
+	http.Run(ctx, state)
+
+	// The server may also stop by itself, failing to listen for example.
+	stop()
+	loops.Wait()
 }
 
 func initEnv() {
@@ -54,6 +74,80 @@ func initLogger() {
 	zap.ReplaceGlobals(lgr)
 }
 
+// runEvery runs fn right away and then every interval until ctx is done.
+// A run in progress is not interrupted.
+func runEvery(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startLoop runs fn every interval in the background until ctx is done.
+func startLoop(ctx context.Context, wg *sync.WaitGroup, interval time.Duration, fn func()) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		runEvery(ctx, interval, fn)
+	}()
+}
+
+// startStallChecker flags stalled assignments periodically in the background.
+func startStallChecker(ctx context.Context, wg *sync.WaitGroup, asgnSvc svc.ComicAsgnSvc, cfg config.AppCfg) {
+	if cfg.StallCheckSecs <= 0 || cfg.StallAfterSecs <= 0 {
+		zap.L().Info("Stall checker disabled")
+		return
+	}
+
+	interval := time.Duration(cfg.StallCheckSecs) * time.Second
+	idleFor := time.Duration(cfg.StallAfterSecs) * time.Second
+
+	startLoop(ctx, wg, interval, func() {
+		// Failures are logged by the service and retried on the next tick.
+		asgnSvc.FlagStalledAsgns(idleFor)
+	})
+}
+
+// startWebhookDispatcher posts due webhook deliveries periodically in the background.
+func startWebhookDispatcher(ctx context.Context, wg *sync.WaitGroup, webhookSvc svc.WebhookSvc, cfg config.AppCfg) {
+	if cfg.WebhookDispatchSecs <= 0 {
+		zap.L().Info("Webhook dispatcher disabled")
+		return
+	}
+
+	interval := time.Duration(cfg.WebhookDispatchSecs) * time.Second
+
+	startLoop(ctx, wg, interval, func() {
+		// Failed attempts are recorded by the service and retried once due again.
+		webhookSvc.DispatchDueDeliveries()
+	})
+}
+
+// startOneBotPusher pushes notifications to QQ periodically in the background.
+func startOneBotPusher(ctx context.Context, wg *sync.WaitGroup, oneBotSvc svc.OneBotSvc, cfg config.AppCfg) {
+	if cfg.OneBotAPIURL == "" || cfg.OneBotPushSecs <= 0 {
+		zap.L().Info("OneBot pusher disabled")
+		return
+	}
+
+	interval := time.Duration(cfg.OneBotPushSecs) * time.Second
+
+	startLoop(ctx, wg, interval, func() {
+		// Failures are logged by the service.
+		oneBotSvc.PushNotifications()
+	})
+}
+
 func initAppState(cfg config.AppCfg, ex repo.Exct) state.AppState {
 	// Create JWT codec.
 	jwtCodec := jwtcodec.NewJWTCodec(cfg.JWTExpSecs)
@@ -66,18 +160,48 @@ func initAppState(cfg config.AppCfg, ex repo.Exct) state.AppState {
 	comicAsgnRepo := repo.NewComicAsgnRepo(ex)
 	comicPageRepo := repo.NewComicPageRepo(ex)
 	invRepo := repo.NewInvitationRepo(ex)
+	termbaseRepo := repo.NewTermbaseRepo(ex)
+	termRepo := repo.NewTermRepo(ex)
+	tagRepo := repo.NewTagRepo(ex)
+	comicLikeRepo := repo.NewComicLikeRepo(ex)
+	sessionRepo := repo.NewSessionRepo(ex)
+	auditLogRepo := repo.NewAuditLogRepo(ex)
+	comicEventRepo := repo.NewComicEventRepo(ex)
+	comicTaskRepo := repo.NewComicTaskRepo(ex)
+	teamRepo := repo.NewTeamRepo(ex)
+	notificationRepo := repo.NewNotificationRepo(ex)
+	webhookRepo := repo.NewWebhookRepo(ex)
 
 	// Create OSS client.
 	ossClient := oss.NewR2Client()
 
 	// Create services.
-	userSvc := svc.NewUserSvc(userRepo, invRepo, jwtCodec)
-	comicSvc := svc.NewComicSvc(comicRepo, userRepo, comicAsgnRepo, comicPageRepo, comicUnitRepo, cfg.ComicExportDir, ossClient)
-	worksetSvc := svc.NewWorksetSvc(worksetRepo, userRepo)
-	comicUnitSvc := svc.NewComicUnitSvc(comicUnitRepo)
-	comicAsgnSvc := svc.NewComicAsgnSvc(comicAsgnRepo, userRepo)
-	comicPageSvc := svc.NewComicPageSvc(comicPageRepo, comicRepo, comicAsgnRepo, comicUnitRepo, ossClient)
-	invitationSvc := svc.NewInvitationSvc(invRepo, userRepo)
+	userSvc := svc.NewUserSvc(userRepo, invRepo, tagRepo, sessionRepo, auditLogRepo, notificationRepo, jwtCodec, cfg.RefreshExpSecs)
+	comicSvc := svc.NewComicSvc(comicRepo, userRepo, comicAsgnRepo, comicPageRepo, comicUnitRepo, tagRepo, comicLikeRepo, teamRepo, auditLogRepo, comicEventRepo, notificationRepo, webhookRepo, cfg.ComicExportDir, ossClient)
+	worksetSvc := svc.NewWorksetSvc(worksetRepo, userRepo, auditLogRepo)
+	authzSvc := svc.NewAuthzSvc(userRepo, comicRepo, comicAsgnRepo, comicPageRepo, termbaseRepo)
+	collabSvc := svc.NewCollabSvc(comicPageRepo, userRepo)
+	comicUnitSvc := svc.NewComicUnitSvc(comicUnitRepo, comicPageRepo, termRepo, notificationRepo, authzSvc, collabSvc)
+	comicAsgnSvc := svc.NewComicAsgnSvc(comicAsgnRepo, userRepo, comicPageRepo, auditLogRepo, comicEventRepo, notificationRepo, webhookRepo)
+	comicPageSvc := svc.NewComicPageSvc(comicPageRepo, comicRepo, comicAsgnRepo, comicUnitRepo, auditLogRepo, comicEventRepo, webhookRepo, ossClient)
+	invitationSvc := svc.NewInvitationSvc(invRepo, userRepo, auditLogRepo, authzSvc, cfg.InvExpSecs)
+	termbaseSvc := svc.NewTermbaseSvc(termbaseRepo, termRepo, userRepo, comicRepo)
+	tagSvc := svc.NewTagSvc(tagRepo, userRepo, comicRepo)
+	sessionSvc := svc.NewSessionSvc(sessionRepo, userRepo, jwtCodec, cfg.RefreshExpSecs)
+	auditSvc := svc.NewAuditSvc(auditLogRepo, userRepo)
+	workflowSvc := svc.NewWorkflowSvc(comicRepo, comicPageRepo, comicUnitRepo, comicAsgnRepo, auditLogRepo, comicEventRepo, notificationRepo, webhookRepo, authzSvc)
+	comicEventSvc := svc.NewComicEventSvc(comicEventRepo, comicRepo)
+	comicTaskSvc := svc.NewComicTaskSvc(comicTaskRepo, comicRepo, comicAsgnRepo, userRepo, auditLogRepo, comicEventRepo, webhookRepo, authzSvc, cfg.TaskClaimLimit)
+	notificationSvc := svc.NewNotificationSvc(notificationRepo)
+	teamSvc := svc.NewTeamSvc(teamRepo, comicRepo, comicAsgnRepo, userRepo, auditLogRepo, comicEventRepo, notificationRepo, webhookRepo)
+	webhookSvc := svc.NewWebhookSvc(webhookRepo, auditLogRepo, time.Duration(cfg.WebhookTimeoutSecs)*time.Second, cfg.WebhookMaxAttempts)
+
+	// The QQ bot is optional.
+	var oneBotClient onebot.Client
+	if cfg.OneBotAPIURL != "" {
+		oneBotClient = onebot.NewHTTPClient(cfg.OneBotAPIURL, 10*time.Second)
+	}
+	oneBotSvc := svc.NewOneBotSvc(notificationRepo, userRepo, comicSvc, comicAsgnSvc, workflowSvc, comicTaskSvc, oneBotClient, cfg.OneBotGroupIDs)
 
 	return state.NewAppState(
 		cfg,
@@ -89,6 +213,19 @@ func initAppState(cfg config.AppCfg, ex repo.Exct) state.AppState {
 		comicAsgnSvc,
 		comicPageSvc,
 		invitationSvc,
+		termbaseSvc,
+		tagSvc,
+		sessionSvc,
+		authzSvc,
+		auditSvc,
+		workflowSvc,
+		comicEventSvc,
+		comicTaskSvc,
+		teamSvc,
+		notificationSvc,
+		webhookSvc,
+		oneBotSvc,
+		collabSvc,
 		ossClient,
 	)
 }
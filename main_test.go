@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunEveryStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runs := 0
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		runEvery(ctx, time.Millisecond, func() {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runEvery did not return after cancel")
+	}
+
+	if runs != 3 {
+		t.Fatalf("got %d runs, want 3", runs)
+	}
+}
+
+func TestRunEveryRunsRightAway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runs := 0
+	runEvery(ctx, time.Hour, func() { runs++ })
+
+	if runs != 1 {
+		t.Fatalf("got %d runs, want 1", runs)
+	}
+}
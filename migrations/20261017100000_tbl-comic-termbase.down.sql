@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "comic_termbase_tbl";
\ No newline at end of file
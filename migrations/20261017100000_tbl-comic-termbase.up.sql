@@ -0,0 +1,10 @@
+CREATE TABLE "comic_termbase_tbl" (
+    "comic_id" TEXT NOT NULL REFERENCES "comic_tbl"("id") ON DELETE CASCADE,
+    "termbase_id" TEXT NOT NULL REFERENCES "termbase_tbl"("id") ON DELETE CASCADE,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY ("comic_id", "termbase_id")
+);
+
+CREATE INDEX idx_comic_termbase_termbase_id ON "comic_termbase_tbl" ("termbase_id");
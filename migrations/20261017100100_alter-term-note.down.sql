@@ -0,0 +1 @@
+ALTER TABLE "term_tbl" DROP COLUMN IF EXISTS "note";
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "term_tbl" ADD COLUMN "note" TEXT;
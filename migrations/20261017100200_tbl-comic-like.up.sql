@@ -0,0 +1,10 @@
+CREATE TABLE "comic_like_tbl" (
+    "user_id" TEXT NOT NULL REFERENCES "user_tbl"("id") ON DELETE CASCADE,
+    "comic_id" TEXT NOT NULL REFERENCES "comic_tbl"("id") ON DELETE CASCADE,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    PRIMARY KEY ("user_id", "comic_id")
+);
+
+CREATE INDEX idx_comic_like_comic_id ON "comic_like_tbl" ("comic_id");
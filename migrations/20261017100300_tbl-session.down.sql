@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "session_tbl";
\ No newline at end of file
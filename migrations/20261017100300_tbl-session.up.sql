@@ -0,0 +1,21 @@
+CREATE TABLE "session_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+
+    "user_id" TEXT NOT NULL REFERENCES "user_tbl"("id") ON DELETE CASCADE,
+
+    -- SHA-256 hex digests; the tokens themselves are never stored.
+    "refresh_token_hash" TEXT NOT NULL UNIQUE,
+    "prev_refresh_token_hash" TEXT,
+
+    "device" TEXT NOT NULL DEFAULT '',
+    "ip" TEXT NOT NULL DEFAULT '',
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "last_used_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "revoked_at" TIMESTAMPTZ
+);
+
+CREATE INDEX idx_session_user_id_active ON "session_tbl" ("user_id") WHERE "revoked_at" IS NULL;
+
+CREATE INDEX idx_session_prev_refresh_token_hash ON "session_tbl" ("prev_refresh_token_hash") WHERE "prev_refresh_token_hash" IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "audit_log_tbl";
\ No newline at end of file
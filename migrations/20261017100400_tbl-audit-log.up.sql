@@ -0,0 +1,25 @@
+CREATE TABLE "audit_log_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+
+    -- No foreign keys, so that entries outlive the actor and the entity.
+    "actor_id" TEXT NOT NULL,
+
+    "action" TEXT NOT NULL,
+    "entity_type" TEXT NOT NULL,
+    "entity_id" TEXT NOT NULL,
+
+    -- Snapshots of the entity, NULL before creations and after deletions.
+    "before" JSONB,
+    "after" JSONB,
+
+    "ip" TEXT NOT NULL DEFAULT '',
+    "user_agent" TEXT NOT NULL DEFAULT '',
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON "audit_log_tbl" ("created_at");
+
+CREATE INDEX idx_audit_log_actor_id ON "audit_log_tbl" ("actor_id", "created_at");
+
+CREATE INDEX idx_audit_log_entity ON "audit_log_tbl" ("entity_type", "entity_id", "created_at");
@@ -0,0 +1,12 @@
+-- Codes cannot be recovered from their hashes; pending invitations must be recreated.
+ALTER TABLE "invitation_tbl"
+    ADD COLUMN "invitation_code" VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE "invitation_tbl" SET "pending" = FALSE WHERE "pending" = TRUE;
+
+ALTER TABLE "invitation_tbl"
+    DROP COLUMN "consumed_by",
+    DROP COLUMN "consumed_at",
+    DROP COLUMN "revoked_at",
+    DROP COLUMN "expires_at",
+    DROP COLUMN "invitation_code_hash";
\ No newline at end of file
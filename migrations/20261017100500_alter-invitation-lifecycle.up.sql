@@ -0,0 +1,17 @@
+-- Codes are stored as SHA-256 hex digests from now on.
+ALTER TABLE "invitation_tbl"
+    ADD COLUMN "invitation_code_hash" TEXT,
+    ADD COLUMN "expires_at" TIMESTAMPTZ,
+    ADD COLUMN "revoked_at" TIMESTAMPTZ,
+    ADD COLUMN "consumed_at" TIMESTAMPTZ,
+    ADD COLUMN "consumed_by" TEXT REFERENCES "user_tbl"("id") ON DELETE SET NULL;
+
+-- Outstanding codes keep working for a week; admins can regenerate them after.
+UPDATE "invitation_tbl"
+SET "invitation_code_hash" = encode(sha256(convert_to(upper("invitation_code"), 'UTF8')), 'hex'),
+    "expires_at" = NOW() + INTERVAL '7 days';
+
+ALTER TABLE "invitation_tbl"
+    ALTER COLUMN "invitation_code_hash" SET NOT NULL,
+    ALTER COLUMN "expires_at" SET NOT NULL,
+    DROP COLUMN "invitation_code";
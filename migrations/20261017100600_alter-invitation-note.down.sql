@@ -0,0 +1 @@
+ALTER TABLE "invitation_tbl" DROP COLUMN IF EXISTS "note";
\ No newline at end of file
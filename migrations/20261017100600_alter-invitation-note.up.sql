@@ -0,0 +1 @@
+ALTER TABLE "invitation_tbl" ADD COLUMN "note" TEXT;
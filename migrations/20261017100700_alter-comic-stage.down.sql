@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_comic_stage;
+ALTER TABLE "comic_tbl" DROP COLUMN IF EXISTS "stage";
\ No newline at end of file
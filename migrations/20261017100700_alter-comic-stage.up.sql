@@ -0,0 +1,18 @@
+-- The workflow stage, kept in sync with the *_started_at/*_completed_at columns.
+ALTER TABLE "comic_tbl"
+    ADD COLUMN "stage" TEXT NOT NULL DEFAULT 'pending'
+    CHECK ("stage" IN ('pending', 'translating', 'proofreading', 'typesetting', 'reviewing', 'uploading', 'completed'));
+
+-- Derived from the furthest timestamp set.
+UPDATE "comic_tbl"
+SET "stage" = CASE
+    WHEN "uploading_completed_at" IS NOT NULL THEN 'completed'
+    WHEN "reviewing_completed_at" IS NOT NULL THEN 'uploading'
+    WHEN "typesetting_completed_at" IS NOT NULL THEN 'reviewing'
+    WHEN "typesetting_started_at" IS NOT NULL OR "proofreading_completed_at" IS NOT NULL THEN 'typesetting'
+    WHEN "proofreading_started_at" IS NOT NULL OR "translating_completed_at" IS NOT NULL THEN 'proofreading'
+    WHEN "translating_started_at" IS NOT NULL THEN 'translating'
+    ELSE 'pending'
+END;
+
+CREATE INDEX idx_comic_stage ON "comic_tbl" ("stage");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "comic_event_tbl";
\ No newline at end of file
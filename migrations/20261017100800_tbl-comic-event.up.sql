@@ -0,0 +1,14 @@
+CREATE TABLE "comic_event_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "comic_id" TEXT NOT NULL REFERENCES "comic_tbl"("id") ON DELETE CASCADE,
+
+    -- No foreign key, so that credits outlive the actor.
+    "actor_id" TEXT NOT NULL,
+
+    "kind" TEXT NOT NULL,
+    "detail" JSONB,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_comic_event_comic_id ON "comic_event_tbl" ("comic_id", "created_at");
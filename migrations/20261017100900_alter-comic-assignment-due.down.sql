@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_comic_assignment_stalled_at;
+
+ALTER TABLE "comic_assignment_tbl"
+    DROP COLUMN IF EXISTS "translator_due_at",
+    DROP COLUMN IF EXISTS "proofreader_due_at",
+    DROP COLUMN IF EXISTS "typesetter_due_at",
+    DROP COLUMN IF EXISTS "redrawer_due_at",
+    DROP COLUMN IF EXISTS "reviewer_due_at",
+    DROP COLUMN IF EXISTS "stalled_at";
\ No newline at end of file
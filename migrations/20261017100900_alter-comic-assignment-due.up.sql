@@ -0,0 +1,12 @@
+-- Due dates per role, only meaningful while the role is assigned.
+ALTER TABLE "comic_assignment_tbl"
+    ADD COLUMN "translator_due_at" TIMESTAMPTZ,
+    ADD COLUMN "proofreader_due_at" TIMESTAMPTZ,
+    ADD COLUMN "typesetter_due_at" TIMESTAMPTZ,
+    ADD COLUMN "redrawer_due_at" TIMESTAMPTZ,
+    ADD COLUMN "reviewer_due_at" TIMESTAMPTZ,
+    -- Set by the stall checker, cleared once work resumes.
+    ADD COLUMN "stalled_at" TIMESTAMPTZ;
+
+CREATE INDEX idx_comic_assignment_stalled_at ON "comic_assignment_tbl" ("stalled_at")
+    WHERE "stalled_at" IS NOT NULL;
@@ -0,0 +1,25 @@
+-- Role slots of comics open for users to claim.
+CREATE TABLE "comic_task_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "comic_id" TEXT NOT NULL REFERENCES "comic_tbl"("id") ON DELETE CASCADE,
+
+    "role" TEXT NOT NULL
+    CHECK ("role" IN ('translator', 'proofreader', 'typesetter', 'redrawer', 'reviewer')),
+    "note" TEXT,
+    -- Becomes the due date of the role once claimed.
+    "due_at" TIMESTAMPTZ,
+
+    "creator_id" TEXT REFERENCES "user_tbl"("id") ON DELETE SET NULL,
+
+    "claimed_by" TEXT REFERENCES "user_tbl"("id") ON DELETE SET NULL,
+    "claimed_at" TIMESTAMPTZ,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "updated_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+-- One unclaimed slot per role and comic.
+CREATE UNIQUE INDEX idx_comic_task_open ON "comic_task_tbl" ("comic_id", "role")
+    WHERE "claimed_by" IS NULL;
+
+CREATE INDEX idx_comic_task_claimed_by ON "comic_task_tbl" ("claimed_by");
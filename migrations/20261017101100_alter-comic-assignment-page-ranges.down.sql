@@ -0,0 +1,2 @@
+ALTER TABLE "comic_assignment_tbl"
+    DROP COLUMN IF EXISTS "page_ranges";
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Page index ranges a translator works on, as [{"from": 0, "to": 9}, ...] with both ends inclusive.
+-- NULL means the whole comic.
+ALTER TABLE "comic_assignment_tbl"
+    ADD COLUMN "page_ranges" JSONB;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "team_member_tbl";
+DROP TABLE IF EXISTS "team_tbl";
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- Standing teams, applied to comics as a set of assignments.
+CREATE TABLE "team_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+
+    "name" TEXT UNIQUE NOT NULL,
+    "description" TEXT,
+
+    "creator_id" TEXT REFERENCES "user_tbl"("id") ON DELETE SET NULL,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "updated_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE "team_member_tbl" (
+    "team_id" TEXT NOT NULL REFERENCES "team_tbl"("id") ON DELETE CASCADE,
+    "user_id" TEXT NOT NULL REFERENCES "user_tbl"("id") ON DELETE CASCADE,
+
+    "is_translator" BOOLEAN DEFAULT FALSE NOT NULL,
+    "is_proofreader" BOOLEAN DEFAULT FALSE NOT NULL,
+    "is_typesetter" BOOLEAN DEFAULT FALSE NOT NULL,
+    "is_redrawer" BOOLEAN DEFAULT FALSE NOT NULL,
+    "is_reviewer" BOOLEAN DEFAULT FALSE NOT NULL,
+
+    PRIMARY KEY ("team_id", "user_id"),
+    CHECK ("is_translator" OR "is_proofreader" OR "is_typesetter" OR "is_redrawer" OR "is_reviewer")
+);
+
+CREATE INDEX idx_team_member_user_id ON "team_member_tbl" ("user_id");
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "notification_tbl";
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Inbox of each user.
+CREATE TABLE "notification_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "user_id" TEXT NOT NULL REFERENCES "user_tbl"("id") ON DELETE CASCADE,
+
+    "kind" TEXT NOT NULL,
+    "comic_id" TEXT REFERENCES "comic_tbl"("id") ON DELETE CASCADE,
+    -- No foreign key, as in comic events.
+    "actor_id" TEXT,
+    "detail" JSONB,
+
+    "read_at" TIMESTAMPTZ,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_notification_user_id ON "notification_tbl" ("user_id", "created_at");
+
+CREATE INDEX idx_notification_unread ON "notification_tbl" ("user_id")
+    WHERE "read_at" IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "webhook_delivery_tbl";
+DROP TABLE IF EXISTS "webhook_tbl";
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- Endpoints the server posts events to.
+CREATE TABLE "webhook_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "url" TEXT NOT NULL,
+    -- Key of the HMAC signature, needed in plain to sign.
+    "secret" TEXT NOT NULL,
+    -- Events posted, all of them if empty.
+    "events" JSONB DEFAULT '[]' NOT NULL,
+    "description" TEXT,
+    "active" BOOLEAN DEFAULT TRUE NOT NULL,
+
+    "creator_id" TEXT REFERENCES "user_tbl"("id") ON DELETE SET NULL,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "updated_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+-- Retry queue and delivery log of webhooks in one.
+CREATE TABLE "webhook_delivery_tbl" (
+    "id" TEXT PRIMARY KEY NOT NULL,
+    "webhook_id" TEXT NOT NULL REFERENCES "webhook_tbl"("id") ON DELETE CASCADE,
+
+    "event" TEXT NOT NULL,
+    "payload" JSONB NOT NULL,
+
+    "status" TEXT DEFAULT 'pending' NOT NULL
+    CHECK ("status" IN ('pending', 'succeeded', 'failed')),
+    "attempts" INTEGER DEFAULT 0 NOT NULL,
+    -- Pushed forward while an attempt is under way, so that no other worker takes it.
+    "next_attempt_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+
+    "last_status_code" INTEGER,
+    "last_error" TEXT,
+    "delivered_at" TIMESTAMPTZ,
+
+    "created_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL,
+    "updated_at" TIMESTAMPTZ DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_webhook_delivery_due ON "webhook_delivery_tbl" ("next_attempt_at")
+    WHERE "status" = 'pending';
+
+CREATE INDEX idx_webhook_delivery_webhook_id ON "webhook_delivery_tbl" ("webhook_id", "created_at");
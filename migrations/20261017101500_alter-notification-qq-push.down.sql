@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notification_unpushed;
+
+ALTER TABLE "notification_tbl"
+    DROP COLUMN IF EXISTS "qq_pushed_at";
\ No newline at end of file
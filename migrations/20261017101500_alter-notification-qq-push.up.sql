@@ -0,0 +1,10 @@
+-- Set once a notification is taken up for pushing to QQ through OneBot.
+ALTER TABLE "notification_tbl"
+    ADD COLUMN "qq_pushed_at" TIMESTAMPTZ;
+
+-- Notifications sent before pushing existed are not pushed.
+UPDATE "notification_tbl" SET "qq_pushed_at" = "created_at";
+
+-- Only the kinds pushed are waited on.
+CREATE INDEX idx_notification_unpushed ON "notification_tbl" ("created_at")
+    WHERE "qq_pushed_at" IS NULL AND "kind" IN ('assigned', 'stage_change');
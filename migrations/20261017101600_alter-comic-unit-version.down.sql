@@ -0,0 +1 @@
+ALTER TABLE "comic_unit_tbl" DROP COLUMN IF EXISTS "version";
\ No newline at end of file
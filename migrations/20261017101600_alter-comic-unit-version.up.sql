@@ -0,0 +1 @@
+ALTER TABLE "comic_unit_tbl" ADD COLUMN "version" BIGINT DEFAULT 1 NOT NULL;
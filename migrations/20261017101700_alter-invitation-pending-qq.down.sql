@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uidx_invitation_pending_invitee_qq;
\ No newline at end of file
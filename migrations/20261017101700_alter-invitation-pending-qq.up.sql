@@ -0,0 +1,13 @@
+-- Only the newest usable invitation of a QQ is kept, the others are superseded.
+UPDATE "invitation_tbl" AS i
+SET "revoked_at" = NOW(), "updated_at" = NOW()
+WHERE i."pending" = TRUE AND i."revoked_at" IS NULL
+  AND EXISTS (
+    SELECT 1 FROM "invitation_tbl" AS n
+    WHERE n."invitee_qq" = i."invitee_qq"
+      AND n."pending" = TRUE AND n."revoked_at" IS NULL
+      AND (n."created_at", n."id") > (i."created_at", i."id")
+  );
+
+CREATE UNIQUE INDEX uidx_invitation_pending_invitee_qq ON "invitation_tbl" ("invitee_qq")
+    WHERE "pending" = TRUE AND "revoked_at" IS NULL;
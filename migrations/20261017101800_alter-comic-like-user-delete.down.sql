@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS "trg_user_uncount_likes" ON "user_tbl";
+DROP FUNCTION IF EXISTS "uncount_user_likes"();
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Likes removed by deleting a user bypass the application, so the counts are kept here.
+CREATE FUNCTION "uncount_user_likes"() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE "comic_tbl"
+    SET "likes_count" = "likes_count" - 1
+    WHERE "id" IN (SELECT "comic_id" FROM "comic_like_tbl" WHERE "user_id" = OLD."id");
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Before the likes are deleted by the cascade.
+CREATE TRIGGER "trg_user_uncount_likes"
+    BEFORE DELETE ON "user_tbl"
+    FOR EACH ROW EXECUTE FUNCTION "uncount_user_likes"();
+
+-- Repair counts drifted by users deleted so far.
+UPDATE "comic_tbl" AS c
+SET "likes_count" = (SELECT COUNT(*) FROM "comic_like_tbl" AS l WHERE l."comic_id" = c."id")
+WHERE "likes_count" <> (SELECT COUNT(*) FROM "comic_like_tbl" AS l WHERE l."comic_id" = c."id");
@@ -12,11 +12,20 @@ import (
 	"testing"
 	"time"
 
+	"poprako-main-server/internal/jwtcodec"
+	"poprako-main-server/internal/repo"
+	"poprako-main-server/internal/seeder"
+
 	"github.com/joho/godotenv"
 )
 
 const (
 	baseURL = "http://localhost:8080/api/v1"
+
+	// Admin user inserted by the migrations, holding all roles
+	adminUserID = "019bbf6f-fa6b-7119-b1cd-c961a808c864"
+
+	tokenExpSecs = 3600
 )
 
 var (
@@ -42,16 +51,18 @@ var (
 
 // TestMain controls the execution flow and starts/stops the server
 func TestMain(m *testing.M) {
-	// Load .env file to get MOCK_AUTH_TOKEN
+	// Load .env file to get DATABASE_URL and JWT_SECRET_KEY
 	if err := godotenv.Load("../.env"); err != nil {
 		fmt.Printf("Warning: .env file not found: %v\n", err)
 	}
 
-	authToken = os.Getenv("MOCK_AUTH_TOKEN")
-	if authToken == "" {
-		fmt.Println("Error: MOCK_AUTH_TOKEN not found in .env")
+	// Requests must carry a token of a live session, so start one for the admin user
+	token, err := seeder.SeedSession(repo.InitDB(), adminUserID, jwtcodec.NewJWTCodec(tokenExpSecs))
+	if err != nil {
+		fmt.Printf("Failed to seed auth session: %v\n", err)
 		os.Exit(1)
 	}
+	authToken = token
 
 	// // Start the server
 	// if err := startServer(); err != nil {